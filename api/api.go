@@ -0,0 +1,8 @@
+// Package api menyimpan dokumen OpenAPI untuk HTTP API. Dokumen ini ditulis
+// manual dan disajikan apa adanya di /openapi.json.
+package api
+
+import _ "embed"
+
+//go:embed openapi.json
+var Spec []byte
@@ -0,0 +1,57 @@
+package api_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/danisasmita/customer-search/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecVersion(t *testing.T) {
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(api.Spec, &spec))
+
+	assert.Equal(t, "3.1.0", spec["openapi"])
+	assert.Contains(t, spec, "paths")
+}
+
+// TestSpecReferencesResolve memastikan setiap $ref menunjuk ke komponen
+// yang ada.
+func TestSpecReferencesResolve(t *testing.T) {
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(api.Spec, &spec))
+
+	for _, ref := range collectRefs(spec) {
+		require.True(t, strings.HasPrefix(ref, "#/"), ref)
+
+		var node interface{} = spec
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			object, ok := node.(map[string]interface{})
+			require.True(t, ok, ref)
+			node, ok = object[part]
+			require.True(t, ok, "unresolved reference %s", ref)
+		}
+	}
+}
+
+func collectRefs(node interface{}) []string {
+	var refs []string
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = append(refs, collectRefs(child)...)
+		}
+	case []interface{}:
+		for _, child := range value {
+			refs = append(refs, collectRefs(child)...)
+		}
+	}
+	return refs
+}
@@ -1,19 +1,39 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/danisasmita/customer-search/internal/config"
 	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/danisasmita/customer-search/internal/model"
 	"github.com/danisasmita/customer-search/internal/repository"
 	"github.com/danisasmita/customer-search/internal/service"
 	"github.com/danisasmita/customer-search/pkg/database"
-	"github.com/danisasmita/customer-search/pkg/middleware"
-	"github.com/gin-contrib/cors"
+	"github.com/danisasmita/customer-search/pkg/health"
+	"github.com/danisasmita/customer-search/pkg/i18n"
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/danisasmita/customer-search/pkg/metrics"
+	"github.com/danisasmita/customer-search/pkg/notifier"
+	"github.com/danisasmita/customer-search/pkg/oidc"
+	"github.com/danisasmita/customer-search/pkg/server"
+	"github.com/danisasmita/customer-search/pkg/tenant"
+	"github.com/danisasmita/customer-search/pkg/tracing"
+	"github.com/danisasmita/customer-search/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -22,97 +42,475 @@ import (
 func main() {
 	migrate := flag.Bool("migrate", false, "Run database migrations")
 	seed := flag.Bool("seed", false, "Seed database with initial data")
+	auditVerify := flag.Bool("audit-verify", false, "Verify the audit log hash chain and exit")
+	auditList := flag.Bool("audit-list", false, "Print audit log entries as JSON and exit")
+	auditUser := flag.Uint("audit-user", 0, "Filter -audit-list by user ID")
+	auditLimit := flag.Int("audit-limit", 100, "Maximum number of entries printed by -audit-list")
+	createAdmin := flag.Bool("create-admin", false, "Create an admin, or promote an existing user, and exit; password from ADMIN_PASSWORD or stdin")
+	adminUsername := flag.String("admin-username", "admin", "Username for -create-admin")
+	adminTenant := flag.String("admin-tenant", "", "Branch for -create-admin")
+	backfillTenant := flag.String("backfill-tenant", "", "Assign customers, their products, and users without a branch to this branch and exit")
+	healthcheck := flag.Bool("healthcheck", false, "Call GET /readyz on SERVER_ADDRESS, over HTTPS when TLS is configured, and exit non-zero if it fails")
 	flag.Parse()
 
-	// Ambil environment variable
-	cfg := config.Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBUser:     getEnv("DB_USER", "user"),
-		DBPassword: getEnv("DB_PASSWORD", "password"),
-		DBName:     getEnv("DB_NAME", "customer_search"),
-		DBPort:     getEnvAsInt("DB_PORT", 5432),
+	// Konfigurasi dari environment variable dan .env jika ada
+	loaded, err := config.LoadConfig()
+	if err != nil {
+		fatal("failed to load configuration", "error", err)
 	}
+	cfg := *loaded
 
-	// Print konfigurasi untuk debugging
-	fmt.Println("Database Configuration:")
-	fmt.Printf("Host: %s\n", cfg.DBHost)
-	fmt.Printf("User: %s\n", cfg.DBUser)
-	fmt.Printf("Database: %s\n", cfg.DBName)
-	fmt.Printf("Port: %d\n", cfg.DBPort)
+	// Semua log, termasuk dari package log dan gin, ditulis lewat logger
+	// ini sehingga formatnya seragam dan data sensitif disembunyikan.
+	logger, err := logging.New(os.Stdout, logging.Config{Level: cfg.LogLevel, Format: cfg.LogFormat})
+	if err != nil {
+		fatal("failed to configure logging", "error", err)
+	}
+	slog.SetDefault(logger)
+	gin.DebugPrintFunc = func(format string, values ...any) {
+		logger.Debug(strings.TrimSpace(fmt.Sprintf(format, values...)))
+	}
+	gin.DebugPrintRouteFunc = func(method, path, handlerName string, _ int) {
+		logger.Debug("route registered", "method", method, "path", path, "handler", handlerName)
+	}
+
+	if *healthcheck {
+		runHealthcheck(cfg)
+		return
+	}
+
+	// TRACING_EXPORTER=none tetap meneruskan traceparent, hanya span tidak
+	// diekspor.
+	tracerProvider, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     cfg.TracingExporter,
+		ServiceName:  cfg.TracingServiceName,
+		File:         cfg.TracingFile,
+		OTLPEndpoint: cfg.OTLPEndpoint,
+		OTLPHeaders:  cfg.OTLPHeaders,
+	})
+	if err != nil {
+		fatal("failed to configure tracing", "error", err)
+	}
+
+	// ctx selesai saat SIGINT atau SIGTERM diterima, misalnya saat deploy.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// User dan password database tidak dicatat.
+	logger.Info("connecting to database", "host", cfg.DBHost, "port", cfg.DBPort, "database", cfg.DBName)
 
 	// Buat DSN dari environment variables
 	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
-		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort,
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort, cfg.DBSSLMode,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// Database bisa baru menyala bersamaan dengan server, misalnya di
+	// docker-compose, jadi koneksi pertama dicoba ulang dengan backoff.
+	db, err := database.OpenWithRetry(ctx, database.RetryPolicy{
+		Attempts:    cfg.DBConnectAttempts,
+		BackoffBase: cfg.DBConnectBackoffBase,
+		BackoffMax:  cfg.DBConnectBackoffMax,
+	}, func() (*gorm.DB, error) {
+		return gorm.Open(postgres.Open(dsn), &gorm.Config{
+			Logger: logging.NewGORMLogger(logger, cfg.DBSlowQueryThreshold),
+			// Pelanggaran unique index menjadi gorm.ErrDuplicatedKey
+			TranslateError: true,
+		})
+	})
 	if err != nil {
-		log.Fatalf("failed to connect database: %v", err)
+		fatal("failed to connect database", "error", err)
 	}
 
+	// Semua query customer dan produknya dibatasi ke cabang pemanggil
+	if err := tenant.Register(db); err != nil {
+		fatal("failed to register tenant scope", "error", err)
+	}
+
+	if err := db.Use(metrics.GORMPlugin{}); err != nil {
+		fatal("failed to register query metrics", "error", err)
+	}
+	if err := db.Use(tracing.GORMPlugin()); err != nil {
+		fatal("failed to register query tracing", "error", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		fatal("failed to get database pool", "error", err)
+	}
+	metrics.RegisterDBStats(metrics.Default, sqlDB, "customer_search")
+
 	if *migrate {
 		err = database.AutoMigrate(db)
 		if err != nil {
-			log.Fatalf("failed to migrate database: %v", err)
+			fatal("failed to migrate database", "error", err)
 		}
-		log.Println("Migration completed successfully!")
+		logger.Info("migration completed")
 	}
 
 	if *seed {
 		err = database.SeedData(db)
 		if err != nil {
-			log.Fatalf("failed to seed database: %v", err)
+			fatal("failed to seed database", "error", err)
 		}
-		log.Println("Seeding completed successfully!")
+		logger.Info("seeding completed")
+	}
+
+	if *backfillTenant != "" {
+		updated, err := database.BackfillTenant(db, *backfillTenant)
+		if err != nil {
+			fatal("failed to backfill tenant", "error", err)
+		}
+		logger.Info("tenant backfill completed", "tenant_id", *backfillTenant, "updated", updated)
+		return
+	}
+
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepo)
+	auditHandler := handler.NewAuditHandler(auditService)
+
+	if *auditVerify || *auditList {
+		runAuditCommand(auditService, *auditVerify, model.AuditFilter{UserID: *auditUser, Limit: *auditLimit})
+		return
 	}
 
 	customerRepo := repository.NewCustomerRepository(db)
 	customerService := service.NewCustomerService(customerRepo)
-	customerHandler := handler.NewCustomerHandler(customerService)
+	customerHandler := handler.NewCustomerHandler(customerService, auditService)
+
+	accessTokenTTL, err := config.ParseDuration(cfg.JWTExpiration, 15*time.Minute)
+	if err != nil {
+		fatal("failed to parse JWT_EXPIRATION", "error", err)
+	}
+	utils.SetTokenTTL(accessTokenTTL)
+
+	err = utils.ConfigureSigning(utils.SigningConfig{
+		Algorithm:        cfg.JWTAlgorithm,
+		KeyID:            cfg.JWTKeyID,
+		Secret:           cfg.JWTSecret,
+		PrivateKeyFile:   cfg.JWTPrivateKeyFile,
+		VerificationKeys: cfg.JWTVerificationKeys,
+	})
+	if err != nil {
+		fatal("failed to configure JWT signing", "error", err)
+	}
+
+	passwordHasher, err := utils.NewPasswordHasher(cfg.PasswordHashAlgorithm, utils.Argon2Params{
+		Memory:      uint32(cfg.Argon2Memory),
+		Iterations:  uint32(cfg.Argon2Iterations),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+	}, cfg.BcryptCost)
+	if err != nil {
+		fatal("failed to configure password hashing", "error", err)
+	}
+	utils.SetPasswordHasher(passwordHasher)
+
+	revocationRepo := repository.NewTokenRevocationRepository(db)
+	// Pencabutan user harus bertahan selama token terpanjang, yaitu access
+	// token biasa atau token impersonation.
+	revocationStore := service.NewRevocationStore(revocationRepo, max(accessTokenTTL, cfg.ImpersonationTTL), logger)
+	if err := revocationStore.Sync(); err != nil {
+		fatal("failed to load token revocations", "error", err)
+	}
+	var jobs sync.WaitGroup
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		revocationStore.Run(ctx, time.Minute)
+	}()
 
 	userRepo := repository.NewUserRepository(db)
-	authService := service.NewAuthService(userRepo)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	tokenService := service.NewTokenService(refreshTokenRepo, userRepo, revocationStore, cfg.RefreshTokenTTL)
+
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	loginThrottle := service.NewLoginThrottle(loginAttemptRepo, auditService, service.LoginThrottlePolicy{
+		MaxFailures:     cfg.LoginMaxFailures,
+		IPMaxFailures:   cfg.LoginIPMaxFailures,
+		LockoutDuration: cfg.LoginLockoutDuration,
+		BackoffBase:     cfg.LoginBackoffBase,
+		BackoffMax:      cfg.LoginBackoffMax,
+	})
+
+	recoveryCodeRepo := repository.NewMFARecoveryCodeRepository(db)
+	mfaService := service.NewMFAService(userRepo, recoveryCodeRepo, tokenService, auditService, service.MFAPolicy{
+		Issuer:       cfg.MFAIssuer,
+		ChallengeTTL: cfg.MFAChallengeTTL,
+	})
+	mfaHandler := handler.NewMFAHandler(mfaService)
+
+	passwordPolicy := service.PasswordPolicy{
+		MinLength:      cfg.PasswordMinLength,
+		RequireUpper:   cfg.PasswordRequireUpper,
+		RequireLower:   cfg.PasswordRequireLower,
+		RequireDigit:   cfg.PasswordRequireDigit,
+		RequireSymbol:  cfg.PasswordRequireSymbol,
+		RejectUsername: cfg.PasswordRejectUsername,
+	}
+	// PASSWORD_COMMON_LIST_FILE kosong mematikan pengecekan password umum.
+	if cfg.PasswordCommonListFile != "" {
+		passwordPolicy.Common, err = service.LoadCommonPasswords(cfg.PasswordCommonListFile, cfg.PasswordCommonListLimit)
+		if err != nil {
+			fatal("failed to load common password list", "error", err)
+		}
+	}
+
+	authService := service.NewAuthService(userRepo, tokenService, loginThrottle, mfaService, passwordPolicy, logger)
 	authHandler := handler.NewAuthHandler(authService)
 
-	r := gin.Default()
-	r.Use(cors.New(cors.Config{
-		AllowAllOrigins: true,
-		AllowMethods:    []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:    []string{"Origin", "Content-Type", "Authorization"},
-	}))
+	userNotifier, err := notifier.New(cfg.NotifierKind, cfg.NotifierFile, logger)
+	if err != nil {
+		fatal("failed to configure notifier", "error", err)
+	}
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	// Permintaan reset dihitung di tabel yang sama dengan login, dengan
+	// prefix sendiri, tanpa backoff.
+	resetThrottle := service.NewLoginThrottle(loginAttemptRepo, auditService, service.LoginThrottlePolicy{
+		KeyPrefix:       "reset:",
+		MaxFailures:     cfg.PasswordResetMaxRequests,
+		IPMaxFailures:   cfg.PasswordResetIPMaxRequests,
+		LockoutDuration: cfg.PasswordResetWindow,
+	})
+	passwordService := service.NewPasswordService(userRepo, passwordResetRepo, tokenService, auditService, loginThrottle, resetThrottle, userNotifier, passwordPolicy, cfg.PasswordResetTTL, &jobs, logger)
+	passwordHandler := handler.NewPasswordHandler(passwordService)
 
-	r.POST("/register", authHandler.Register)
-	r.POST("/login", authHandler.Login)
+	userService := service.NewUserService(userRepo, tokenService, auditService, passwordPolicy)
+	userHandler := handler.NewUserHandler(userService)
 
-	authorized := r.Group("/")
-	authorized.Use(middleware.JWTAuth())
-	{
-		authorized.GET("/customers", customerHandler.SearchByName)
+	if *createAdmin {
+		runCreateAdmin(userService, *adminUsername, *adminTenant)
+		return
 	}
 
-	serverAddress := getEnv("SERVER_ADDRESS", ":8080")
-	log.Printf("Starting server on %s\n", serverAddress)
-	r.Run(serverAddress)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, auditService, logger)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+
+	impersonationService := service.NewImpersonationService(userRepo, auditService, service.ImpersonationPolicy{
+		TTL:         cfg.ImpersonationTTL,
+		GlobalRoles: cfg.TenantGlobalRoles,
+	})
+	impersonationHandler := handler.NewImpersonationHandler(impersonationService)
+
+	if !i18n.Supported(cfg.DefaultLanguage) {
+		fatal("DEFAULT_LANGUAGE is not supported", "language", cfg.DefaultLanguage, "supported", i18n.Languages)
+	}
+
+	readiness := health.NewChecker(cfg.ReadinessTimeout)
+	readiness.Add("database", func(ctx context.Context) error {
+		return database.Ping(ctx, db)
+	})
+	readiness.Add("migrations", database.MigrationCheck(db))
+	readiness.Add("signing_key", func(ctx context.Context) error {
+		if !utils.SigningKeysLoaded() {
+			return errors.New("signing key not loaded")
+		}
+		return nil
+	})
+
+	rt := routes{
+		cfg:           cfg,
+		logger:        logger,
+		health:        handler.NewHealthHandler(readiness),
+		auth:          authHandler,
+		password:      passwordHandler,
+		mfa:           mfaHandler,
+		user:          userHandler,
+		apiKey:        apiKeyHandler,
+		impersonation: impersonationHandler,
+		customer:      customerHandler,
+		audit:         auditHandler,
+		users:         userRepo,
+		revocations:   revocationStore,
+		apiKeys:       apiKeyService,
+		auditLog:      auditService,
+	}
+	// Login SSO hanya aktif jika OIDC_ISSUER diisi.
+	if cfg.OIDCIssuer != "" {
+		rt.oidc = newOIDCHandler(cfg, db, userRepo, tokenService, mfaService, logger)
+	}
+
+	// /metrics dilayani di METRICS_ADDRESS yang tidak dibuka ke publik.
+	// Tanpa METRICS_ADDRESS, /metrics ikut di port API dan wajib token.
+	metricsHandler := metrics.Handler(metrics.Default, cfg.MetricsToken)
+	if cfg.MetricsAddress == "" {
+		if cfg.MetricsToken == "" {
+			fatal("METRICS_TOKEN is required when METRICS_ADDRESS is empty")
+		}
+		rt.metrics = metricsHandler
+	}
+	r := newRouter(rt)
+
+	serverConfig := server.Config{
+		Addr:              cfg.ServerAddress,
+		ReadHeaderTimeout: cfg.ServerReadHeaderTimeout,
+		ReadTimeout:       cfg.ServerReadTimeout,
+		WriteTimeout:      cfg.ServerWriteTimeout,
+		IdleTimeout:       cfg.ServerIdleTimeout,
+		ShutdownTimeout:   cfg.ShutdownTimeout,
+		TLSCertFile:       cfg.TLSCertFile,
+		TLSKeyFile:        cfg.TLSKeyFile,
+		TLSReloadInterval: cfg.TLSReloadInterval,
+	}
+
+	if cfg.MetricsAddress != "" {
+		ln, err := net.Listen("tcp", cfg.MetricsAddress)
+		if err != nil {
+			fatal("failed to listen on METRICS_ADDRESS", "error", err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", metricsHandler)
+		metricsServer := server.New(server.Config{
+			ReadHeaderTimeout: cfg.ServerReadHeaderTimeout,
+			ReadTimeout:       cfg.ServerReadTimeout,
+			WriteTimeout:      cfg.ServerWriteTimeout,
+			IdleTimeout:       cfg.ServerIdleTimeout,
+		}, mux)
+
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			if err := server.Serve(ctx, metricsServer, ln, cfg.ShutdownTimeout); err != nil {
+				logger.Error("metrics server stopped", "error", err)
+			}
+		}()
+		logger.Info("serving metrics", "address", cfg.MetricsAddress)
+	}
+
+	logger.Info("starting server", "address", serverConfig.Addr, "tls", serverConfig.TLSEnabled())
+	serveErr := server.Run(ctx, serverConfig, r)
+
+	// Job latar belakang dihentikan, pool database ditutup, dan span yang
+	// tersisa diekspor setelah semua request selesai, juga jika server
+	// gagal start.
+	stop()
+	jobs.Wait()
+	if err := database.Close(db); err != nil {
+		logger.Error("failed to close database", "error", err)
+	}
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := tracerProvider.Shutdown(flushCtx); err != nil {
+		logger.Error("failed to flush traces", "error", err)
+	}
+	cancel()
+	if serveErr != nil {
+		fatal("server stopped", "error", serveErr)
+	}
+	logger.Info("server stopped")
 }
 
-// Fungsi untuk mendapatkan environment variable dengan nilai default
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// newOIDCHandler mengambil dokumen discovery identity provider saat start
+// dan memastikan semua role di OIDC_ROLE_MAPPING valid.
+func newOIDCHandler(cfg config.Config, db *gorm.DB, userRepo repository.UserRepository, tokenService service.TokenService, mfaService service.MFAService, logger *slog.Logger) *handler.OIDCHandler {
+	for group, role := range cfg.OIDCRoleMapping {
+		if !model.ValidRole(role) {
+			fatal("OIDC_ROLE_MAPPING maps a group to an unknown role", "group", group, "role", role)
+		}
+	}
+	if cfg.OIDCDefaultRole != "" && !model.ValidRole(cfg.OIDCDefaultRole) {
+		fatal("OIDC_DEFAULT_ROLE is not a valid role", "role", cfg.OIDCDefaultRole)
 	}
-	return defaultValue
+	if cfg.OIDCDefaultTenant != "" && !model.ValidTenant(cfg.OIDCDefaultTenant) {
+		fatal("OIDC_DEFAULT_TENANT is not a valid branch code", "tenant_id", cfg.OIDCDefaultTenant)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	client, err := oidc.Discover(ctx, oidc.Config{
+		Issuer:       cfg.OIDCIssuer,
+		ClientID:     cfg.OIDCClientID,
+		ClientSecret: cfg.OIDCClientSecret,
+		RedirectURL:  cfg.OIDCRedirectURL,
+		Scopes:       cfg.OIDCScopes,
+		GroupsClaim:  cfg.OIDCGroupsClaim,
+		TenantClaim:  cfg.OIDCTenantClaim,
+	}, nil)
+	if err != nil {
+		fatal("failed to configure OIDC", "error", err)
+	}
+
+	oidcService := service.NewOIDCService(client, repository.NewOIDCRepository(db), userRepo, tokenService, mfaService, service.OIDCPolicy{
+		RoleMapping:   cfg.OIDCRoleMapping,
+		DefaultRole:   cfg.OIDCDefaultRole,
+		DefaultTenant: cfg.OIDCDefaultTenant,
+		StateTTL:      cfg.OIDCStateTTL,
+	}, logger)
+	return handler.NewOIDCHandler(oidcService, cfg.OIDCStateTTL)
+}
+
+// fatal mencatat error lalu keluar dengan status 1, pengganti log.Fatalf
+// yang menulis lewat logger terstruktur.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// runCreateAdmin menjalankan perintah -create-admin. Password dibaca dari
+// ADMIN_PASSWORD atau baris pertama stdin agar tidak muncul di daftar
+// proses atau riwayat shell.
+func runCreateAdmin(userService service.UserService, username, tenantID string) {
+	if username == "" {
+		fatal("-admin-username is required")
+	}
+	password := os.Getenv("ADMIN_PASSWORD")
+	if password == "" {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			fatal("failed to read admin password", "error", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	user, err := userService.Bootstrap(username, password, tenantID)
+	if err != nil {
+		fatal("failed to create admin", "username", username, "error", err)
+	}
+	slog.Info("admin ready", "user_id", user.ID, "username", user.Username, "tenant_id", user.TenantID)
 }
 
-// Fungsi untuk mendapatkan environment variable sebagai integer dengan nilai default
-func getEnvAsInt(key string, defaultValue int) int {
-	valueStr := getEnv(key, "")
-	if valueStr == "" {
-		return defaultValue
+// runHealthcheck menjalankan perintah -healthcheck untuk HEALTHCHECK
+// Docker. Skema dipilih dari TLS_CERT_FILE dan TLS_KEY_FILE sehingga probe
+// tetap berjalan saat server melayani HTTPS.
+func runHealthcheck(cfg config.Config) {
+	url, err := server.LocalURL(server.Config{
+		Addr:        cfg.ServerAddress,
+		TLSCertFile: cfg.TLSCertFile,
+		TLSKeyFile:  cfg.TLSKeyFile,
+	}, "/readyz")
+	if err != nil {
+		fatal("invalid SERVER_ADDRESS", "error", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ReadinessTimeout+time.Second)
+	defer cancel()
+	if err := server.Probe(ctx, url); err != nil {
+		fatal("healthcheck failed", "error", err)
+	}
+}
+
+// runAuditCommand menjalankan perintah CLI audit lalu keluar dengan status
+// non-zero jika rantai hash rusak.
+func runAuditCommand(auditService service.AuditService, verify bool, filter model.AuditFilter) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	if !verify {
+		logs, err := auditService.Query(filter)
+		if err != nil {
+			fatal("failed to query audit log", "error", err)
+		}
+		if err := encoder.Encode(logs); err != nil {
+			fatal("failed to print audit log", "error", err)
+		}
+		return
 	}
-	value, err := strconv.Atoi(valueStr)
+
+	result, err := auditService.Verify()
 	if err != nil {
-		log.Fatalf("failed to convert %s to int: %v", key, err)
+		fatal("failed to verify audit log", "error", err)
+	}
+	if err := encoder.Encode(result); err != nil {
+		fatal("failed to print audit verification", "error", err)
+	}
+	if !result.Valid {
+		os.Exit(1)
 	}
-	return value
 }
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/danisasmita/customer-search/internal/config"
+	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/middleware"
+	"github.com/danisasmita/customer-search/pkg/tracing"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// routes berisi semua dependency yang dibutuhkan newRouter. OIDC nil
+// berarti login SSO tidak aktif; metrics nil berarti /metrics dilayani di
+// port admin terpisah.
+type routes struct {
+	cfg     config.Config
+	logger  *slog.Logger
+	metrics http.Handler
+
+	health        *handler.HealthHandler
+	auth          *handler.AuthHandler
+	password      *handler.PasswordHandler
+	mfa           *handler.MFAHandler
+	user          *handler.UserHandler
+	apiKey        *handler.APIKeyHandler
+	impersonation *handler.ImpersonationHandler
+	customer      *handler.CustomerHandler
+	audit         *handler.AuditHandler
+	oidc          *handler.OIDCHandler
+
+	users       middleware.UserFinder
+	revocations middleware.RevocationChecker
+	apiKeys     middleware.APIKeyAuthenticator
+	auditLog    middleware.AuditRecorder
+}
+
+// newRouter memasang API di /v1 dan route yang bentuk responsnya berubah
+// di /v2. Route tanpa prefix versi adalah alias /v1 yang deprecated dan
+// hanya ada selama LEGACY_ROUTES_ENABLED aktif. Setiap route baru juga
+// harus ditulis di api/openapi.json; TestRoutesMatchOpenAPI gagal jika
+// keduanya berbeda.
+func newRouter(rt routes) *gin.Engine {
+	r := gin.New()
+	r.Use(middleware.RequestID())
+	r.Use(middleware.AccessLog(rt.logger))
+	r.Use(middleware.Recovery())
+	r.Use(cors.New(cors.Config{
+		AllowAllOrigins: true,
+		AllowMethods:    []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:    []string{"Origin", "Content-Type", "Authorization", middleware.APIKeyHeader, middleware.AccessReasonHeader, middleware.BreakGlassHeader, middleware.RequestIDHeader, tracing.TraceParentHeader, tracing.TraceStateHeader},
+		ExposeHeaders:   []string{middleware.RequestIDHeader, "Retry-After", "Deprecation", "Sunset", "Link"},
+	}))
+	r.Use(middleware.Tracing(rt.cfg.TracingServiceName)...)
+	r.Use(middleware.Metrics())
+	r.Use(middleware.Language(rt.cfg.DefaultLanguage))
+
+	// Route di luar versi API.
+	r.GET("/healthz", handler.Live)
+	r.GET("/readyz", rt.health.Ready)
+	r.GET("/version", handler.Version)
+	r.GET("/openapi.json", handler.OpenAPI)
+	r.GET("/docs", handler.Docs)
+	r.GET("/.well-known/jwks.json", handler.JWKS)
+	if rt.metrics != nil {
+		r.GET("/metrics", gin.WrapH(rt.metrics))
+	}
+
+	rt.registerV1(r.Group("/v1"))
+	rt.registerV2(r.Group("/v2"))
+
+	if rt.cfg.LegacyRoutesEnabled {
+		rt.registerV1(r.Group("/", middleware.Deprecated(middleware.DeprecationPolicy{
+			Since:     rt.cfg.LegacyRoutesDeprecatedAt,
+			Sunset:    rt.cfg.LegacyRoutesSunset,
+			Successor: "/v1",
+		})))
+	}
+
+	return r
+}
+
+// registerV1 mendaftarkan semua route API versi 1 di bawah g.
+func (rt routes) registerV1(g *gin.RouterGroup) {
+	cfg := rt.cfg
+
+	if cfg.RegistrationEnabled {
+		g.POST("/register", rt.auth.Register)
+	} else {
+		g.POST("/register", handler.RegistrationDisabled)
+	}
+	g.POST("/login", rt.auth.Login)
+	g.POST("/login/mfa", rt.auth.LoginMFA)
+	g.POST("/token/refresh", rt.auth.Refresh)
+	g.POST("/password/forgot", rt.password.Forgot)
+	g.POST("/password/reset", rt.password.Reset)
+
+	if rt.oidc != nil {
+		g.GET("/oidc/login", rt.oidc.Login)
+		g.GET("/oidc/callback", rt.oidc.Callback)
+	}
+
+	denyImpersonation := middleware.DenyImpersonation()
+	loadUser := middleware.LoadUser(rt.users)
+	userLanguage := middleware.UserLanguage()
+
+	// Logout, enrollment MFA, dan ganti password hanya untuk sesi login,
+	// bukan API key. Route ini juga sengaja tidak memakai requireMFA agar
+	// user yang wajib MFA tetap bisa mendaftar. Token impersonation hanya
+	// boleh logout.
+	session := g.Group("/")
+	session.Use(middleware.JWTAuth(rt.revocations), loadUser, userLanguage, middleware.AuditImpersonation(rt.auditLog))
+	{
+		session.POST("/logout", rt.auth.Logout)
+		session.POST("/me/mfa/enroll", denyImpersonation, rt.mfa.Enroll)
+		session.POST("/me/mfa/confirm", denyImpersonation, rt.mfa.Confirm)
+		session.POST("/me/password", denyImpersonation, rt.password.Change)
+		session.PUT("/me/language", denyImpersonation, rt.user.SetLanguage)
+	}
+
+	customers := g.Group("/customers", rt.customerAccess()...)
+	{
+		customers.GET("", rt.requireAccessReason(middleware.HasQuery("account_number")), rt.customer.SearchByName)
+		customers.GET("/:id", rt.requireAccessReason(nil), rt.customer.GetByID)
+	}
+
+	admin := g.Group("/admin")
+	admin.Use(middleware.Authenticate(rt.revocations, rt.apiKeys), loadUser, userLanguage, denyImpersonation, middleware.RequireScope(model.ScopeAdmin), middleware.RequireRole(model.RoleAdmin), middleware.RequireMFA(cfg.MFARequiredRoles...))
+	{
+		admin.DELETE("/users/:id/mfa", rt.mfa.Disable)
+		admin.GET("/users", rt.user.List)
+		admin.POST("/users", rt.user.Create)
+		admin.POST("/users/:id/disable", rt.user.Disable)
+		admin.POST("/users/:id/enable", rt.user.Enable)
+		admin.PUT("/users/:id/role", rt.user.AssignRole)
+		admin.PUT("/users/:id/tenant", rt.user.AssignTenant)
+		admin.POST("/users/:id/impersonate", rt.impersonation.Start)
+		admin.DELETE("/users/:id", rt.user.Delete)
+		admin.POST("/users/:id/revoke-sessions", rt.auth.RevokeSessions)
+		admin.POST("/users/:id/unlock", rt.auth.Unlock)
+		admin.POST("/users/:id/password-reset", rt.password.AdminReset)
+		admin.GET("/api-keys", rt.apiKey.List)
+		admin.POST("/api-keys", rt.apiKey.Create)
+		admin.DELETE("/api-keys/:id", rt.apiKey.Revoke)
+		admin.GET("/audit-logs", rt.audit.List)
+		admin.GET("/audit-logs/verify", rt.audit.Verify)
+	}
+}
+
+// registerV2 hanya berisi route yang bentuk responsnya berbeda dari v1.
+// Route lain tetap dipanggil lewat /v1.
+func (rt routes) registerV2(g *gin.RouterGroup) {
+	customers := g.Group("/customers", rt.customerAccess()...)
+	{
+		customers.GET("", rt.requireAccessReason(middleware.HasQuery("account_number")), rt.customer.SearchV2)
+		customers.GET("/:id", rt.requireAccessReason(nil), rt.customer.GetByIDV2)
+	}
+}
+
+// customerAccess adalah middleware bersama untuk route data nasabah di
+// semua versi.
+func (rt routes) customerAccess() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		middleware.Authenticate(rt.revocations, rt.apiKeys),
+		middleware.LoadUser(rt.users),
+		middleware.UserLanguage(),
+		middleware.AuditImpersonation(rt.auditLog),
+		middleware.RequireMFA(rt.cfg.MFARequiredRoles...),
+		middleware.RequireScope(model.ScopeCustomersRead),
+		middleware.TenantScope(rt.cfg.TenantGlobalRoles...),
+	}
+}
+
+func (rt routes) requireAccessReason(when func(*gin.Context) bool) gin.HandlerFunc {
+	return middleware.RequireAccessReason(middleware.AccessReasonPolicy{
+		Required:          rt.cfg.AccessReasonRequired,
+		Codes:             rt.cfg.AccessReasonCodes,
+		BreakGlassEnabled: rt.cfg.BreakGlassEnabled,
+	}, when)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/api"
+	"github.com/danisasmita/customer-search/internal/config"
+	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/danisasmita/customer-search/pkg/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// undocumentedRoutes adalah route yang sengaja tidak ditulis di spec.
+var undocumentedRoutes = []string{
+	"GET /openapi.json",
+	"GET /docs",
+	"GET /metrics",
+}
+
+func testRoutes() routes {
+	return routes{
+		cfg:     config.Config{RegistrationEnabled: true, LegacyRoutesEnabled: true, DefaultLanguage: "en"},
+		logger:  logging.Discard(),
+		oidc:    new(handler.OIDCHandler),
+		metrics: metrics.Handler(metrics.Default, "secret"),
+	}
+}
+
+// rootRoutes berada di luar versi API sehingga tidak punya alias lama.
+var rootRoutes = []string{
+	"/healthz",
+	"/readyz",
+	"/version",
+	"/.well-known/jwks.json",
+}
+
+// versioned memisahkan route berprefix versi dan route di luar versi dari
+// alias lama.
+func versioned(path string) bool {
+	return strings.HasPrefix(path, "/v1/") || strings.HasPrefix(path, "/v2/") || slices.Contains(rootRoutes, path)
+}
+
+// TestRoutesMatchOpenAPI gagal jika route di newRouter dan path di
+// api/openapi.json tidak sama persis. Semua route opsional diaktifkan;
+// alias lama tidak didokumentasikan.
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := newRouter(testRoutes())
+
+	var registered []string
+	for _, route := range r.Routes() {
+		entry := route.Method + " " + openAPIPath(route.Path)
+		if versioned(route.Path) && !slices.Contains(undocumentedRoutes, entry) {
+			registered = append(registered, entry)
+		}
+	}
+
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(api.Spec, &spec))
+
+	var documented []string
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	slices.Sort(registered)
+	slices.Sort(documented)
+	assert.Equal(t, documented, registered, "routes in cmd/routes.go and api/openapi.json differ")
+}
+
+// TestLegacyRoutesAliasV1 memastikan setiap route /v1 juga tersedia tanpa
+// prefix, dan sebaliknya.
+func TestLegacyRoutesAliasV1(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := newRouter(testRoutes())
+
+	var v1, legacy []string
+	for _, route := range r.Routes() {
+		entry := route.Method + " " + route.Path
+		switch {
+		case strings.HasPrefix(route.Path, "/v1/"):
+			v1 = append(v1, route.Method+" "+strings.TrimPrefix(route.Path, "/v1"))
+		case !versioned(route.Path) && !slices.Contains(undocumentedRoutes, entry):
+			legacy = append(legacy, entry)
+		}
+	}
+
+	slices.Sort(v1)
+	slices.Sort(legacy)
+	assert.NotEmpty(t, v1)
+	assert.Equal(t, v1, legacy)
+}
+
+func TestLegacyRoutesDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rt := testRoutes()
+	rt.cfg.LegacyRoutesSunset = time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC)
+	r := newRouter(rt)
+
+	// Body kosong ditolak sebelum handler menyentuh service.
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "true", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 30 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</v1/login>; rel="successor-version"`, w.Header().Get("Link"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/login", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Header().Get("Deprecation"))
+}
+
+func TestRoutesOptionalDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := newRouter(routes{cfg: config.Config{DefaultLanguage: "en"}, logger: logging.Discard()})
+
+	var found bool
+	for _, route := range r.Routes() {
+		if route.Method == http.MethodPost && route.Path == "/v1/register" {
+			found = true
+		}
+		assert.NotContains(t, route.Path, "/oidc/")
+		assert.NotEqual(t, "/metrics", route.Path)
+		assert.True(t, versioned(route.Path) || slices.Contains(undocumentedRoutes, route.Method+" "+route.Path), route.Path)
+	}
+	assert.True(t, found)
+}
+
+func TestMetricsRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := newRouter(testRoutes())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `http_request_duration_seconds_count{method="GET",route="/metrics",status="401"} 1`)
+}
+
+// openAPIPath mengubah parameter gin (:id) menjadi bentuk OpenAPI ({id}).
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -1,9 +1,20 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/i18n"
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/danisasmita/customer-search/pkg/notifier"
+	"github.com/danisasmita/customer-search/pkg/tracing"
+	"github.com/danisasmita/customer-search/pkg/utils"
 	"github.com/joho/godotenv"
 )
 
@@ -19,35 +30,354 @@ type Config struct {
 	DBDriver      string
 	DBSource      string
 	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+
+	AccessReasonRequired bool
+	AccessReasonCodes    []string
+	BreakGlassEnabled    bool
+
+	RefreshTokenTTL time.Duration
+
+	JWTAlgorithm        string
+	JWTKeyID            string
+	JWTPrivateKeyFile   string
+	JWTVerificationKeys map[string]string
+
+	LoginMaxFailures     int
+	LoginIPMaxFailures   int
+	LoginLockoutDuration time.Duration
+	LoginBackoffBase     time.Duration
+	LoginBackoffMax      time.Duration
+
+	MFAIssuer        string
+	MFARequiredRoles []string
+	MFAChallengeTTL  time.Duration
+
+	PasswordMinLength       int
+	PasswordRequireUpper    bool
+	PasswordRequireLower    bool
+	PasswordRequireDigit    bool
+	PasswordRequireSymbol   bool
+	PasswordRejectUsername  bool
+	PasswordCommonListFile  string
+	PasswordCommonListLimit int
+
+	PasswordHashAlgorithm string
+	Argon2Memory          int
+	Argon2Iterations      int
+	Argon2Parallelism     int
+	BcryptCost            int
+
+	RegistrationEnabled bool
+
+	PasswordResetTTL           time.Duration
+	PasswordResetMaxRequests   int
+	PasswordResetIPMaxRequests int
+	PasswordResetWindow        time.Duration
+	NotifierKind               string
+	NotifierFile               string
+
+	OIDCIssuer        string
+	OIDCClientID      string
+	OIDCClientSecret  string
+	OIDCRedirectURL   string
+	OIDCScopes        []string
+	OIDCGroupsClaim   string
+	OIDCTenantClaim   string
+	OIDCRoleMapping   map[string]string
+	OIDCDefaultRole   string
+	OIDCDefaultTenant string
+	OIDCStateTTL      time.Duration
+
+	TenantGlobalRoles []string
+
+	ImpersonationTTL time.Duration
+
+	DefaultLanguage string
+
+	LegacyRoutesEnabled      bool
+	LegacyRoutesDeprecatedAt time.Time
+	LegacyRoutesSunset       time.Time
+
+	DBConnectAttempts    int
+	DBConnectBackoffBase time.Duration
+	DBConnectBackoffMax  time.Duration
+	ReadinessTimeout     time.Duration
+
+	ServerReadHeaderTimeout time.Duration
+	ServerReadTimeout       time.Duration
+	ServerWriteTimeout      time.Duration
+	ServerIdleTimeout       time.Duration
+	ShutdownTimeout         time.Duration
+
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSReloadInterval time.Duration
+
+	MetricsAddress string
+	MetricsToken   string
+
+	TracingExporter    string
+	TracingFile        string
+	TracingServiceName string
+	OTLPEndpoint       string
+	OTLPHeaders        map[string]string
+
+	LogLevel             string
+	LogFormat            string
+	DBSlowQueryThreshold time.Duration
+}
+
+// DefaultAccessReasonCodes dipakai jika ACCESS_REASON_CODES tidak diisi.
+var DefaultAccessReasonCodes = []string{
+	"CUSTOMER_REQUEST",
+	"FRAUD_INVESTIGATION",
+	"COMPLIANCE_REVIEW",
+	"COMPLAINT_HANDLING",
 }
 
+// LoadConfig membaca konfigurasi dari environment variable. File .env (atau
+// .env.test jika TEST_ENV=true) dibaca lebih dulu jika ada; variabel yang
+// sudah di-set di environment tidak ditimpa. Variabel yang kosong memakai
+// default di bawah, dan nilai yang tidak bisa di-parse menghasilkan error
+// dengan nama variabelnya.
 func LoadConfig() (*Config, error) {
 	if os.Getenv("TEST_ENV") == "true" {
 		if err := godotenv.Load(".env.test"); err != nil {
 			return nil, err
 		}
-	} else {
-		if err := godotenv.Load(); err != nil {
-			return nil, err
+	} else if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	env := &envReader{}
+	cfg := &Config{
+		DBHost:        env.String("DB_HOST", "localhost"),
+		DBPort:        env.Int("DB_PORT", 5432),
+		DBUser:        env.String("DB_USER", "user"),
+		DBPassword:    env.String("DB_PASSWORD", "password"),
+		DBName:        env.String("DB_NAME", "customer_search"),
+		DBSSLMode:     env.String("DB_SSL_MODE", "disable"),
+		JWTSecret:     env.String("JWT_SECRET", ""),
+		JWTExpiration: env.String("JWT_EXPIRATION", "15m"),
+		DBDriver:      env.String("DB_DRIVER", "postgres"),
+		DBSource:      env.String("DB_SOURCE", ""),
+		ServerAddress: env.String("SERVER_ADDRESS", ":8080"),
+
+		AccessReasonRequired: env.Bool("ACCESS_REASON_REQUIRED", false),
+		AccessReasonCodes:    env.List("ACCESS_REASON_CODES", DefaultAccessReasonCodes),
+		BreakGlassEnabled:    env.Bool("BREAK_GLASS_ENABLED", false),
+
+		RefreshTokenTTL: env.Duration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		JWTAlgorithm:        env.String("JWT_ALGORITHM", utils.AlgorithmHS256),
+		JWTKeyID:            env.String("JWT_KEY_ID", ""),
+		JWTPrivateKeyFile:   env.String("JWT_PRIVATE_KEY_FILE", ""),
+		JWTVerificationKeys: env.Pairs("JWT_VERIFICATION_KEYS"),
+
+		LoginMaxFailures:     env.Int("LOGIN_MAX_FAILURES", 5),
+		LoginIPMaxFailures:   env.Int("LOGIN_IP_MAX_FAILURES", 20),
+		LoginLockoutDuration: env.Duration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		LoginBackoffBase:     env.Duration("LOGIN_BACKOFF_BASE", time.Second),
+		LoginBackoffMax:      env.Duration("LOGIN_BACKOFF_MAX", time.Minute),
+
+		MFAIssuer:        env.String("MFA_ISSUER", "Customer Search"),
+		MFARequiredRoles: env.List("MFA_REQUIRED_ROLES", nil),
+		MFAChallengeTTL:  env.Duration("MFA_CHALLENGE_TTL", 5*time.Minute),
+
+		PasswordMinLength:       env.Int("PASSWORD_MIN_LENGTH", 10),
+		PasswordRequireUpper:    env.Bool("PASSWORD_REQUIRE_UPPER", true),
+		PasswordRequireLower:    env.Bool("PASSWORD_REQUIRE_LOWER", true),
+		PasswordRequireDigit:    env.Bool("PASSWORD_REQUIRE_DIGIT", true),
+		PasswordRequireSymbol:   env.Bool("PASSWORD_REQUIRE_SYMBOL", false),
+		PasswordRejectUsername:  env.Bool("PASSWORD_REJECT_USERNAME", true),
+		PasswordCommonListFile:  env.String("PASSWORD_COMMON_LIST_FILE", "data/common-passwords.txt"),
+		PasswordCommonListLimit: env.Int("PASSWORD_COMMON_LIST_LIMIT", 10000),
+
+		PasswordHashAlgorithm: env.String("PASSWORD_HASH_ALGORITHM", utils.HashArgon2id),
+		Argon2Memory:          env.Int("ARGON2_MEMORY", 64*1024),
+		Argon2Iterations:      env.Int("ARGON2_ITERATIONS", 3),
+		Argon2Parallelism:     env.Int("ARGON2_PARALLELISM", 2),
+		BcryptCost:            env.Int("BCRYPT_COST", 10),
+
+		RegistrationEnabled: env.Bool("REGISTRATION_ENABLED", true),
+
+		PasswordResetTTL:           env.Duration("PASSWORD_RESET_TTL", 30*time.Minute),
+		PasswordResetMaxRequests:   env.Int("PASSWORD_RESET_MAX_REQUESTS", 3),
+		PasswordResetIPMaxRequests: env.Int("PASSWORD_RESET_IP_MAX_REQUESTS", 20),
+		PasswordResetWindow:        env.Duration("PASSWORD_RESET_WINDOW", time.Hour),
+		NotifierKind:               env.String("NOTIFIER", notifier.KindLog),
+		NotifierFile:               env.String("NOTIFIER_FILE", "notifications.log"),
+
+		OIDCIssuer:        env.String("OIDC_ISSUER", ""),
+		OIDCClientID:      env.String("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:  env.String("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:   env.String("OIDC_REDIRECT_URL", "http://localhost:8080/v1/oidc/callback"),
+		OIDCScopes:        env.List("OIDC_SCOPES", []string{"openid", "profile", "email"}),
+		OIDCGroupsClaim:   env.String("OIDC_GROUPS_CLAIM", "groups"),
+		OIDCTenantClaim:   env.String("OIDC_TENANT_CLAIM", ""),
+		OIDCRoleMapping:   env.Pairs("OIDC_ROLE_MAPPING"),
+		OIDCDefaultRole:   env.String("OIDC_DEFAULT_ROLE", ""),
+		OIDCDefaultTenant: env.String("OIDC_DEFAULT_TENANT", ""),
+		OIDCStateTTL:      env.Duration("OIDC_STATE_TTL", 10*time.Minute),
+
+		TenantGlobalRoles: env.List("TENANT_GLOBAL_ROLES", []string{model.RoleAdmin}),
+
+		ImpersonationTTL: env.Duration("IMPERSONATION_TTL", 15*time.Minute),
+
+		DefaultLanguage: env.String("DEFAULT_LANGUAGE", i18n.English),
+
+		LegacyRoutesEnabled:      env.Bool("LEGACY_ROUTES_ENABLED", true),
+		LegacyRoutesDeprecatedAt: env.Date("LEGACY_ROUTES_DEPRECATED_AT", "2026-10-19"),
+		LegacyRoutesSunset:       env.Date("LEGACY_ROUTES_SUNSET", "2027-04-30"),
+
+		DBConnectAttempts:    env.Int("DB_CONNECT_ATTEMPTS", 10),
+		DBConnectBackoffBase: env.Duration("DB_CONNECT_BACKOFF_BASE", time.Second),
+		DBConnectBackoffMax:  env.Duration("DB_CONNECT_BACKOFF_MAX", 30*time.Second),
+		ReadinessTimeout:     env.Duration("READINESS_TIMEOUT", 2*time.Second),
+
+		ServerReadHeaderTimeout: env.Duration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+		ServerReadTimeout:       env.Duration("SERVER_READ_TIMEOUT", 30*time.Second),
+		ServerWriteTimeout:      env.Duration("SERVER_WRITE_TIMEOUT", time.Minute),
+		ServerIdleTimeout:       env.Duration("SERVER_IDLE_TIMEOUT", 2*time.Minute),
+		ShutdownTimeout:         env.Duration("SHUTDOWN_TIMEOUT", 25*time.Second),
+
+		TLSCertFile:       env.String("TLS_CERT_FILE", ""),
+		TLSKeyFile:        env.String("TLS_KEY_FILE", ""),
+		TLSReloadInterval: env.Duration("TLS_RELOAD_INTERVAL", time.Minute),
+
+		MetricsAddress: env.String("METRICS_ADDRESS", ":9090"),
+		MetricsToken:   env.String("METRICS_TOKEN", ""),
+
+		TracingExporter:    env.String("TRACING_EXPORTER", tracing.ExporterNone),
+		TracingFile:        env.String("TRACING_FILE", "traces.jsonl"),
+		TracingServiceName: env.String("OTEL_SERVICE_NAME", "customer-search"),
+		OTLPEndpoint:       env.String("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"),
+		OTLPHeaders:        env.Pairs("OTEL_EXPORTER_OTLP_HEADERS"),
+
+		LogLevel:             env.String("LOG_LEVEL", "info"),
+		LogFormat:            env.String("LOG_FORMAT", logging.FormatJSON),
+		DBSlowQueryThreshold: env.Duration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+	}
+	if env.err != nil {
+		return nil, env.err
+	}
+	return cfg, nil
+}
+
+// envReader membaca environment variable dengan nilai default dan
+// menyimpan error parse pertama agar LoadConfig cukup memeriksanya sekali.
+type envReader struct {
+	err error
+}
+
+// String memakai nilai variabel apa adanya jika di-set, termasuk string
+// kosong, misalnya METRICS_ADDRESS= untuk mematikan listener metrics.
+func (r *envReader) String(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+func (r *envReader) Int(key string, defaultValue int) int {
+	value, err := parseInt(os.Getenv(key), defaultValue)
+	r.fail(key, err)
+	return value
+}
+
+// Bool menerima format strconv.ParseBool: 1, t, true, 0, f, false, dan
+// variasi huruf besarnya.
+func (r *envReader) Bool(key string, defaultValue bool) bool {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(raw)
+	r.fail(key, err)
+	return value
+}
+
+func (r *envReader) Duration(key string, defaultValue time.Duration) time.Duration {
+	value, err := ParseDuration(os.Getenv(key), defaultValue)
+	r.fail(key, err)
+	return value
+}
+
+// Date menerima default dalam format yang sama dengan variabelnya.
+func (r *envReader) Date(key, defaultValue string) time.Time {
+	raw := os.Getenv(key)
+	if raw == "" {
+		raw = defaultValue
+	}
+	value, err := ParseDate(raw, time.Time{})
+	r.fail(key, err)
+	return value
+}
+
+func (r *envReader) List(key string, defaultValue []string) []string {
+	return SplitList(os.Getenv(key), defaultValue)
+}
+
+func (r *envReader) Pairs(key string) map[string]string {
+	return SplitPairs(os.Getenv(key))
+}
+
+func (r *envReader) fail(key string, err error) {
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("invalid %s: %w", key, err)
+	}
+}
+
+// SplitList memecah nilai dipisah koma dan membuang elemen kosong.
+func SplitList(value string, defaultValue []string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
 	}
+	if len(items) == 0 {
+		return defaultValue
+	}
+	return items
+}
 
-	dbPort, err := strconv.Atoi(os.Getenv("DB_PORT"))
-	if err != nil {
-		return nil, err
+func parseInt(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
 	}
+	return strconv.Atoi(value)
+}
+
+// SplitPairs memecah nilai "a=1,b=2" menjadi map. Elemen tanpa "=" diabaikan.
+func SplitPairs(value string) map[string]string {
+	pairs := map[string]string{}
+	for _, item := range SplitList(value, nil) {
+		key, val, ok := strings.Cut(item, "=")
+		if ok && key != "" {
+			pairs[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		}
+	}
+	return pairs
+}
 
-	return &Config{
-		DBHost:        os.Getenv("DB_HOST"),
-		DBPort:        dbPort,
-		DBUser:        os.Getenv("DB_USER"),
-		DBPassword:    os.Getenv("DB_PASSWORD"),
-		DBName:        os.Getenv("DB_NAME"),
-		DBSSLMode:     os.Getenv("DB_SSL_MODE"),
-		JWTSecret:     os.Getenv("JWT_SECRET"),
-		JWTExpiration: os.Getenv("JWT_EXPIRATION"),
-		DBDriver:      os.Getenv("DB_DRIVER"),
-		DBSource:      os.Getenv("DB_SOURCE"),
-		ServerAddress: os.Getenv("SERVER_ADDRESS"),
-	}, nil
+// ParseDuration menerima format durasi Go ("15m", "24h") atau jumlah detik
+// ("3600"). Nilai kosong menghasilkan defaultValue.
+func ParseDuration(value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// ParseDate menerima tanggal "2006-01-02" dalam UTC. Nilai kosong
+// menghasilkan defaultValue.
+func ParseDate(value string, defaultValue time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return time.Parse(time.DateOnly, value)
 }
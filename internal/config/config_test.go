@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -59,13 +60,41 @@ func TestLoadConfigInvalidDBPort(t *testing.T) {
 	assert.Error(t, err, "LoadConfig should return an error for invalid DB_PORT")
 }
 
-func TestLoadConfigMissingEnvFile(t *testing.T) {
-	// Simulate missing .env file by unsetting variables
+func TestLoadConfigWithoutEnvFile(t *testing.T) {
+	// Tanpa .env, misalnya di container, nilai diambil dari environment dan
+	// default
 	os.Clearenv()
 
-	// Call LoadConfig
-	_, err := LoadConfig()
+	config, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 5432, config.DBPort)
+	assert.Equal(t, "disable", config.DBSSLMode)
+	assert.Equal(t, []string{"admin"}, config.TenantGlobalRoles)
+	assert.Equal(t, "Customer Search", config.MFAIssuer)
+	assert.Equal(t, "en", config.DefaultLanguage)
+	assert.Equal(t, "json", config.LogFormat)
+	assert.Equal(t, ":9090", config.MetricsAddress)
+	assert.True(t, config.RegistrationEnabled)
+	assert.True(t, config.PasswordRequireUpper)
+	assert.Equal(t, 30*24*time.Hour, config.RefreshTokenTTL)
+	assert.Equal(t, 2027, config.LegacyRoutesSunset.Year())
+}
+
+func TestLoadConfigEnvironment(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("ACCESS_REASON_REQUIRED", "1")
+	t.Setenv("REGISTRATION_ENABLED", "FALSE")
+	t.Setenv("METRICS_ADDRESS", "")
+	t.Setenv("TENANT_GLOBAL_ROLES", "admin, auditor")
+
+	config, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.True(t, config.AccessReasonRequired)
+	assert.False(t, config.RegistrationEnabled)
+	assert.Empty(t, config.MetricsAddress, "an empty value disables the metrics listener")
+	assert.Equal(t, []string{"admin", "auditor"}, config.TenantGlobalRoles)
 
-	// Expect an error because .env file is missing
-	assert.Error(t, err, "LoadConfig should return an error if .env is missing")
+	t.Setenv("BREAK_GLASS_ENABLED", "yes")
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, "BREAK_GLASS_ENABLED")
 }
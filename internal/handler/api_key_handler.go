@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	service service.APIKeyService
+}
+
+func NewAPIKeyHandler(service service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) {
+	var userID uint64
+	if v := c.Query("user_id"); v != "" {
+		var err error
+		userID, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			respondError(c, errBadRequest)
+			return
+		}
+	}
+
+	keys, err := h.service.List(uint(userID))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
+
+// Create mengembalikan nilai key lengkap satu kali saja.
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var request model.CreateAPIKeyRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	key, err := h.service.Create(request, c.GetUint("userID"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": key})
+}
+
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, errBadRequest)
+		return
+	}
+
+	if err := h.service.Revoke(uint(id), c.GetUint("userID")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, messageBody(c, message.KeyAPIKeyRevoked))
+}
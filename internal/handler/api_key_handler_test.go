@@ -0,0 +1,93 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyService adalah mock untuk service.APIKeyService
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) Create(request model.CreateAPIKeyRequest, adminID uint) (*model.APIKeyResponse, error) {
+	args := m.Called(request, adminID)
+	key, _ := args.Get(0).(*model.APIKeyResponse)
+	return key, args.Error(1)
+}
+
+func (m *MockAPIKeyService) List(userID uint) ([]model.APIKeyResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.APIKeyResponse), args.Error(1)
+}
+
+func (m *MockAPIKeyService) Revoke(id, adminID uint) error {
+	args := m.Called(id, adminID)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Authenticate(key string) (*model.APIKey, error) {
+	args := m.Called(key)
+	found, _ := args.Get(0).(*model.APIKey)
+	return found, args.Error(1)
+}
+
+func TestAPIKeyHandler(t *testing.T) {
+	mockService := new(MockAPIKeyService)
+	apiKeyHandler := handler.NewAPIKeyHandler(mockService)
+
+	router := setupRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+	})
+	router.GET("/admin/api-keys", apiKeyHandler.List)
+	router.POST("/admin/api-keys", apiKeyHandler.Create)
+	router.DELETE("/admin/api-keys/:id", apiKeyHandler.Revoke)
+
+	valid := model.CreateAPIKeyRequest{Name: "batch", UserID: 7, Scopes: []string{model.ScopeCustomersRead}}
+	invalid := model.CreateAPIKeyRequest{Name: "batch", UserID: 7, Scopes: []string{"everything"}}
+	mockService.On("Create", valid, uint(1)).Return(&model.APIKeyResponse{ID: 1, Key: "csk_secret"}, nil).Once()
+	mockService.On("List", uint(7)).Return([]model.APIKeyResponse{{ID: 1, Prefix: "csk_abcdefgh"}}, nil).Once()
+	mockService.On("Revoke", uint(9), uint(1)).Return(service.ErrAPIKeyNotFound).Once()
+
+	send := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var reqBody []byte
+		if body != nil {
+			reqBody, _ = json.Marshal(body)
+		}
+		req, _ := http.NewRequest(method, path, bytes.NewReader(reqBody))
+		req.Header.Set(contentTypeHeader, contentType)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	recorder := send(http.MethodPost, "/admin/api-keys", valid)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "csk_secret")
+
+	// Scope tidak valid ditolak oleh validasi request sebelum service.
+	recorder = send(http.MethodPost, "/admin/api-keys", invalid)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"field":"scopes[0]","code":"scope"`)
+	assert.Contains(t, recorder.Body.String(), model.ScopeAdmin)
+
+	recorder = send(http.MethodGet, "/admin/api-keys?user_id=7", nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), `"key"`)
+
+	recorder = send(http.MethodDelete, "/admin/api-keys/9", nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	mockService.AssertExpectations(t)
+}
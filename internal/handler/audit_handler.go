@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditHandler struct {
+	service service.AuditService
+}
+
+func NewAuditHandler(service service.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+func (h *AuditHandler) List(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		respondError(c, errInvalidAuditFilter)
+		return
+	}
+
+	logs, err := h.service.Query(filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": logs,
+	})
+}
+
+func (h *AuditHandler) Verify(c *gin.Context) {
+	result, err := h.service.Verify()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
+
+func parseAuditFilter(c *gin.Context) (model.AuditFilter, error) {
+	filter := model.AuditFilter{
+		Action: c.Query("action"),
+		Limit:  defaultAuditLimit,
+	}
+
+	if v := c.Query("user_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return filter, err
+		}
+		filter.UserID = uint(id)
+	}
+	if v := c.Query("review_required"); v != "" {
+		reviewRequired, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, err
+		}
+		filter.ReviewRequired = reviewRequired
+	}
+	if v := c.Query("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, err
+		}
+		filter.From = from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, err
+		}
+		filter.To = to
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, errors.New(message.InvalidAuditFilter)
+		}
+		filter.Limit = min(limit, maxAuditLimit)
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,64 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditHandlerList(t *testing.T) {
+	mockAudit := new(MockAuditService)
+	auditHandler := handler.NewAuditHandler(mockAudit)
+	router := setupRouter()
+	router.GET("/admin/audit-logs", auditHandler.List)
+
+	t.Run("success - filter by user", func(t *testing.T) {
+		logs := []model.AuditLog{{Seq: 1, UserID: 7, Action: model.AuditActionCustomerSearch}}
+		mockAudit.On("Query", mock.MatchedBy(func(filter model.AuditFilter) bool {
+			return filter.UserID == 7 && filter.Limit == 10
+		})).Return(logs, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/admin/audit-logs?user_id=7&limit=10", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Data []model.AuditLog `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Equal(t, logs, response.Data)
+		mockAudit.AssertExpectations(t)
+	})
+
+	t.Run("error - invalid time filter", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/admin/audit-logs?from=yesterday", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestAuditHandlerVerify(t *testing.T) {
+	mockAudit := new(MockAuditService)
+	auditHandler := handler.NewAuditHandler(mockAudit)
+	router := setupRouter()
+	router.GET("/admin/audit-logs/verify", auditHandler.Verify)
+
+	mockAudit.On("Verify").Return(&model.AuditVerification{Valid: false, Checked: 2, BrokenAt: 3}, nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/admin/audit-logs/verify", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"data":{"valid":false,"checked":2,"broken_at":3}}`, recorder.Body.String())
+	mockAudit.AssertExpectations(t)
+}
@@ -1,14 +1,14 @@
 package handler
 
 import (
-	"fmt"
 	"net/http"
-
-	"errors"
+	"strconv"
 
 	"github.com/danisasmita/customer-search/internal/model"
 	"github.com/danisasmita/customer-search/internal/service"
 	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/metrics"
+	"github.com/danisasmita/customer-search/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -21,52 +21,133 @@ func NewAuthHandler(service service.AuthService) *AuthHandler {
 }
 
 func (h *AuthHandler) Register(c *gin.Context) {
-	var user model.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": message.BadRequest})
+	var request model.UserRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	if user.Username == "" || user.Password == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": message.UsernameRequired + " and " + message.PasswordRequired})
+	user := model.User{Username: request.Username, Password: request.Password}
+	if err := h.service.Register(&user); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	if err := h.service.Register(&user); err != nil {
-		fmt.Println("Error registering user:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": message.InternalServerError})
+	c.JSON(http.StatusCreated, messageBody(c, message.KeyUserRegistered))
+}
+
+// RegistrationDisabled dipasang di /register saat REGISTRATION_ENABLED=false.
+func RegistrationDisabled(c *gin.Context) {
+	respondError(c, errRegistrationClosed)
+}
+
+func (h *AuthHandler) Login(c *gin.Context) {
+	var user model.UserRequest
+	if err := bindJSON(c, &user); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	tokens, err := h.service.Login(user, c.ClientIP())
+	metrics.Logins.WithLabelValues(metrics.LoginStepPassword, loginResult(tokens, err)).Inc()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var request model.MFALoginRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": message.UserRegistered})
+	tokens, err := h.service.LoginMFA(request, c.ClientIP())
+	metrics.Logins.WithLabelValues(metrics.LoginStepMFA, loginResult(tokens, err)).Inc()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
 }
 
-func (h *AuthHandler) ValidateCredentials(user model.UserRequest) error {
-	if user.Username == "" || user.Password == "" {
-		return errors.New(message.UsernameRequired + " and " + message.PasswordRequired)
+// loginResult mengubah hasil satu langkah login menjadi label result di
+// metrics.Logins.
+func loginResult(response *model.LoginResponse, err error) string {
+	switch {
+	case err != nil:
+		return metrics.LoginFailure
+	case response.MFARequired:
+		return metrics.LoginMFARequired
 	}
-	return nil
+	return metrics.LoginSuccess
 }
 
-func (h *AuthHandler) Login(c *gin.Context) {
-	var user model.UserRequest
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": message.BadRequest})
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var request model.RefreshRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	if err := h.ValidateCredentials(user); err != nil {
-		fmt.Printf("Validation error: %v\n", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	tokens, err := h.service.Refresh(request.RefreshToken)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, ok := c.MustGet("claims").(*utils.Claims)
+	if !ok {
+		respondError(c, errUnauthorized)
+		return
+	}
+
+	// Body bersifat opsional; tanpa refresh token hanya access token yang dicabut.
+	var request model.LogoutRequest
+	_ = c.ShouldBindJSON(&request)
+
+	if err := h.service.Logout(claims, request.RefreshToken); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, messageBody(c, message.KeyLoggedOut))
+}
+
+func (h *AuthHandler) RevokeSessions(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, errBadRequest)
+		return
+	}
+
+	if err := h.service.RevokeSessions(uint(userID)); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	token, err := h.service.Login(user)
+	c.JSON(http.StatusOK, messageBody(c, message.KeySessionsRevoked))
+}
+
+func (h *AuthHandler) Unlock(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		fmt.Printf("Login error: %v\n", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": message.InvalidCredentials})
+		respondError(c, errBadRequest)
+		return
+	}
+
+	if err := h.service.Unlock(uint(userID), c.GetUint("userID")); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, messageBody(c, message.KeyAccountUnlocked))
 }
@@ -7,14 +7,21 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/danisasmita/customer-search/internal/handler"
 	"github.com/danisasmita/customer-search/internal/model"
 	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/logging"
 	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/metrics"
+	"github.com/danisasmita/customer-search/pkg/problem"
+	"github.com/danisasmita/customer-search/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 // Mock service untuk AuthService
@@ -27,9 +34,37 @@ func (m *MockAuthService) Register(user *model.User) error {
 	return args.Error(0)
 }
 
-func (m *MockAuthService) Login(user model.UserRequest) (string, error) {
-	args := m.Called(user)
-	return args.String(0), args.Error(1)
+func (m *MockAuthService) Login(user model.UserRequest, clientIP string) (*model.LoginResponse, error) {
+	args := m.Called(user, clientIP)
+	tokens, _ := args.Get(0).(*model.LoginResponse)
+	return tokens, args.Error(1)
+}
+
+func (m *MockAuthService) Refresh(refreshToken string) (*model.LoginResponse, error) {
+	args := m.Called(refreshToken)
+	tokens, _ := args.Get(0).(*model.LoginResponse)
+	return tokens, args.Error(1)
+}
+
+func (m *MockAuthService) Logout(claims *utils.Claims, refreshToken string) error {
+	args := m.Called(claims, refreshToken)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) RevokeSessions(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) LoginMFA(request model.MFALoginRequest, clientIP string) (*model.LoginResponse, error) {
+	args := m.Called(request, clientIP)
+	response, _ := args.Get(0).(*model.LoginResponse)
+	return response, args.Error(1)
+}
+
+func (m *MockAuthService) Unlock(userID, adminID uint) error {
+	args := m.Called(userID, adminID)
+	return args.Error(0)
 }
 
 // Tambahkan setelah MockAuthService
@@ -42,6 +77,61 @@ func (m *MockUserRepository) CreateUser(user *model.User) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) FindUserByID(id uint) (*model.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateUser(user *model.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindUsers(filter model.UserFilter) ([]model.User, int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]model.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) DeleteUser(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassword(id uint, hash string) error {
+	args := m.Called(id, hash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) AdvanceMFAStep(id uint, step int64) (bool, error) {
+	args := m.Called(id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) ResetMFA(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateRole(id uint, role string) error {
+	args := m.Called(id, role)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateTenant(id uint, tenantID string) error {
+	args := m.Called(id, tenantID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetMFASecret(id uint, secret string) error {
+	args := m.Called(id, secret)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) EnableMFA(id uint, secret string, step int64) (bool, error) {
+	args := m.Called(id, secret, step)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockUserRepository) FindUserByUsername(username string) (*model.User, error) {
 	args := m.Called(username)
 	return args.Get(0).(*model.User), args.Error(1)
@@ -55,6 +145,8 @@ func setupRouter() *gin.Engine {
 const (
 	registerPath      = "/register"
 	loginPath         = "/login"
+	refreshPath       = "/token/refresh"
+	logoutPath        = "/logout"
 	contentType       = "application/json"
 	contentTypeHeader = "Content-Type"
 )
@@ -69,7 +161,7 @@ func TestAuthHandlerRegister(t *testing.T) {
 		user := model.User{Username: "john_doe", Password: "password123"}
 		mockService.On("Register", &user).Return(nil)
 
-		reqBody, _ := json.Marshal(user)
+		reqBody, _ := json.Marshal(model.UserRequest{Username: user.Username, Password: user.Password})
 		req, _ := http.NewRequest(http.MethodPost, registerPath, bytes.NewBuffer(reqBody))
 		req.Header.Set(contentTypeHeader, contentType)
 		recorder := httptest.NewRecorder()
@@ -82,7 +174,7 @@ func TestAuthHandlerRegister(t *testing.T) {
 
 	t.Run("error - missing username or password", func(t *testing.T) {
 		user := model.User{}
-		reqBody, _ := json.Marshal(user)
+		reqBody, _ := json.Marshal(model.UserRequest{Username: user.Username, Password: user.Password})
 		req, _ := http.NewRequest(http.MethodPost, registerPath, bytes.NewBuffer(reqBody))
 		req.Header.Set(contentTypeHeader, contentType)
 		recorder := httptest.NewRecorder()
@@ -90,11 +182,30 @@ func TestAuthHandlerRegister(t *testing.T) {
 		router.ServeHTTP(recorder, req)
 
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, problem.ContentType, recorder.Header().Get(contentTypeHeader))
+
+		var body problem.Problem
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		assert.Equal(t, message.CodeValidationFailed, body.Code)
+		assert.Equal(t, []problem.FieldError{
+			{Field: "username", Code: message.CodeRequired, Message: message.UsernameRequired},
+			{Field: "password", Code: message.CodeRequired, Message: message.PasswordRequired},
+		}, body.Errors)
+	})
+
+	t.Run("error - username taken", func(t *testing.T) {
+		user := model.User{Username: "taken", Password: "password123"}
+		mockService.On("Register", &user).Return(service.ErrUsernameTaken).Once()
+
+		recorder := postJSON(router, registerPath, model.UserRequest{Username: user.Username, Password: user.Password})
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"code":"username_taken"`)
 	})
 
 	t.Run("error - internal server error", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
-		mockService := service.NewAuthService(mockRepo)
+		mockService := service.NewAuthService(mockRepo, nil, nil, nil, service.PasswordPolicy{}, logging.Discard())
 		authHandler := handler.NewAuthHandler(mockService)
 
 		router := setupRouter()
@@ -103,9 +214,10 @@ func TestAuthHandlerRegister(t *testing.T) {
 		user := model.User{Username: "john_doe", Password: "password123"}
 
 		// Mocking `CreateUser` untuk mengembalikan error
+		mockRepo.On("FindUserByUsername", user.Username).Return((*model.User)(nil), gorm.ErrRecordNotFound).Once()
 		mockRepo.On("CreateUser", mock.AnythingOfType("*model.User")).Return(errors.New("internal error")).Once()
 
-		reqBody, _ := json.Marshal(user)
+		reqBody, _ := json.Marshal(model.UserRequest{Username: user.Username, Password: user.Password})
 		req, _ := http.NewRequest(http.MethodPost, registerPath, bytes.NewBuffer(reqBody))
 		req.Header.Set(contentTypeHeader, contentType)
 		recorder := httptest.NewRecorder()
@@ -116,6 +228,36 @@ func TestAuthHandlerRegister(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("error - password policy", func(t *testing.T) {
+		user := model.User{Username: "jane_doe", Password: "1"}
+		mockService.On("Register", &user).Return(&service.PasswordPolicyError{Failures: []service.PasswordRuleFailure{
+			{Rule: service.PasswordRuleMinLength, Message: "password must be at least 10 characters", Params: map[string]interface{}{"min": 10}},
+			{Rule: service.PasswordRuleCommon, Message: message.PasswordTooCommon},
+		}}).Once()
+
+		reqBody, _ := json.Marshal(model.UserRequest{Username: user.Username, Password: user.Password})
+		req, _ := http.NewRequest(http.MethodPost, registerPath, bytes.NewBuffer(reqBody))
+		req.Header.Set(contentTypeHeader, contentType)
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, problem.ContentType, recorder.Header().Get(contentTypeHeader))
+		assert.JSONEq(t, `{
+			"type": "about:blank",
+			"title": "Bad Request",
+			"status": 400,
+			"detail": "password does not meet the password policy",
+			"instance": "/register",
+			"code": "password_policy",
+			"errors": [
+				{"field": "password", "code": "min_length", "message": "password must be at least 10 characters"},
+				{"field": "password", "code": "common_password", "message": "password is too common"}
+			]
+		}`, recorder.Body.String())
+	})
+
 }
 
 func TestAuthHandlerLogin(t *testing.T) {
@@ -126,7 +268,7 @@ func TestAuthHandlerLogin(t *testing.T) {
 
 	t.Run("success - login", func(t *testing.T) {
 		user := model.UserRequest{Username: "john_doe", Password: "password123"}
-		mockService.On("Login", user).Return("valid-token", nil)
+		mockService.On("Login", user, mock.Anything).Return(&model.LoginResponse{AccessToken: "valid-token", RefreshToken: "refresh", ExpiresIn: 900}, nil)
 
 		reqBody, _ := json.Marshal(user)
 		req, _ := http.NewRequest(http.MethodPost, loginPath, bytes.NewBuffer(reqBody))
@@ -136,6 +278,7 @@ func TestAuthHandlerLogin(t *testing.T) {
 		router.ServeHTTP(recorder, req)
 
 		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"token":"valid-token","refresh_token":"refresh","expires_in":900}`, recorder.Body.String())
 		mockService.AssertExpectations(t)
 	})
 
@@ -153,7 +296,23 @@ func TestAuthHandlerLogin(t *testing.T) {
 
 	t.Run("error - invalid credentials", func(t *testing.T) {
 		user := model.UserRequest{Username: "john_doe", Password: "wrongpassword"}
-		mockService.On("Login", user).Return("", errors.New(message.InvalidCredentials))
+		mockService.On("Login", user, mock.Anything).Return(nil, service.ErrInvalidCredentials)
+
+		reqBody, _ := json.Marshal(user)
+		req, _ := http.NewRequest(http.MethodPost, loginPath, bytes.NewBuffer(reqBody))
+		req.Header.Set(contentTypeHeader, contentType)
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("error - too many attempts", func(t *testing.T) {
+		user := model.UserRequest{Username: "locked_user", Password: "password123"}
+		mockService.On("Login", user, mock.Anything).
+			Return(nil, &service.LoginThrottledError{RetryAfter: 1500 * time.Millisecond}).Once()
 
 		reqBody, _ := json.Marshal(user)
 		req, _ := http.NewRequest(http.MethodPost, loginPath, bytes.NewBuffer(reqBody))
@@ -162,7 +321,192 @@ func TestAuthHandlerLogin(t *testing.T) {
 
 		router.ServeHTTP(recorder, req)
 
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.Equal(t, "2", recorder.Header().Get("Retry-After"))
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAuthHandlerLoginMetrics(t *testing.T) {
+	mockService := new(MockAuthService)
+	authHandler := handler.NewAuthHandler(mockService)
+	router := setupRouter()
+	router.POST(loginPath, authHandler.Login)
+
+	mockService.On("Login", model.UserRequest{Username: "mfa_user", Password: "password123"}, mock.Anything).
+		Return(&model.LoginResponse{MFARequired: true, MFAToken: "challenge"}, nil).Once()
+	mockService.On("Login", model.UserRequest{Username: "mfa_user", Password: "wrongpassword"}, mock.Anything).
+		Return(nil, service.ErrInvalidCredentials).Once()
+
+	mfaRequired := metrics.Logins.WithLabelValues(metrics.LoginStepPassword, metrics.LoginMFARequired)
+	failure := metrics.Logins.WithLabelValues(metrics.LoginStepPassword, metrics.LoginFailure)
+	mfaRequiredBefore, failureBefore := testutil.ToFloat64(mfaRequired), testutil.ToFloat64(failure)
+
+	for _, password := range []string{"password123", "wrongpassword"} {
+		reqBody, _ := json.Marshal(model.UserRequest{Username: "mfa_user", Password: password})
+		req, _ := http.NewRequest(http.MethodPost, loginPath, bytes.NewBuffer(reqBody))
+		req.Header.Set(contentTypeHeader, contentType)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, mfaRequiredBefore+1, testutil.ToFloat64(mfaRequired))
+	assert.Equal(t, failureBefore+1, testutil.ToFloat64(failure))
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandlerLoginMFA(t *testing.T) {
+	mockService := new(MockAuthService)
+	authHandler := handler.NewAuthHandler(mockService)
+	router := setupRouter()
+	router.POST("/login/mfa", authHandler.LoginMFA)
+
+	tests := []struct {
+		name           string
+		request        model.MFALoginRequest
+		err            error
+		expectedStatus int
+	}{
+		{name: "success", request: model.MFALoginRequest{MFAToken: "challenge", Code: "123456"}, expectedStatus: http.StatusOK},
+		{name: "invalid code", request: model.MFALoginRequest{MFAToken: "challenge", Code: "000000"}, err: service.ErrMFALoginFailed, expectedStatus: http.StatusUnauthorized},
+		{name: "expired challenge", request: model.MFALoginRequest{MFAToken: "expired", Code: "123456"}, err: service.ErrInvalidMFAChallenge, expectedStatus: http.StatusUnauthorized},
+		{name: "missing code", request: model.MFALoginRequest{MFAToken: "challenge"}, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expectedStatus != http.StatusBadRequest {
+				var response *model.LoginResponse
+				if tt.err == nil {
+					response = &model.LoginResponse{AccessToken: "access"}
+				}
+				mockService.On("LoginMFA", tt.request, mock.Anything).Return(response, tt.err).Once()
+			}
+
+			reqBody, _ := json.Marshal(tt.request)
+			req, _ := http.NewRequest(http.MethodPost, "/login/mfa", bytes.NewBuffer(reqBody))
+			req.Header.Set(contentTypeHeader, contentType)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandlerUnlock(t *testing.T) {
+	mockService := new(MockAuthService)
+	authHandler := handler.NewAuthHandler(mockService)
+	router := setupRouter()
+	router.POST("/admin/users/:id/unlock", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+	}, authHandler.Unlock)
+
+	mockService.On("Unlock", uint(3), uint(1)).Return(nil).Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/admin/users/3/unlock", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandlerRefresh(t *testing.T) {
+	mockService := new(MockAuthService)
+	authHandler := handler.NewAuthHandler(mockService)
+	router := setupRouter()
+	router.POST(refreshPath, authHandler.Refresh)
+
+	t.Run("success - rotate token", func(t *testing.T) {
+		mockService.On("Refresh", "old-refresh").
+			Return(&model.LoginResponse{AccessToken: "new-access", RefreshToken: "new-refresh"}, nil).Once()
+
+		reqBody, _ := json.Marshal(model.RefreshRequest{RefreshToken: "old-refresh"})
+		req, _ := http.NewRequest(http.MethodPost, refreshPath, bytes.NewBuffer(reqBody))
+		req.Header.Set(contentTypeHeader, contentType)
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "new-refresh")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("error - reused token", func(t *testing.T) {
+		mockService.On("Refresh", "used-refresh").Return(nil, service.ErrInvalidRefreshToken).Once()
+
+		reqBody, _ := json.Marshal(model.RefreshRequest{RefreshToken: "used-refresh"})
+		req, _ := http.NewRequest(http.MethodPost, refreshPath, bytes.NewBuffer(reqBody))
+		req.Header.Set(contentTypeHeader, contentType)
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, req)
+
 		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("error - missing token", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, refreshPath, bytes.NewBufferString(`{}`))
+		req.Header.Set(contentTypeHeader, contentType)
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestAuthHandlerLogout(t *testing.T) {
+	mockService := new(MockAuthService)
+	authHandler := handler.NewAuthHandler(mockService)
+	claims := &utils.Claims{UserID: 7}
+	claims.Id = "jti-1"
+
+	router := setupRouter()
+	router.POST(logoutPath, func(c *gin.Context) {
+		c.Set("claims", claims)
+	}, authHandler.Logout)
+
+	mockService.On("Logout", claims, "refresh").Return(nil).Once()
+
+	reqBody, _ := json.Marshal(model.LogoutRequest{RefreshToken: "refresh"})
+	req, _ := http.NewRequest(http.MethodPost, logoutPath, bytes.NewBuffer(reqBody))
+	req.Header.Set(contentTypeHeader, contentType)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandlerRevokeSessions(t *testing.T) {
+	mockService := new(MockAuthService)
+	authHandler := handler.NewAuthHandler(mockService)
+	router := setupRouter()
+	router.POST("/admin/users/:id/revoke-sessions", authHandler.RevokeSessions)
+
+	t.Run("success - revoke", func(t *testing.T) {
+		mockService.On("RevokeSessions", uint(3)).Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/admin/users/3/revoke-sessions", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("error - unknown user", func(t *testing.T) {
+		mockService.On("RevokeSessions", uint(4)).Return(service.ErrUserNotFound).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/admin/users/4/revoke-sessions", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
 }
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// bindJSON membaca body JSON ke request lalu menjalankan tag binding. Semua
+// field yang melanggar aturan dikembalikan sekaligus dalam satu error.
+func bindJSON(c *gin.Context, request interface{}) error {
+	return bindError(c.ShouldBindJSON(request))
+}
+
+func bindQuery(c *gin.Context, request interface{}) error {
+	return bindError(c.ShouldBindQuery(request))
+}
+
+func bindError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	invalid, ok := validation.Fields(err)
+	if !ok {
+		return errBadRequest
+	}
+	fields := make([]service.FieldError, 0, len(invalid))
+	for _, field := range invalid {
+		fields = append(fields, service.FieldError{Field: field.Field, Code: field.Code, Message: field.Message, Params: field.Params})
+	}
+	return validationFailed(fields...)
+}
@@ -1,39 +1,52 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/danisasmita/customer-search/internal/model"
 	"github.com/danisasmita/customer-search/internal/service"
-	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/metrics"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	defaultCustomerLimit = 20
+	maxCustomerLimit     = 100
+)
+
 type CustomerHandler struct {
 	service service.CustomerService
+	audit   service.AuditService
 }
 
-func NewCustomerHandler(service service.CustomerService) *CustomerHandler {
-	return &CustomerHandler{service: service}
+func NewCustomerHandler(service service.CustomerService, audit service.AuditService) *CustomerHandler {
+	return &CustomerHandler{service: service, audit: audit}
 }
 
 func (h *CustomerHandler) SearchByName(c *gin.Context) {
-	name := c.Query("name")
-	email := c.Query("email")
-	accountNumber := c.Query("account_number")
-
-	if name == "" && email == "" && accountNumber == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": message.SearchCustomer})
+	var request model.CustomerSearchRequest
+	if err := bindQuery(c, &request); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	customers, err := h.service.SearchByName(name, email, accountNumber)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": message.InternalServerError})
+	filter := model.CustomerFilter{
+		Name:          request.Name,
+		Email:         request.Email,
+		AccountNumber: request.AccountNumber,
+	}
+	customers, ok := h.search(c, "v1", filter, func() ([]model.Customer, error) {
+		return h.service.SearchByName(c.Request.Context(), filter.Name, filter.Email, filter.AccountNumber)
+	})
+	if !ok {
 		return
 	}
 
 	if len(customers) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": message.CustomerNotFound})
+		respondError(c, service.ErrCustomerNotFound)
 		return
 	}
 
@@ -41,3 +54,153 @@ func (h *CustomerHandler) SearchByName(c *gin.Context) {
 		"data": customers,
 	})
 }
+
+// SearchV2 mengembalikan hasil per halaman dengan nominal uang sebagai
+// string. Hasil kosong dijawab 200 dengan data kosong, bukan 404.
+func (h *CustomerHandler) SearchV2(c *gin.Context) {
+	var request model.CustomerPageRequest
+	if err := bindQuery(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	filter := model.CustomerFilter{
+		Name:          request.Name,
+		Email:         request.Email,
+		AccountNumber: request.AccountNumber,
+		Limit:         request.Limit,
+		Offset:        request.Offset,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultCustomerLimit
+	}
+	filter.Limit = min(filter.Limit, maxCustomerLimit)
+
+	var total int64
+	customers, ok := h.search(c, "v2", filter, func() (customers []model.Customer, err error) {
+		customers, total, err = h.service.Search(c.Request.Context(), filter)
+		return customers, err
+	})
+	if !ok {
+		return
+	}
+
+	data := make([]model.CustomerResponse, 0, len(customers))
+	for i := range customers {
+		data = append(data, model.NewCustomerResponse(&customers[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":       data,
+		"pagination": model.Pagination{Limit: filter.Limit, Offset: filter.Offset, Total: total},
+	})
+}
+
+// search menjalankan pencarian lalu mencatatnya di audit log. Jika audit
+// gagal ditulis, data tidak boleh dikembalikan. ok false berarti respons
+// error sudah ditulis. version menjadi label metrik jumlah hasil.
+func (h *CustomerHandler) search(c *gin.Context, version string, filter model.CustomerFilter, find func() ([]model.Customer, error)) ([]model.Customer, bool) {
+	name, email, accountNumber := filter.Name, filter.Email, filter.AccountNumber
+	if name == "" && email == "" && accountNumber == "" {
+		respondError(c, errSearchCriteria)
+		return nil, false
+	}
+
+	customers, err := find()
+
+	// Setiap akses data nasabah wajib tercatat.
+	params := map[string]string{}
+	if name != "" {
+		params["name"] = name
+	}
+	if email != "" {
+		params["email"] = email
+	}
+	if accountNumber != "" {
+		params["account_number"] = accountNumber
+	}
+	if auditErr := h.recordAccess(c, model.AuditActionCustomerSearch, params, customers); auditErr != nil {
+		respondError(c, auditErr)
+		return nil, false
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	metrics.SearchResults.WithLabelValues(version).Observe(float64(len(customers)))
+	return customers, true
+}
+
+func (h *CustomerHandler) GetByID(c *gin.Context) {
+	customer, ok := h.find(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": customer,
+	})
+}
+
+func (h *CustomerHandler) GetByIDV2(c *gin.Context) {
+	customer, ok := h.find(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": model.NewCustomerResponse(customer),
+	})
+}
+
+// find mengambil customer dari parameter :id dan mencatat aksesnya.
+func (h *CustomerHandler) find(c *gin.Context) (*model.Customer, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, errInvalidCustomerID)
+		return nil, false
+	}
+
+	customer, err := h.service.GetByID(c.Request.Context(), uint(id))
+
+	var found []model.Customer
+	if customer != nil {
+		found = append(found, *customer)
+	}
+	params := map[string]string{"id": c.Param("id")}
+	if auditErr := h.recordAccess(c, model.AuditActionCustomerView, params, found); auditErr != nil {
+		respondError(c, auditErr)
+		return nil, false
+	}
+
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	return customer, true
+}
+
+// recordAccess menulis audit log untuk akses data nasabah, termasuk alasan
+// akses dan penanda break-glass dari middleware RequireAccessReason.
+func (h *CustomerHandler) recordAccess(c *gin.Context, action string, params map[string]string, customers []model.Customer) error {
+	query, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(customers))
+	for _, customer := range customers {
+		ids = append(ids, strconv.FormatUint(uint64(customer.ID), 10))
+	}
+
+	return h.audit.Record(&model.AuditLog{
+		UserID:         c.GetUint("userID"),
+		ActorID:        c.GetUint("actorID"),
+		Action:         action,
+		Query:          string(query),
+		ResultIDs:      strings.Join(ids, ","),
+		ClientIP:       c.ClientIP(),
+		Reason:         c.GetString("accessReason"),
+		ReviewRequired: c.GetBool("breakGlass"),
+	})
+}
@@ -1,14 +1,21 @@
 package handler_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/danisasmita/customer-search/internal/handler"
 	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/logging"
 	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/problem"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -23,11 +30,43 @@ type MockCustomerService struct {
 	mock.Mock
 }
 
-func (m *MockCustomerService) SearchByName(name, email, accountNumber string) ([]model.Customer, error) {
+func (m *MockCustomerService) SearchByName(ctx context.Context, name, email, accountNumber string) ([]model.Customer, error) {
 	args := m.Called(name, email, accountNumber)
 	return args.Get(0).([]model.Customer), args.Error(1)
 }
 
+// MockAuditService adalah mock untuk service.AuditService
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) Record(entry *model.AuditLog) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditService) Query(filter model.AuditFilter) ([]model.AuditLog, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]model.AuditLog), args.Error(1)
+}
+
+func (m *MockAuditService) Verify() (*model.AuditVerification, error) {
+	args := m.Called()
+	return args.Get(0).(*model.AuditVerification), args.Error(1)
+}
+
+func (m *MockCustomerService) Search(ctx context.Context, filter model.CustomerFilter) ([]model.Customer, int64, error) {
+	args := m.Called(filter)
+	customers, _ := args.Get(0).([]model.Customer)
+	return customers, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCustomerService) GetByID(ctx context.Context, id uint) (*model.Customer, error) {
+	args := m.Called(id)
+	customer, _ := args.Get(0).(*model.Customer)
+	return customer, args.Error(1)
+}
+
 func setRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	return gin.Default()
@@ -40,8 +79,12 @@ type MockCustomerRepository struct {
 
 func TestCustomerHandlerSearchByName(t *testing.T) {
 	mockService := new(MockCustomerService)
-	customerHandler := handler.NewCustomerHandler(mockService)
+	mockAudit := new(MockAuditService)
+	customerHandler := handler.NewCustomerHandler(mockService, mockAudit)
 	router := setupRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(7))
+	})
 	router.GET("/search", customerHandler.SearchByName)
 
 	t.Run("success - search by name", func(t *testing.T) {
@@ -55,7 +98,14 @@ func TestCustomerHandlerSearchByName(t *testing.T) {
 				},
 			},
 		}
-		mockService.On("SearchByName", "John", "", "").Return(mockCustomers, nil)
+		mockCustomers[0].ID = 42
+		mockService.On("SearchByName", "John", "", "").Return(mockCustomers, nil).Once()
+		mockAudit.On("Record", mock.MatchedBy(func(entry *model.AuditLog) bool {
+			return entry.UserID == 7 &&
+				entry.Action == model.AuditActionCustomerSearch &&
+				entry.Query == `{"name":"John"}` &&
+				entry.ResultIDs == "42"
+		})).Return(nil).Once()
 
 		// Buat request
 		req, _ := http.NewRequest(http.MethodGet, searchEndpoint, nil)
@@ -78,6 +128,34 @@ func TestCustomerHandlerSearchByName(t *testing.T) {
 		assert.Equal(t, mockCustomers, response.Data)
 
 		mockService.AssertExpectations(t)
+		mockAudit.AssertExpectations(t)
+	})
+
+	t.Run("error - audit log unavailable", func(t *testing.T) {
+		mockService.On("SearchByName", "John", "", "").Return([]model.Customer{{Name: "John Doe"}}, nil).Once()
+		mockAudit.On("Record", mock.AnythingOfType("*model.AuditLog")).Return(errors.New("db down")).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, searchEndpoint, nil)
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, req)
+
+		// Data tidak boleh keluar jika akses tidak bisa dicatat
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.NotContains(t, recorder.Body.String(), "John Doe")
+		mockAudit.AssertExpectations(t)
+	})
+
+	t.Run("success - partial email", func(t *testing.T) {
+		mockService.On("SearchByName", "", "john", "").Return([]model.Customer{{Name: "John Doe", Email: "john@example.com"}}, nil).Once()
+		mockAudit.On("Record", mock.AnythingOfType("*model.AuditLog")).Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/search?email=john", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		mockService.AssertExpectations(t)
 	})
 
 	t.Run("error - no query parameters provided", func(t *testing.T) {
@@ -94,6 +172,194 @@ func TestCustomerHandlerSearchByName(t *testing.T) {
 		var responseBody gin.H
 		err := json.Unmarshal(recorder.Body.Bytes(), &responseBody)
 		assert.NoError(t, err)
-		assert.Equal(t, gin.H{"error": message.SearchCustomer}, responseBody)
+		assert.Equal(t, message.CodeSearchCriteria, responseBody["code"])
+		assert.Equal(t, message.SearchCustomer, responseBody["detail"])
+	})
+
+	t.Run("error - invalid query parameters", func(t *testing.T) {
+		email := strings.Repeat("a", 255)
+		req, _ := http.NewRequest(http.MethodGet, "/search?email="+email+"&account_number=12-34", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var body problem.Problem
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		assert.Equal(t, message.CodeValidationFailed, body.Code)
+		assert.Equal(t, []problem.FieldError{
+			{Field: "email", Code: "max", Message: "email must have a length of at most 254"},
+			{Field: "account_number", Code: "account_number", Message: "account_number must be 10 to 16 digits"},
+		}, body.Errors)
+		mockService.AssertNotCalled(t, "SearchByName", "", email, "12-34")
+	})
+}
+
+func TestCustomerHandlerGetByID(t *testing.T) {
+	mockService := new(MockCustomerService)
+	mockAudit := new(MockAuditService)
+	customerHandler := handler.NewCustomerHandler(mockService, mockAudit)
+	router := setupRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(7))
+		c.Set("accessReason", "CUSTOMER_REQUEST")
 	})
+	router.GET("/customers/:id", customerHandler.GetByID)
+
+	t.Run("success - audited with reason", func(t *testing.T) {
+		customer := &model.Customer{Name: "John Doe"}
+		customer.ID = 5
+		mockService.On("GetByID", uint(5)).Return(customer, nil).Once()
+		mockAudit.On("Record", mock.MatchedBy(func(entry *model.AuditLog) bool {
+			return entry.Action == model.AuditActionCustomerView &&
+				entry.Reason == "CUSTOMER_REQUEST" &&
+				entry.ResultIDs == "5" &&
+				!entry.ReviewRequired
+		})).Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/customers/5", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		mockService.AssertExpectations(t)
+		mockAudit.AssertExpectations(t)
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		mockService.On("GetByID", uint(9)).Return(nil, service.ErrCustomerNotFound).Once()
+		mockAudit.On("Record", mock.MatchedBy(func(entry *model.AuditLog) bool {
+			return entry.ResultIDs == ""
+		})).Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/customers/9", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		mockAudit.AssertExpectations(t)
+	})
+
+	t.Run("error - invalid id", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/customers/abc", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestCustomerHandlerInternalErrorLogged(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.Config{})
+	assert.NoError(t, err)
+
+	mockService := new(MockCustomerService)
+	mockAudit := new(MockAuditService)
+	customerHandler := handler.NewCustomerHandler(mockService, mockAudit)
+	router := setupRouter()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), logger))
+		c.Set("userID", uint(7))
+	})
+	router.GET("/customers/:id", customerHandler.GetByID)
+
+	mockService.On("GetByID", uint(5)).Return(nil, errors.New("lookup for jane@example.com failed")).Once()
+	mockAudit.On("Record", mock.AnythingOfType("*model.AuditLog")).Return(nil).Maybe()
+
+	req, _ := http.NewRequest(http.MethodGet, "/customers/5", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	// Detail error hanya ada di log, dan data nasabah di dalamnya disembunyikan.
+	assert.NotContains(t, recorder.Body.String(), "lookup")
+	assert.Contains(t, buf.String(), `"msg":"request failed"`)
+	assert.Contains(t, buf.String(), `"route":"/customers/:id"`)
+	assert.Contains(t, buf.String(), `"error":"lookup for [REDACTED] failed"`)
+}
+
+func TestCustomerHandlerSearchV2(t *testing.T) {
+	mockService := new(MockCustomerService)
+	mockAudit := new(MockAuditService)
+	customerHandler := handler.NewCustomerHandler(mockService, mockAudit)
+	router := setupRouter()
+	router.GET("/v2/customers", customerHandler.SearchV2)
+
+	t.Run("success - paginated with money as strings", func(t *testing.T) {
+		customer := model.Customer{
+			Name:         "John Doe",
+			BankAccounts: []model.BankAccount{{AccountNumber: "1234567890"}},
+			Pockets:      []model.Pocket{{Name: "Holiday", Balance: 1500.5}},
+			TermDeposits: []model.TermDeposit{{Amount: 10000, Duration: 12}},
+		}
+		customer.ID = 42
+		mockService.On("Search", model.CustomerFilter{Name: "John", Limit: 10, Offset: 20}).
+			Return([]model.Customer{customer}, int64(21), nil).Once()
+		mockAudit.On("Record", mock.MatchedBy(func(entry *model.AuditLog) bool {
+			return entry.ResultIDs == "42"
+		})).Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/v2/customers?name=John&limit=10&offset=20", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{
+			"data": [{
+				"id": 42, "tenant_id": "", "name": "John Doe", "email": "",
+				"bank_accounts": [{"account_number": "1234567890"}],
+				"pockets": [{"name": "Holiday", "balance": "1500.50"}],
+				"term_deposits": [{"amount": "10000.00", "duration": 12}]
+			}],
+			"pagination": {"limit": 10, "offset": 20, "total": 21}
+		}`, recorder.Body.String())
+		mockService.AssertExpectations(t)
+		mockAudit.AssertExpectations(t)
+	})
+
+	t.Run("success - empty page uses default limit", func(t *testing.T) {
+		mockService.On("Search", model.CustomerFilter{Name: "Nobody", Limit: 20}).
+			Return([]model.Customer{}, int64(0), nil).Once()
+		mockAudit.On("Record", mock.AnythingOfType("*model.AuditLog")).Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/v2/customers?name=Nobody", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"data": [], "pagination": {"limit": 20, "offset": 0, "total": 0}}`, recorder.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("error - limit too large", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/v2/customers?name=John&limit=1000", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"field":"limit"`)
+	})
+}
+
+func TestCustomerHandlerGetByIDV2(t *testing.T) {
+	mockService := new(MockCustomerService)
+	mockAudit := new(MockAuditService)
+	customerHandler := handler.NewCustomerHandler(mockService, mockAudit)
+	router := setupRouter()
+	router.GET("/v2/customers/:id", customerHandler.GetByIDV2)
+
+	customer := &model.Customer{Name: "John Doe", Pockets: []model.Pocket{{Name: "Main", Balance: 0.1}}}
+	customer.ID = 5
+	mockService.On("GetByID", uint(5)).Return(customer, nil).Once()
+	mockAudit.On("Record", mock.AnythingOfType("*model.AuditLog")).Return(nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/v2/customers/5", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"pockets":[{"name":"Main","balance":"0.10"}]`)
+	assert.Contains(t, recorder.Body.String(), `"bank_accounts":[]`)
+	mockAudit.AssertExpectations(t)
 }
@@ -0,0 +1,36 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/api"
+	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPI(t *testing.T) {
+	router := setupRouter()
+	router.GET("/openapi.json", handler.OpenAPI)
+
+	req, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, string(api.Spec), recorder.Body.String())
+}
+
+func TestDocs(t *testing.T) {
+	router := setupRouter()
+	router.GET("/docs", handler.Docs)
+
+	req, _ := http.NewRequest(http.MethodGet, "/docs", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `url: "/openapi.json"`)
+}
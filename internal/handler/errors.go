@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// Error milik lapisan HTTP. errBadRequest dipakai saat body atau parameter
+// path tidak bisa dibaca.
+var (
+	errBadRequest         = service.NewError(service.ErrValidation, message.CodeBadRequest, message.BadRequest)
+	errUnauthorized       = service.NewError(service.ErrUnauthorized, message.CodeUnauthorized, message.Unauthorized)
+	errRegistrationClosed = service.NewError(service.ErrForbidden, message.CodeRegistrationClosed, message.RegistrationClosed)
+	errInvalidCustomerID  = service.NewError(service.ErrValidation, message.CodeInvalidCustomerID, message.InvalidCustomerID)
+	errSearchCriteria     = service.NewError(service.ErrValidation, message.CodeSearchCriteria, message.SearchCustomer)
+	errInvalidAuditFilter = service.NewError(service.ErrValidation, message.CodeInvalidAuditFilter, message.InvalidAuditFilter)
+	errInvalidUserFilter  = service.NewError(service.ErrValidation, message.CodeInvalidUserFilter, message.InvalidUserFilter)
+)
+
+func validationFailed(fields ...service.FieldError) error {
+	return service.NewValidationError(message.CodeValidationFailed, message.ValidationFailed, fields...)
+}
+
+var kindStatus = map[error]int{
+	service.ErrValidation:   http.StatusBadRequest,
+	service.ErrUnauthorized: http.StatusUnauthorized,
+	service.ErrForbidden:    http.StatusForbidden,
+	service.ErrNotFound:     http.StatusNotFound,
+	service.ErrConflict:     http.StatusConflict,
+}
+
+// respondError memetakan error service ke respons problem+json. Status
+// ditentukan oleh jenis error, bukan oleh masing-masing handler. Error yang
+// tidak dikenal dicatat di log dan dijawab 500 tanpa detail.
+func respondError(c *gin.Context, err error) {
+	problem.Write(c, toProblem(c, err))
+}
+
+func toProblem(c *gin.Context, err error) *problem.Problem {
+	var throttled *service.LoginThrottledError
+	var policyErr *service.PasswordPolicyError
+	var domainErr *service.Error
+
+	switch {
+	case errors.As(err, &throttled):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		return problem.New(http.StatusTooManyRequests, message.CodeTooManyLoginAttempts, message.TooManyLoginAttempts)
+
+	case errors.As(err, &policyErr):
+		p := problem.New(http.StatusBadRequest, message.CodePasswordPolicy, message.PasswordPolicyViolation)
+		for _, failure := range policyErr.Failures {
+			p.Errors = append(p.Errors, problem.FieldError{Field: "password", Code: failure.Rule, Message: failure.Message, Params: failure.Params})
+		}
+		return p
+
+	case errors.As(err, &domainErr):
+		status, ok := kindStatus[domainErr.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		p := problem.New(status, domainErr.Code, domainErr.Message)
+		for _, field := range domainErr.Fields {
+			p.Errors = append(p.Errors, problem.FieldError{Field: field.Field, Code: field.Code, Message: field.Message, Params: field.Params})
+		}
+		p.Extensions = domainErr.Details
+		return p
+
+	default:
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).ErrorContext(ctx, "request failed", "method", c.Request.Method, "route", c.FullPath(), "error", err)
+		return problem.New(http.StatusInternalServerError, message.CodeInternal, message.InternalServerError)
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danisasmita/customer-search/pkg/health"
+	"github.com/danisasmita/customer-search/pkg/version"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Live hanya menandakan proses masih melayani request; dependency tidak
+// diperiksa agar gangguan database tidak membuat pod di-restart.
+func Live(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Ready menjawab 503 selama salah satu pemeriksaan gagal sehingga pod
+// dikeluarkan dari load balancer.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.checker.Run(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, report)
+}
+
+func Version(c *gin.Context) {
+	c.JSON(http.StatusOK, version.Get())
+}
@@ -0,0 +1,61 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/danisasmita/customer-search/pkg/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLive(t *testing.T) {
+	router := setupRouter()
+	router.GET("/healthz", handler.Live)
+
+	req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, recorder.Body.String())
+}
+
+func TestHealthHandlerReady(t *testing.T) {
+	databaseErr := error(nil)
+	checker := health.NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return databaseErr })
+
+	router := setupRouter()
+	router.GET("/readyz", handler.NewHealthHandler(checker).Ready)
+
+	req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"ok","checks":{"database":"ok"}}`, recorder.Body.String())
+
+	databaseErr = errors.New("connection refused")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.JSONEq(t, `{"status":"unavailable","checks":{"database":"connection refused"}}`, recorder.Body.String())
+}
+
+func TestVersion(t *testing.T) {
+	router := setupRouter()
+	router.GET("/version", handler.Version)
+
+	req, _ := http.NewRequest(http.MethodGet, "/version", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"go_version":"go`)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ImpersonationHandler struct {
+	service service.ImpersonationService
+}
+
+func NewImpersonationHandler(service service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{service: service}
+}
+
+func (h *ImpersonationHandler) Start(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, errBadRequest)
+		return
+	}
+
+	var request model.ImpersonateRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response, err := h.service.Start(c.GetUint("userID"), uint(userID), request.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
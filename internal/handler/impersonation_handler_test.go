@@ -0,0 +1,63 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockImpersonationService adalah mock untuk service.ImpersonationService
+type MockImpersonationService struct {
+	mock.Mock
+}
+
+func (m *MockImpersonationService) Start(adminID, userID uint, reason string) (*model.ImpersonationResponse, error) {
+	args := m.Called(adminID, userID, reason)
+	response, _ := args.Get(0).(*model.ImpersonationResponse)
+	return response, args.Error(1)
+}
+
+func TestImpersonationHandlerStart(t *testing.T) {
+	mockService := new(MockImpersonationService)
+	impersonationHandler := handler.NewImpersonationHandler(mockService)
+
+	router := setupRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+	})
+	router.POST("/admin/users/:id/impersonate", impersonationHandler.Start)
+
+	mockService.On("Start", uint(1), uint(2), "ticket 42").Return(&model.ImpersonationResponse{AccessToken: "token", ExpiresIn: 900, UserID: 2}, nil).Once()
+	mockService.On("Start", uint(1), uint(3), "ticket 42").Return(nil, service.ErrCannotImpersonate).Once()
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "success", path: "/admin/users/2/impersonate", expectedStatus: http.StatusOK},
+		{name: "higher privilege", path: "/admin/users/3/impersonate", expectedStatus: http.StatusForbidden},
+		{name: "invalid id", path: "/admin/users/abc/impersonate", expectedStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(model.ImpersonateRequest{Reason: "ticket 42"})
+			req, _ := http.NewRequest(http.MethodPost, tt.path, bytes.NewReader(body))
+			req.Header.Set(contentTypeHeader, contentType)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+	mockService.AssertExpectations(t)
+}
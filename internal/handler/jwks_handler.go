@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKS mempublikasikan kunci publik JWT agar service internal lain bisa
+// memverifikasi token yang kita terbitkan.
+func JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, utils.JWKS())
+}
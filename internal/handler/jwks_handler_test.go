@@ -0,0 +1,23 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWKS(t *testing.T) {
+	router := setupRouter()
+	router.GET("/.well-known/jwks.json", handler.JWKS)
+
+	req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	// Kunci HMAC bawaan tidak dipublikasikan
+	assert.JSONEq(t, `{"keys":[]}`, recorder.Body.String())
+}
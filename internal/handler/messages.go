@@ -0,0 +1,11 @@
+package handler
+
+import (
+	"github.com/danisasmita/customer-search/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// messageBody membuat respons {"message": ...} dalam bahasa request.
+func messageBody(c *gin.Context, key string) gin.H {
+	return gin.H{"message": i18n.T(c.GetString(i18n.ContextKey), key, nil)}
+}
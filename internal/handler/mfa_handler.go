@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	service service.MFAService
+}
+
+func NewMFAHandler(service service.MFAService) *MFAHandler {
+	return &MFAHandler{service: service}
+}
+
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	enrollment, err := h.service.Enroll(c.GetUint("userID"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	var request model.MFACodeRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	codes, err := h.service.Confirm(c.GetUint("userID"), request.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.MFARecoveryCodes{RecoveryCodes: codes})
+}
+
+// Disable dipakai admin untuk mereset MFA user lain. Sesi user ikut
+// dicabut.
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, errBadRequest)
+		return
+	}
+
+	if err := h.service.Disable(uint(userID), c.GetUint("userID")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, messageBody(c, message.KeyMFADisabled))
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oidcStateCookie = "oidc_state"
+	// oidcCookiePath "/" karena login dan callback bisa datang lewat prefix
+	// berbeda, misalnya /oidc/login lalu /v1/oidc/callback.
+	oidcCookiePath = "/"
+)
+
+type OIDCHandler struct {
+	service  service.OIDCService
+	stateTTL time.Duration
+}
+
+func NewOIDCHandler(service service.OIDCService, stateTTL time.Duration) *OIDCHandler {
+	return &OIDCHandler{service: service, stateTTL: stateTTL}
+}
+
+// Login mengarahkan browser ke identity provider. State juga disimpan di
+// cookie agar callback hanya diterima dari browser yang memulai login.
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authURL, state, err := h.service.Begin()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.setStateCookie(c, state, int(h.stateTTL.Seconds()))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	cookie, _ := c.Cookie(oidcStateCookie)
+	h.setStateCookie(c, "", -1)
+
+	if c.Query("error") != "" {
+		respondError(c, service.ErrOIDCLoginFailed)
+		return
+	}
+
+	state := c.Query("state")
+	if cookie == "" || cookie != state {
+		respondError(c, service.ErrInvalidOIDCState)
+		return
+	}
+
+	response, err := h.service.Callback(c.Request.Context(), state, c.Query("code"))
+	metrics.Logins.WithLabelValues(metrics.LoginStepOIDC, loginResult(response, err)).Inc()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *OIDCHandler) setStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, value, maxAge, oidcCookiePath, "", c.Request.TLS != nil, true)
+}
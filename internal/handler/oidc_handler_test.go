@@ -0,0 +1,110 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOIDCService adalah mock untuk service.OIDCService
+type MockOIDCService struct {
+	mock.Mock
+}
+
+func (m *MockOIDCService) Begin() (string, string, error) {
+	args := m.Called()
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockOIDCService) Callback(ctx context.Context, state, code string) (*model.LoginResponse, error) {
+	args := m.Called(state, code)
+	response, _ := args.Get(0).(*model.LoginResponse)
+	return response, args.Error(1)
+}
+
+func TestOIDCHandler(t *testing.T) {
+	mockService := new(MockOIDCService)
+	oidcHandler := handler.NewOIDCHandler(mockService, 10*time.Minute)
+
+	router := setupRouter()
+	router.GET("/oidc/login", oidcHandler.Login)
+	router.GET("/oidc/callback", oidcHandler.Callback)
+
+	mockService.On("Begin").Return("https://idp.example.com/authorize?state=abc", "abc", nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/oidc/login", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusFound, recorder.Code)
+	assert.Equal(t, "https://idp.example.com/authorize?state=abc", recorder.Header().Get("Location"))
+	cookies := recorder.Result().Cookies()
+	if assert.Len(t, cookies, 1) {
+		assert.Equal(t, "abc", cookies[0].Value)
+		assert.Equal(t, "/", cookies[0].Path)
+		assert.True(t, cookies[0].HttpOnly)
+	}
+
+	tests := []struct {
+		name           string
+		cookie         string
+		query          string
+		setup          func()
+		expectedStatus int
+	}{
+		{
+			name:           "state mismatch",
+			cookie:         "other",
+			query:          "state=abc&code=xyz",
+			setup:          func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "provider error",
+			cookie:         "abc",
+			query:          "error=access_denied&state=abc",
+			setup:          func() {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "no mapped role",
+			cookie: "abc",
+			query:  "state=abc&code=xyz",
+			setup: func() {
+				mockService.On("Callback", "abc", "xyz").Return(nil, service.ErrNoMappedRole).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "success",
+			cookie: "abc",
+			query:  "state=abc&code=xyz",
+			setup: func() {
+				mockService.On("Callback", "abc", "xyz").Return(&model.LoginResponse{AccessToken: "access", ExpiresIn: 900}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req, _ := http.NewRequest(http.MethodGet, "/oidc/callback?"+tt.query, nil)
+			req.AddCookie(&http.Cookie{Name: "oidc_state", Value: tt.cookie})
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+	mockService.AssertExpectations(t)
+}
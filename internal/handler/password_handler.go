@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordHandler struct {
+	service service.PasswordService
+}
+
+func NewPasswordHandler(service service.PasswordService) *PasswordHandler {
+	return &PasswordHandler{service: service}
+}
+
+func (h *PasswordHandler) Change(c *gin.Context) {
+	var request model.ChangePasswordRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	tokens, err := h.service.Change(c.GetUint("userID"), request.OldPassword, request.NewPassword, c.ClientIP())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *PasswordHandler) AdminReset(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, errBadRequest)
+		return
+	}
+
+	if err := h.service.AdminReset(uint(userID), c.GetUint("userID")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, messageBody(c, message.KeyPasswordResetIssued))
+}
+
+// Forgot selalu menjawab 202 agar tidak membocorkan username yang terdaftar,
+// kecuali saat permintaan dari username atau IP yang sama terlalu sering.
+func (h *PasswordHandler) Forgot(c *gin.Context) {
+	var request model.ForgotPasswordRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := h.service.RequestReset(request.Username, c.ClientIP()); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, messageBody(c, message.KeyPasswordResetSent))
+}
+
+func (h *PasswordHandler) Reset(c *gin.Context) {
+	var request model.ResetPasswordRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := h.service.Reset(request.Token, request.NewPassword); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, messageBody(c, message.KeyPasswordChanged))
+}
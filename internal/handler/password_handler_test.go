@@ -0,0 +1,140 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPasswordService adalah mock untuk service.PasswordService
+type MockPasswordService struct {
+	mock.Mock
+}
+
+func (m *MockPasswordService) Change(userID uint, oldPassword, newPassword, clientIP string) (*model.LoginResponse, error) {
+	args := m.Called(userID, oldPassword, newPassword, clientIP)
+	tokens, _ := args.Get(0).(*model.LoginResponse)
+	return tokens, args.Error(1)
+}
+
+func (m *MockPasswordService) AdminReset(userID, adminID uint) error {
+	args := m.Called(userID, adminID)
+	return args.Error(0)
+}
+
+func (m *MockPasswordService) RequestReset(username, clientIP string) error {
+	args := m.Called(username, clientIP)
+	return args.Error(0)
+}
+
+func (m *MockPasswordService) Reset(token, newPassword string) error {
+	args := m.Called(token, newPassword)
+	return args.Error(0)
+}
+
+func postJSON(router *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+	reqBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(reqBody))
+	req.Header.Set(contentTypeHeader, contentType)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestPasswordHandlerChange(t *testing.T) {
+	mockService := new(MockPasswordService)
+	passwordHandler := handler.NewPasswordHandler(mockService)
+	router := setupRouter()
+	router.POST("/me/password", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+	}, passwordHandler.Change)
+
+	t.Run("success", func(t *testing.T) {
+		mockService.On("Change", uint(1), "OldPassword1", "NewPassword1", mock.Anything).
+			Return(&model.LoginResponse{AccessToken: "fresh"}, nil).Once()
+
+		recorder := postJSON(router, "/me/password", model.ChangePasswordRequest{OldPassword: "OldPassword1", NewPassword: "NewPassword1"})
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "fresh")
+	})
+
+	t.Run("error - wrong old password", func(t *testing.T) {
+		mockService.On("Change", uint(1), "wrong", "NewPassword1", mock.Anything).Return(nil, service.ErrInvalidPassword).Once()
+
+		recorder := postJSON(router, "/me/password", model.ChangePasswordRequest{OldPassword: "wrong", NewPassword: "NewPassword1"})
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.Run("error - password policy", func(t *testing.T) {
+		mockService.On("Change", uint(1), "OldPassword1", "1", mock.Anything).Return(nil, &service.PasswordPolicyError{
+			Failures: []service.PasswordRuleFailure{{Rule: service.PasswordRuleMinLength}},
+		}).Once()
+
+		recorder := postJSON(router, "/me/password", model.ChangePasswordRequest{OldPassword: "OldPassword1", NewPassword: "1"})
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"code":"password_policy"`)
+		assert.Contains(t, recorder.Body.String(), `{"field":"password","code":"min_length"`)
+	})
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPasswordHandlerForgotAndReset(t *testing.T) {
+	mockService := new(MockPasswordService)
+	passwordHandler := handler.NewPasswordHandler(mockService)
+	router := setupRouter()
+	router.POST("/password/forgot", passwordHandler.Forgot)
+	router.POST("/password/reset", passwordHandler.Reset)
+
+	mockService.On("RequestReset", "ghost", mock.Anything).Return(nil).Once()
+	recorder := postJSON(router, "/password/forgot", model.ForgotPasswordRequest{Username: "ghost"})
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+
+	mockService.On("RequestReset", "john_doe", mock.Anything).
+		Return(&service.LoginThrottledError{RetryAfter: time.Minute}).Once()
+	recorder = postJSON(router, "/password/forgot", model.ForgotPasswordRequest{Username: "john_doe"})
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "60", recorder.Header().Get("Retry-After"))
+
+	mockService.On("Reset", "expired", "NewPassword1").Return(service.ErrInvalidResetToken).Once()
+	recorder = postJSON(router, "/password/reset", model.ResetPasswordRequest{Token: "expired", NewPassword: "NewPassword1"})
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	mockService.On("Reset", "valid", "NewPassword1").Return(nil).Once()
+	recorder = postJSON(router, "/password/reset", model.ResetPasswordRequest{Token: "valid", NewPassword: "NewPassword1"})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPasswordHandlerAdminReset(t *testing.T) {
+	mockService := new(MockPasswordService)
+	passwordHandler := handler.NewPasswordHandler(mockService)
+	router := setupRouter()
+	router.POST("/admin/users/:id/password-reset", func(c *gin.Context) {
+		c.Set("userID", uint(9))
+	}, passwordHandler.AdminReset)
+
+	mockService.On("AdminReset", uint(2), uint(9)).Return(nil).Once()
+	recorder := postJSON(router, "/admin/users/2/password-reset", nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	mockService.On("AdminReset", uint(3), uint(9)).Return(service.ErrUserNotFound).Once()
+	recorder = postJSON(router, "/admin/users/3/password-reset", nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = postJSON(router, "/admin/users/abc/password-reset", nil)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	mockService.AssertExpectations(t)
+}
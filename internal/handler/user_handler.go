@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultUserLimit = 50
+	maxUserLimit     = 500
+)
+
+type UserHandler struct {
+	service service.UserService
+}
+
+func NewUserHandler(service service.UserService) *UserHandler {
+	return &UserHandler{service: service}
+}
+
+func (h *UserHandler) List(c *gin.Context) {
+	filter, err := parseUserFilter(c)
+	if err != nil {
+		respondError(c, errInvalidUserFilter)
+		return
+	}
+
+	users, total, err := h.service.List(filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  users,
+		"total": total,
+	})
+}
+
+func (h *UserHandler) Create(c *gin.Context) {
+	var request model.CreateUserRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	user, err := h.service.Create(request, c.GetUint("userID"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": user})
+}
+
+func (h *UserHandler) Disable(c *gin.Context) {
+	h.setDisabled(c, true)
+}
+
+func (h *UserHandler) Enable(c *gin.Context) {
+	h.setDisabled(c, false)
+}
+
+func (h *UserHandler) AssignRole(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, errBadRequest)
+		return
+	}
+
+	var request model.AssignRoleRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	user, err := h.service.AssignRole(uint(userID), request.Role, c.GetUint("userID"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+func (h *UserHandler) AssignTenant(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, errBadRequest)
+		return
+	}
+
+	var request model.AssignTenantRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	user, err := h.service.AssignTenant(uint(userID), request.TenantID, c.GetUint("userID"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+func (h *UserHandler) Delete(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, errBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(uint(userID), c.GetUint("userID")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, messageBody(c, message.KeyUserDeleted))
+}
+
+// SetLanguage mengubah bahasa pesan API untuk user yang sedang login.
+func (h *UserHandler) SetLanguage(c *gin.Context) {
+	var request model.LanguageRequest
+	if err := bindJSON(c, &request); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	user, err := h.service.SetLanguage(c.GetUint("userID"), request.Language)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+func (h *UserHandler) setDisabled(c *gin.Context, disabled bool) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, errBadRequest)
+		return
+	}
+
+	user, err := h.service.SetDisabled(uint(userID), disabled, c.GetUint("userID"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
+
+func parseUserFilter(c *gin.Context) (model.UserFilter, error) {
+	filter := model.UserFilter{
+		Query: c.Query("q"),
+		Role:  c.Query("role"),
+		Limit: defaultUserLimit,
+	}
+
+	if v := c.Query("disabled"); v != "" {
+		disabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, err
+		}
+		filter.Disabled = &disabled
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, errors.New(message.InvalidUserFilter)
+		}
+		filter.Limit = min(limit, maxUserLimit)
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return filter, errors.New(message.InvalidUserFilter)
+		}
+		filter.Offset = offset
+	}
+	return filter, nil
+}
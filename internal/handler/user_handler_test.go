@@ -0,0 +1,233 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/handler"
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUserService adalah mock untuk service.UserService
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) List(filter model.UserFilter) ([]model.User, int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]model.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserService) Create(request model.CreateUserRequest, adminID uint) (*model.User, error) {
+	args := m.Called(request, adminID)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserService) SetDisabled(userID uint, disabled bool, adminID uint) (*model.User, error) {
+	args := m.Called(userID, disabled, adminID)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserService) AssignRole(userID uint, role string, adminID uint) (*model.User, error) {
+	args := m.Called(userID, role, adminID)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserService) AssignTenant(userID uint, tenantID string, adminID uint) (*model.User, error) {
+	args := m.Called(userID, tenantID, adminID)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserService) Delete(userID, adminID uint) error {
+	args := m.Called(userID, adminID)
+	return args.Error(0)
+}
+
+func (m *MockUserService) SetLanguage(userID uint, language string) (*model.User, error) {
+	args := m.Called(userID, language)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserService) Bootstrap(username, password, tenantID string) (*model.User, error) {
+	args := m.Called(username, password, tenantID)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func setupUserRouter(mockService *MockUserService) *gin.Engine {
+	userHandler := handler.NewUserHandler(mockService)
+	router := setupRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+	})
+	router.GET("/admin/users", userHandler.List)
+	router.POST("/admin/users", userHandler.Create)
+	router.POST("/admin/users/:id/disable", userHandler.Disable)
+	router.PUT("/admin/users/:id/role", userHandler.AssignRole)
+	router.PUT("/admin/users/:id/tenant", userHandler.AssignTenant)
+	router.DELETE("/admin/users/:id", userHandler.Delete)
+	router.PUT("/me/language", userHandler.SetLanguage)
+	return router
+}
+
+func TestUserHandlerList(t *testing.T) {
+	mockService := new(MockUserService)
+	router := setupUserRouter(mockService)
+
+	disabled := true
+	users := []model.User{{Username: "agent", Password: "$argon2id$secret", Role: model.RoleAgent, Disabled: true}}
+	mockService.On("List", model.UserFilter{Query: "ag", Disabled: &disabled, Limit: 10}).Return(users, int64(1), nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/admin/users?q=ag&disabled=true&limit=10", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "password")
+	assert.NotContains(t, recorder.Body.String(), "argon2id")
+
+	var response struct {
+		Data  []model.User `json:"data"`
+		Total int64        `json:"total"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, int64(1), response.Total)
+	mockService.AssertExpectations(t)
+
+	req, _ = http.NewRequest(http.MethodGet, "/admin/users?limit=abc", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestUserHandlerMutations(t *testing.T) {
+	mockService := new(MockUserService)
+	router := setupUserRouter(mockService)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           interface{}
+		setup          func()
+		expectedStatus int
+	}{
+		{
+			name:   "create",
+			method: http.MethodPost,
+			path:   "/admin/users",
+			body:   model.CreateUserRequest{Username: "batch_job", Password: "LongPassword1"},
+			setup: func() {
+				mockService.On("Create", model.CreateUserRequest{Username: "batch_job", Password: "LongPassword1"}, uint(1)).
+					Return(&model.User{Username: "batch_job"}, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "create - duplicate",
+			method: http.MethodPost,
+			path:   "/admin/users",
+			body:   model.CreateUserRequest{Username: "taken", Password: "LongPassword1"},
+			setup: func() {
+				mockService.On("Create", model.CreateUserRequest{Username: "taken", Password: "LongPassword1"}, uint(1)).
+					Return(nil, service.ErrUsernameTaken).Once()
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "disable",
+			method: http.MethodPost,
+			path:   "/admin/users/2/disable",
+			setup: func() {
+				mockService.On("SetDisabled", uint(2), true, uint(1)).Return(&model.User{Disabled: true}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "disable - self",
+			method: http.MethodPost,
+			path:   "/admin/users/1/disable",
+			setup: func() {
+				mockService.On("SetDisabled", uint(1), true, uint(1)).Return(nil, service.ErrSelfModify).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "assign role - invalid",
+			method:         http.MethodPut,
+			path:           "/admin/users/2/role",
+			body:           model.AssignRoleRequest{Role: "root"},
+			setup:          func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "assign tenant - invalid",
+			method:         http.MethodPut,
+			path:           "/admin/users/2/tenant",
+			body:           model.AssignTenantRequest{TenantID: "JKT SBY"},
+			setup:          func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "set language",
+			method: http.MethodPut,
+			path:   "/me/language",
+			body:   model.LanguageRequest{Language: "id"},
+			setup: func() {
+				mockService.On("SetLanguage", uint(1), "id").Return(&model.User{Language: "id"}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "set language - unsupported",
+			method:         http.MethodPut,
+			path:           "/me/language",
+			body:           model.LanguageRequest{Language: "fr"},
+			setup:          func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "delete - not found",
+			method: http.MethodDelete,
+			path:   "/admin/users/9",
+			setup: func() {
+				mockService.On("Delete", uint(9), uint(1)).Return(service.ErrUserNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			var recorder *httptest.ResponseRecorder
+			if tt.body != nil {
+				reqBody, _ := json.Marshal(tt.body)
+				req, _ := http.NewRequest(tt.method, tt.path, bytes.NewReader(reqBody))
+				req.Header.Set(contentTypeHeader, contentType)
+				recorder = httptest.NewRecorder()
+				router.ServeHTTP(recorder, req)
+			} else {
+				req, _ := http.NewRequest(tt.method, tt.path, nil)
+				recorder = httptest.NewRecorder()
+				router.ServeHTTP(recorder, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+	mockService.AssertExpectations(t)
+}
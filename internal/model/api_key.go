@@ -0,0 +1,91 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Scope API key. Request dengan API key hanya boleh memakai route yang
+// scope-nya dimiliki key tersebut.
+const (
+	ScopeCustomersRead = "customers:read"
+	ScopeAdmin         = "admin"
+)
+
+var APIKeyScopes = []string{ScopeCustomersRead, ScopeAdmin}
+
+func ValidScope(scope string) bool {
+	return slices.Contains(APIKeyScopes, scope)
+}
+
+// APIKey menyimpan hash SHA-256 dari key. Prefix disimpan apa adanya agar
+// admin bisa mengenali key tanpa melihat nilai lengkapnya. Request dengan
+// key ini berjalan atas nama UserID.
+type APIKey struct {
+	gorm.Model
+	Name       string     `json:"name"`
+	UserID     uint       `gorm:"index" json:"user_id"`
+	CreatedBy  uint       `json:"created_by"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `gorm:"uniqueIndex" json:"-"`
+	Scopes     string     `json:"-"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// ScopeList mengembalikan Scopes yang disimpan dipisah koma.
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return nil
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.ScopeList(), scope)
+}
+
+type (
+	// CreateAPIKeyRequest dengan ExpiresIn kosong membuat key tanpa
+	// kedaluwarsa. ExpiresIn memakai format durasi Go, misalnya 720h.
+	CreateAPIKeyRequest struct {
+		Name      string   `json:"name" binding:"required,max=100"`
+		UserID    uint     `json:"user_id"`
+		Scopes    []string `json:"scopes" binding:"required,min=1,dive,scope"`
+		ExpiresIn string   `json:"expires_in"`
+	}
+
+	// APIKeyResponse hanya berisi Key saat key baru dibuat.
+	APIKeyResponse struct {
+		ID         uint       `json:"id"`
+		Name       string     `json:"name"`
+		UserID     uint       `json:"user_id"`
+		CreatedBy  uint       `json:"created_by"`
+		Prefix     string     `json:"prefix"`
+		Scopes     []string   `json:"scopes"`
+		CreatedAt  time.Time  `json:"created_at"`
+		ExpiresAt  *time.Time `json:"expires_at"`
+		LastUsedAt *time.Time `json:"last_used_at"`
+		RevokedAt  *time.Time `json:"revoked_at"`
+		Key        string     `json:"key,omitempty"`
+	}
+)
+
+func NewAPIKeyResponse(key *APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		UserID:     key.UserID,
+		CreatedBy:  key.CreatedBy,
+		Prefix:     key.Prefix,
+		Scopes:     key.ScopeList(),
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
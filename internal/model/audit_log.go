@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+const (
+	AuditActionCustomerSearch = "customer.search"
+	AuditActionCustomerView   = "customer.view"
+	AuditActionLoginLockout   = "auth.lockout"
+	AuditActionAccountUnlock  = "auth.unlock"
+	AuditActionUserCreate     = "user.create"
+	AuditActionUserDisable    = "user.disable"
+	AuditActionUserEnable     = "user.enable"
+	AuditActionUserDelete     = "user.delete"
+	AuditActionUserRole       = "user.role"
+	AuditActionUserTenant     = "user.tenant"
+	AuditActionMFAReset       = "user.mfa_reset"
+	AuditActionPasswordReset  = "user.password_reset"
+	AuditActionAPIKeyCreate   = "apikey.create"
+	AuditActionAPIKeyRevoke   = "apikey.revoke"
+	AuditActionImpersonate    = "impersonation.start"
+	AuditActionImpersonated   = "impersonation.request"
+)
+
+// AuditLog adalah satu baris append-only pada audit trail. Setiap baris
+// menyimpan hash dari baris sebelumnya sehingga perubahan atau penghapusan
+// baris lama dapat dideteksi saat verifikasi.
+type AuditLog struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Seq       uint64    `gorm:"uniqueIndex;not null" json:"seq"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UserID    uint      `gorm:"index" json:"user_id"`
+	// ActorID adalah admin yang melakukan impersonation atas UserID.
+	ActorID   uint   `gorm:"index" json:"actor_id,omitempty"`
+	Action    string `gorm:"index" json:"action"`
+	Query     string `json:"query"`
+	ResultIDs string `json:"result_ids"`
+	ClientIP  string `json:"client_ip"`
+	Reason    string `json:"reason,omitempty"`
+	// ReviewRequired menandai akses break-glass yang harus ditinjau supervisor.
+	ReviewRequired bool   `gorm:"index" json:"review_required"`
+	PrevHash       string `json:"prev_hash"`
+	Hash           string `gorm:"uniqueIndex" json:"hash"`
+}
+
+// AuditFilter.UserID juga cocok dengan ActorID agar aktivitas admin
+// selama impersonation ikut tampil.
+type AuditFilter struct {
+	UserID         uint
+	Action         string
+	ReviewRequired bool
+	From           time.Time
+	To             time.Time
+	Limit          int
+}
+
+type AuditVerification struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	BrokenAt uint64 `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
@@ -1,10 +1,24 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"regexp"
+
+	"gorm.io/gorm"
+)
+
+var accountNumberPattern = regexp.MustCompile(`^[0-9]{10,16}$`)
+
+// ValidAccountNumber memeriksa format nomor rekening: 10 sampai 16 digit.
+func ValidAccountNumber(accountNumber string) bool {
+	return accountNumberPattern.MatchString(accountNumber)
+}
 
 type BankAccount struct {
 	gorm.Model
+	TenantID      string  `gorm:"index;size:64" json:"-"`
 	CustomerID    uint    `json:"customer_id"`
 	AccountNumber string  `json:"account_number"`
 	Balance       float64 `json:"balance"`
 }
+
+func (BankAccount) TenantOwned() {}
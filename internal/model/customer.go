@@ -1,9 +1,16 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"strconv"
 
+	"gorm.io/gorm"
+)
+
+// Customer dan produknya dimiliki satu cabang. Query atas model ini selalu
+// dibatasi ke cabang pemanggil oleh package tenant.
 type Customer struct {
 	gorm.Model
+	TenantID     string        `gorm:"index;size:64" json:"tenant_id"`
 	Name         string        `json:"name"`
 	Email        string        `json:"email"`
 	BankAccounts []BankAccount `json:"bank_accounts"`
@@ -11,11 +18,92 @@ type Customer struct {
 	TermDeposits []TermDeposit `json:"term_deposits"`
 }
 
+func (Customer) TenantOwned() {}
+
+// CustomerSearchRequest dibaca dari query string. Minimal satu kriteria
+// wajib diisi. Nama dan email dicari sebagian, jadi email tidak divalidasi
+// sebagai alamat lengkap.
+type CustomerSearchRequest struct {
+	Name          string `form:"name" binding:"max=100"`
+	Email         string `form:"email" binding:"max=254"`
+	AccountNumber string `form:"account_number" binding:"omitempty,account_number"`
+}
+
+// CustomerPageRequest adalah pencarian customer di /v2 dengan paginasi.
+type CustomerPageRequest struct {
+	CustomerSearchRequest
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+// CustomerFilter dengan Limit 0 mengembalikan semua hasil.
+type CustomerFilter struct {
+	Name          string
+	Email         string
+	AccountNumber string
+	Limit         int
+	Offset        int
+}
+
+// Pagination menyertai daftar di /v2. Total adalah jumlah seluruh hasil,
+// bukan hanya halaman ini.
+type Pagination struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
+
+// CustomerResponse adalah bentuk customer di /v2. Nominal uang dikirim
+// sebagai string desimal agar klien tidak kehilangan presisi.
 type CustomerResponse struct {
-	ID           uint          `json:"id"`
-	Name         string        `json:"name"`
-	Email        string        `json:"email"`
-	BankAccounts []BankAccount `json:"bank_accounts,omitempty"`
-	Pockets      []Pocket      `json:"pockets,omitempty"`
-	TermDeposits []TermDeposit `json:"term_deposits,omitempty"`
+	ID           uint                  `json:"id"`
+	TenantID     string                `json:"tenant_id"`
+	Name         string                `json:"name"`
+	Email        string                `json:"email"`
+	BankAccounts []BankAccountResponse `json:"bank_accounts"`
+	Pockets      []PocketResponse      `json:"pockets"`
+	TermDeposits []TermDepositResponse `json:"term_deposits"`
+}
+
+type (
+	BankAccountResponse struct {
+		AccountNumber string `json:"account_number"`
+	}
+
+	PocketResponse struct {
+		Name    string `json:"name"`
+		Balance string `json:"balance"`
+	}
+
+	TermDepositResponse struct {
+		Amount   string `json:"amount"`
+		Duration int    `json:"duration"`
+	}
+)
+
+func NewCustomerResponse(customer *Customer) CustomerResponse {
+	response := CustomerResponse{
+		ID:           customer.ID,
+		TenantID:     customer.TenantID,
+		Name:         customer.Name,
+		Email:        customer.Email,
+		BankAccounts: make([]BankAccountResponse, 0, len(customer.BankAccounts)),
+		Pockets:      make([]PocketResponse, 0, len(customer.Pockets)),
+		TermDeposits: make([]TermDepositResponse, 0, len(customer.TermDeposits)),
+	}
+	for _, account := range customer.BankAccounts {
+		response.BankAccounts = append(response.BankAccounts, BankAccountResponse{AccountNumber: account.AccountNumber})
+	}
+	for _, pocket := range customer.Pockets {
+		response.Pockets = append(response.Pockets, PocketResponse{Name: pocket.Name, Balance: FormatMoney(pocket.Balance)})
+	}
+	for _, deposit := range customer.TermDeposits {
+		response.TermDeposits = append(response.TermDeposits, TermDepositResponse{Amount: FormatMoney(deposit.Amount), Duration: deposit.Duration})
+	}
+	return response
+}
+
+// FormatMoney menulis nominal dengan dua angka desimal, misalnya "1500.00".
+func FormatMoney(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
 }
@@ -0,0 +1,15 @@
+package model
+
+type (
+	ImpersonateRequest struct {
+		Reason string `json:"reason" binding:"required,max=500"`
+	}
+
+	// ImpersonationResponse hanya berisi access token; impersonation tidak
+	// bisa diperpanjang lewat refresh token.
+	ImpersonationResponse struct {
+		AccessToken string `json:"token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		UserID      uint   `json:"user_id"`
+	}
+)
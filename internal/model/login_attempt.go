@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// LoginAttempt mencatat kegagalan login berturut-turut untuk satu kunci,
+// yaitu "user:<username>" atau "ip:<alamat>".
+type LoginAttempt struct {
+	ID            uint   `gorm:"primarykey"`
+	Key           string `gorm:"uniqueIndex"`
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// MFARecoveryCode menyimpan hash SHA-256 dari kode pemulihan sekali pakai.
+type MFARecoveryCode struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UserID    uint   `gorm:"index"`
+	CodeHash  string `gorm:"uniqueIndex"`
+	UsedAt    *time.Time
+}
+
+type (
+	MFAEnrollment struct {
+		Secret     string `json:"secret"`
+		OTPAuthURI string `json:"otpauth_uri"`
+	}
+
+	MFACodeRequest struct {
+		Code string `json:"code" binding:"required,max=32"`
+	}
+
+	MFALoginRequest struct {
+		MFAToken string `json:"mfa_token" binding:"required"`
+		Code     string `json:"code" binding:"required,max=32"`
+	}
+
+	MFARecoveryCodes struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+)
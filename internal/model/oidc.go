@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// OIDCState menyimpan data login OIDC yang sedang berjalan sampai
+// identity provider memanggil callback. State disimpan sebagai hash dan
+// hanya bisa dipakai sekali.
+type OIDCState struct {
+	ID           uint `gorm:"primarykey"`
+	CreatedAt    time.Time
+	StateHash    string `gorm:"uniqueIndex"`
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
+
+// ExternalIdentity menghubungkan user lokal dengan subject di identity
+// provider. Pasangan issuer dan subject unik.
+type ExternalIdentity struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UserID    uint   `gorm:"index"`
+	Issuer    string `gorm:"uniqueIndex:idx_external_identity"`
+	Subject   string `gorm:"uniqueIndex:idx_external_identity"`
+}
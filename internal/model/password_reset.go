@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// PasswordResetToken menyimpan hash SHA-256 dari token reset sekali pakai.
+type PasswordResetToken struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UserID    uint   `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+type (
+	ChangePasswordRequest struct {
+		OldPassword string `json:"old_password" binding:"required,max=128"`
+		NewPassword string `json:"new_password" binding:"required,max=128"`
+	}
+
+	ForgotPasswordRequest struct {
+		Username string `json:"username" binding:"required,max=64"`
+	}
+
+	ResetPasswordRequest struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required,max=128"`
+	}
+)
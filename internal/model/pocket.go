@@ -4,7 +4,10 @@ import "gorm.io/gorm"
 
 type Pocket struct {
 	gorm.Model
+	TenantID   string  `gorm:"index;size:64" json:"-"`
 	CustomerID uint    `json:"customer_id"`
 	Name       string  `json:"name"`
 	Balance    float64 `json:"balance"`
 }
+
+func (Pocket) TenantOwned() {}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefreshToken menyimpan hash SHA-256 dari refresh token opaque. Semua token
+// hasil rotasi dari satu login berbagi FamilyID yang sama.
+type RefreshToken struct {
+	gorm.Model
+	UserID    uint `gorm:"index"`
+	TenantID  string
+	FamilyID  string `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+type (
+	RefreshRequest struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+)
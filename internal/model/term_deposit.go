@@ -4,7 +4,10 @@ import "gorm.io/gorm"
 
 type TermDeposit struct {
 	gorm.Model
+	TenantID   string  `gorm:"index;size:64" json:"-"`
 	CustomerID uint    `json:"customer_id"`
 	Amount     float64 `json:"amount"`
 	Duration   int     `json:"duration"`
 }
+
+func (TermDeposit) TenantOwned() {}
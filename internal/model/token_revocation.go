@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// TokenRevocation mencabut satu access token (JTI terisi) atau semua token
+// milik UserID yang diterbitkan sebelum IssuedBefore (JTI kosong). Baris
+// boleh dihapus setelah ExpiresAt karena token terkait sudah kedaluwarsa.
+type TokenRevocation struct {
+	ID           uint `gorm:"primarykey"`
+	CreatedAt    time.Time
+	JTI          string `gorm:"index"`
+	UserID       uint   `gorm:"index"`
+	IssuedBefore time.Time
+	ExpiresAt    time.Time `gorm:"index"`
+}
+
+type (
+	LogoutRequest struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+)
@@ -1,22 +1,105 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"regexp"
+	"slices"
 
+	"gorm.io/gorm"
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleAgent = "agent"
+)
+
+// Roles berisi semua role yang boleh diberikan ke user.
+var Roles = []string{RoleAdmin, RoleAgent}
+
+func ValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+// RoleRank mengembalikan posisi role di Roles; angka kecil berarti hak
+// lebih tinggi. Role yang tidak dikenal mendapat peringkat terendah.
+func RoleRank(role string) int {
+	if i := slices.Index(Roles, role); i >= 0 {
+		return i
+	}
+	return len(Roles)
+}
+
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ValidTenant memeriksa format kode cabang, misalnya "JKT".
+func ValidTenant(tenantID string) bool {
+	return tenantPattern.MatchString(tenantID)
+}
+
+// Password tidak pernah ikut diserialisasi ke JSON. Username unik di antara
+// user yang belum dihapus, sehingga username user yang dihapus bisa
+// dipakai lagi.
 type User struct {
 	gorm.Model
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `gorm:"uniqueIndex:idx_users_username,where:deleted_at IS NULL" json:"username"`
+	Password string `json:"-"`
+	Role     string `gorm:"default:agent" json:"role"`
+	Disabled bool   `json:"disabled"`
+	TenantID string `gorm:"index;size:64" json:"tenant_id"`
+	// Language mengganti Accept-Language untuk pesan API. Kosong berarti
+	// mengikuti header.
+	Language string `gorm:"size:8" json:"language,omitempty"`
+
+	// MFASecret terisi sejak enrollment dimulai, tetapi MFA baru berlaku
+	// setelah MFAEnabled true. MFALastStep mencegah kode TOTP dipakai ulang.
+	MFASecret   string `json:"-"`
+	MFAEnabled  bool   `json:"mfa_enabled"`
+	MFALastStep int64  `json:"-"`
 }
 
 type (
 	UserRequest struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
+		Username string `json:"username" binding:"required,max=64"`
+		Password string `json:"password" binding:"required,max=128"`
+	}
+
+	CreateUserRequest struct {
+		Username string `json:"username" binding:"required,max=64"`
+		Password string `json:"password" binding:"required,max=128"`
+		Role     string `json:"role" binding:"omitempty,role"`
+		TenantID string `json:"tenant_id" binding:"omitempty,tenant"`
+	}
+
+	AssignRoleRequest struct {
+		Role string `json:"role" binding:"required,role"`
+	}
+
+	AssignTenantRequest struct {
+		TenantID string `json:"tenant_id" binding:"required,tenant"`
+	}
+
+	// Language kosong menghapus pilihan bahasa user.
+	LanguageRequest struct {
+		Language string `json:"language" binding:"omitempty,language"`
+	}
+
+	// UserFilter dengan Query mencari username yang mengandung teks itu.
+	UserFilter struct {
+		Query    string
+		Role     string
+		Disabled *bool
+		Limit    int
+		Offset   int
 	}
 )
 
 type (
+	// LoginResponse berisi MFAToken, bukan token akhir, jika user sudah
+	// mendaftarkan MFA.
 	LoginResponse struct {
-		AccessToken string `json:"accessToken"`
+		AccessToken  string `json:"token,omitempty"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+		ExpiresIn    int64  `json:"expires_in,omitempty"`
+		MFARequired  bool   `json:"mfa_required,omitempty"`
+		MFAToken     string `json:"mfa_token,omitempty"`
 	}
 )
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(key *model.APIKey) error
+	FindByID(id uint) (*model.APIKey, error)
+	FindByHash(keyHash string) (*model.APIKey, error)
+	List(userID uint) ([]model.APIKey, error)
+	Revoke(id uint, at time.Time) (bool, error)
+	TouchLastUsed(id uint, at time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(key *model.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *apiKeyRepository) FindByID(id uint) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.First(&key, id).Error
+	return &key, err
+}
+
+func (r *apiKeyRepository) FindByHash(keyHash string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.Where("key_hash = ?", keyHash).First(&key).Error
+	return &key, err
+}
+
+// List dengan userID 0 mengembalikan key milik semua user.
+func (r *apiKeyRepository) List(userID uint) ([]model.APIKey, error) {
+	query := r.db.Order("id")
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var keys []model.APIKey
+	err := query.Find(&keys).Error
+	return keys, err
+}
+
+// Revoke hanya mengubah key yang belum dicabut agar waktu pencabutan
+// pertama tidak tertimpa.
+func (r *apiKeyRepository) Revoke(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *apiKeyRepository) TouchLastUsed(id uint, at time.Time) error {
+	return r.db.Model(&model.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	db := setupSQLiteDB(t, &model.APIKey{})
+
+	repo := NewAPIKeyRepository(db)
+	now := time.Now()
+
+	key := &model.APIKey{Name: "batch", UserID: 1, KeyHash: "hash", Scopes: model.ScopeCustomersRead}
+	assert.NoError(t, repo.Create(key))
+	assert.NoError(t, repo.Create(&model.APIKey{Name: "other", UserID: 2, KeyHash: "other"}))
+
+	found, err := repo.FindByHash("hash")
+	assert.NoError(t, err)
+	assert.Equal(t, key.ID, found.ID)
+	assert.True(t, found.HasScope(model.ScopeCustomersRead))
+
+	keys, err := repo.List(1)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	keys, err = repo.List(0)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	assert.NoError(t, repo.TouchLastUsed(key.ID, now))
+	found, err = repo.FindByID(key.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, found.LastUsedAt)
+
+	revoked, err := repo.Revoke(key.ID, now)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.Revoke(key.ID, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	_, err = repo.FindByID(99)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
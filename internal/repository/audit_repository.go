@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"gorm.io/gorm"
+)
+
+// AuditRepository hanya menyediakan operasi tambah dan baca; audit log
+// tidak pernah di-update atau dihapus.
+type AuditRepository interface {
+	Append(entry *model.AuditLog, seal func(prev *model.AuditLog)) error
+	Find(filter model.AuditFilter) ([]model.AuditLog, error)
+	Walk(batchSize int, fn func(batch []model.AuditLog) error) error
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// auditChainLock adalah key advisory lock Postgres untuk rantai audit.
+const auditChainLock = 0x617564697463 // "auditc"
+
+// Append membaca baris terakhir dan menyisipkan entry baru dalam satu
+// transaksi. seal dipanggil dengan baris sebelumnya (nil untuk baris
+// pertama) untuk mengisi Seq, PrevHash dan Hash. Di Postgres transaksi
+// memegang advisory lock sampai commit, sehingga replica lain menunggu
+// dan membaca baris terakhir yang sudah tersimpan, bukan bertabrakan di
+// Seq yang sama. SQLite sudah menyerialkan semua penulisan.
+func (r *auditRepository) Append(entry *model.AuditLog, seal func(prev *model.AuditLog)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLock).Error; err != nil {
+				return err
+			}
+		}
+
+		var prev model.AuditLog
+		err := tx.Order("seq DESC").First(&prev).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			seal(nil)
+		case err != nil:
+			return err
+		default:
+			seal(&prev)
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *auditRepository) Find(filter model.AuditFilter) ([]model.AuditLog, error) {
+	var logs []model.AuditLog
+
+	query := r.db.Order("seq DESC")
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ? OR actor_id = ?", filter.UserID, filter.UserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ReviewRequired {
+		query = query.Where("review_required = ?", true)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at <= ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	err := query.Find(&logs).Error
+	return logs, err
+}
+
+// Walk membaca seluruh audit log berurutan berdasarkan Seq dalam batch.
+func (r *auditRepository) Walk(batchSize int, fn func(batch []model.AuditLog) error) error {
+	var lastSeq uint64
+	for {
+		var batch []model.AuditLog
+		err := r.db.Where("seq > ?", lastSeq).Order("seq").Limit(batchSize).Find(&batch).Error
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		lastSeq = batch[len(batch)-1].Seq
+	}
+}
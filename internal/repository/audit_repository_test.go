@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepositoryAppend(t *testing.T) {
+	repo := NewAuditRepository(setupSQLiteDB(t, &model.AuditLog{}))
+
+	var seenPrev []*model.AuditLog
+	for i := 1; i <= 3; i++ {
+		entry := &model.AuditLog{UserID: uint(i), Action: model.AuditActionCustomerSearch}
+		err := repo.Append(entry, func(prev *model.AuditLog) {
+			seenPrev = append(seenPrev, prev)
+			entry.Seq = uint64(i)
+			entry.Hash = string(rune('a' + i))
+		})
+		assert.NoError(t, err)
+	}
+
+	assert.Nil(t, seenPrev[0])
+	assert.Equal(t, uint64(1), seenPrev[1].Seq)
+	assert.Equal(t, uint64(2), seenPrev[2].Seq)
+}
+
+func TestAuditRepositoryAppendLocksChain(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	repo := NewAuditRepository(gormDB)
+
+	// Lock diambil sebelum baris terakhir dibaca, di transaksi yang sama.
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).
+		WithArgs(auditChainLock).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT \* FROM "audit_logs" ORDER BY seq DESC`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "seq", "hash"}).AddRow(1, 1, "a"))
+	mock.ExpectQuery(`INSERT INTO "audit_logs"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+
+	entry := &model.AuditLog{Action: model.AuditActionCustomerSearch}
+	err := repo.Append(entry, func(prev *model.AuditLog) {
+		entry.Seq = prev.Seq + 1
+		entry.Hash = "b"
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepositoryFindAndWalk(t *testing.T) {
+	repo := NewAuditRepository(setupSQLiteDB(t, &model.AuditLog{}))
+	for i := 1; i <= 5; i++ {
+		entry := &model.AuditLog{UserID: uint(i%2 + 1), Action: model.AuditActionCustomerSearch}
+		err := repo.Append(entry, func(prev *model.AuditLog) {
+			entry.Seq = uint64(i)
+			entry.Hash = string(rune('a' + i))
+		})
+		assert.NoError(t, err)
+	}
+
+	logs, err := repo.Find(model.AuditFilter{UserID: 2, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 3)
+	assert.Equal(t, uint64(5), logs[0].Seq)
+
+	var seqs []uint64
+	err = repo.Walk(2, func(batch []model.AuditLog) error {
+		for _, entry := range batch {
+			seqs = append(seqs, entry.Seq)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5}, seqs)
+}
+
+func TestAuditRepositoryAppendLockError(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	repo := NewAuditRepository(gormDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).
+		WithArgs(auditChainLock).
+		WillReturnError(errors.New("lock timeout"))
+	mock.ExpectRollback()
+
+	sealed := false
+	err := repo.Append(&model.AuditLog{}, func(prev *model.AuditLog) { sealed = true })
+	assert.EqualError(t, err, "lock timeout")
+	assert.False(t, sealed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,13 +1,17 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/danisasmita/customer-search/internal/model"
 
 	"gorm.io/gorm"
 )
 
 type CustomerRepository interface {
-	FindByName(name, email, accountNumber string) ([]model.Customer, error)
+	FindByName(ctx context.Context, name, email, accountNumber string) ([]model.Customer, error)
+	Search(ctx context.Context, filter model.CustomerFilter) ([]model.Customer, int64, error)
+	FindByID(ctx context.Context, id uint) (*model.Customer, error)
 }
 
 type customerRepository struct {
@@ -18,19 +22,43 @@ func NewCustomerRepository(db *gorm.DB) CustomerRepository {
 	return &customerRepository{db: db}
 }
 
-func (r *customerRepository) FindByName(name, email, accountNumber string) ([]model.Customer, error) {
+// ctx harus membawa tenant.Scope; tanpa itu query ditolak oleh callback
+// tenant.
+func (r *customerRepository) FindByName(ctx context.Context, name, email, accountNumber string) ([]model.Customer, error) {
 	var customers []model.Customer
+	err := filterCustomers(r.withProducts(ctx), name, email, accountNumber).Find(&customers).Error
+	return customers, err
+}
 
-	query := r.db.Preload("BankAccounts", func(db *gorm.DB) *gorm.DB {
-		return db.Select("customer_id, account_number")
-	}).
-		Preload("Pockets", func(db *gorm.DB) *gorm.DB {
-			return db.Select("customer_id, name, balance")
-		}).
-		Preload("TermDeposits", func(db *gorm.DB) *gorm.DB {
-			return db.Select("customer_id, amount, duration")
-		})
+// Search menghitung total hasil sebelum mengambil satu halaman. Limit 0
+// berarti tanpa batas.
+func (r *customerRepository) Search(ctx context.Context, filter model.CustomerFilter) ([]model.Customer, int64, error) {
+	var total int64
+	err := filterCustomers(r.db.WithContext(ctx).Model(&model.Customer{}), filter.Name, filter.Email, filter.AccountNumber).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
 
+	query := filterCustomers(r.withProducts(ctx), filter.Name, filter.Email, filter.AccountNumber).
+		Order("customers.id").
+		Offset(filter.Offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var customers []model.Customer
+	err = query.Find(&customers).Error
+	return customers, total, err
+}
+
+func (r *customerRepository) FindByID(ctx context.Context, id uint) (*model.Customer, error) {
+	var customer model.Customer
+	err := r.withProducts(ctx).First(&customer, id).Error
+	return &customer, err
+}
+
+func filterCustomers(query *gorm.DB, name, email, accountNumber string) *gorm.DB {
 	if name != "" {
 		query = query.Where("name LIKE ?", "%"+name+"%")
 	}
@@ -41,7 +69,19 @@ func (r *customerRepository) FindByName(name, email, accountNumber string) ([]mo
 		query = query.Joins("JOIN bank_accounts ON bank_accounts.customer_id = customers.id").
 			Where("bank_accounts.account_number = ?", accountNumber)
 	}
+	return query
+}
 
-	err := query.Find(&customers).Error
-	return customers, err
+// withProducts memuat rekening, pocket dan deposito milik customer. Query
+// preload memakai context yang sama sehingga ikut dibatasi per cabang.
+func (r *customerRepository) withProducts(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Preload("BankAccounts", func(db *gorm.DB) *gorm.DB {
+		return db.Select("customer_id, account_number")
+	}).
+		Preload("Pockets", func(db *gorm.DB) *gorm.DB {
+			return db.Select("customer_id, name, balance")
+		}).
+		Preload("TermDeposits", func(db *gorm.DB) *gorm.DB {
+			return db.Select("customer_id, amount, duration")
+		})
 }
@@ -1,12 +1,14 @@
 package repository
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/tenant"
 	"github.com/stretchr/testify/assert"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -16,22 +18,6 @@ const (
 	customerEmailJane = "jane@example.com"
 )
 
-func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-
-	gormDB, err := gorm.Open(postgres.New(postgres.Config{
-		Conn: db,
-	}), &gorm.Config{})
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a gorm database connection", err)
-	}
-
-	return gormDB, mock
-}
-
 func TestCustomerRepositoryFindByName(t *testing.T) {
 	gormDB, mock := setupMockDB(t)
 	repo := NewCustomerRepository(gormDB)
@@ -61,7 +47,7 @@ func TestCustomerRepositoryFindByName(t *testing.T) {
 			WithArgs(1).
 			WillReturnRows(sqlmock.NewRows([]string{"customer_id", "amount", "duration"}))
 
-		customers, err := repo.FindByName("John", "", "")
+		customers, err := repo.FindByName(tenant.Global(context.Background()), "John", "", "")
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(customers))
@@ -96,7 +82,7 @@ func TestCustomerRepositoryFindByName(t *testing.T) {
 			WithArgs(1).
 			WillReturnRows(sqlmock.NewRows([]string{"customer_id", "amount", "duration"}))
 
-		customers, err := repo.FindByName("", customerEmailJane, "")
+		customers, err := repo.FindByName(tenant.Global(context.Background()), "", customerEmailJane, "")
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(customers))
@@ -112,7 +98,7 @@ func TestCustomerRepositoryFindByName(t *testing.T) {
 			AddRow(1, time.Now(), time.Now(), nil, customerName, customerEmail)
 
 		// Mock query untuk customers dengan JOIN bank_accounts
-		mock.ExpectQuery(`SELECT "customers"."id","customers"."created_at","customers"."updated_at","customers"."deleted_at","customers"."name","customers"."email" FROM "customers" JOIN bank_accounts ON bank_accounts.customer_id = customers.id WHERE bank_accounts.account_number = \$1 AND "customers"."deleted_at" IS NULL`).
+		mock.ExpectQuery(`SELECT "customers"."id","customers"."created_at","customers"."updated_at","customers"."deleted_at","customers"."tenant_id","customers"."name","customers"."email" FROM "customers" JOIN bank_accounts ON bank_accounts.customer_id = customers.id WHERE bank_accounts.account_number = \$1 AND "customers"."deleted_at" IS NULL`).
 			WithArgs("123456").
 			WillReturnRows(rows)
 
@@ -133,7 +119,7 @@ func TestCustomerRepositoryFindByName(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"customer_id", "amount", "duration"}))
 
 		// Panggil fungsi repository
-		customers, err := repo.FindByName("", "", "123456")
+		customers, err := repo.FindByName(tenant.Global(context.Background()), "", "", "123456")
 
 		// Assertions
 		assert.NoError(t, err)                                  // Pastikan tidak ada error
@@ -150,7 +136,7 @@ func TestCustomerRepositoryFindByName(t *testing.T) {
 			WithArgs("%Unknown%").
 			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "deleted_at"}))
 
-		customers, err := repo.FindByName("Unknown", "", "")
+		customers, err := repo.FindByName(tenant.Global(context.Background()), "Unknown", "", "")
 
 		assert.NoError(t, err)
 		assert.Equal(t, 0, len(customers))
@@ -165,7 +151,7 @@ func TestCustomerRepositoryFindByName(t *testing.T) {
 			WillReturnError(gorm.ErrInvalidDB) // Simulasikan error database
 
 		// Panggil fungsi repository
-		customers, err := repo.FindByName("John", "", "")
+		customers, err := repo.FindByName(tenant.Global(context.Background()), "John", "", "")
 
 		// Assertions
 		assert.Error(t, err)     // Pastikan error tidak nil
@@ -175,3 +161,119 @@ func TestCustomerRepositoryFindByName(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestCustomerRepositoryFindByID(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	repo := NewCustomerRepository(gormDB)
+
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "deleted_at"}).
+			AddRow(1, customerName, customerEmail, time.Now(), time.Now(), nil)
+
+		mock.ExpectQuery(`SELECT \* FROM "customers" WHERE "customers"."id" = \$1 AND "customers"."deleted_at" IS NULL ORDER BY "customers"."id" LIMIT \$2`).
+			WithArgs(1, 1).
+			WillReturnRows(rows)
+		mock.ExpectQuery(`SELECT customer_id, account_number FROM "bank_accounts"`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"customer_id", "account_number"}).AddRow(1, "123456"))
+		mock.ExpectQuery(`SELECT customer_id, name, balance FROM "pockets"`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"customer_id", "name", "balance"}))
+		mock.ExpectQuery(`SELECT customer_id, amount, duration FROM "term_deposits"`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"customer_id", "amount", "duration"}))
+
+		customer, err := repo.FindByID(tenant.Global(context.Background()), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, customerName, customer.Name)
+		assert.Len(t, customer.BankAccounts, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT \* FROM "customers" WHERE "customers"."id" = \$1`).
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.FindByID(tenant.Global(context.Background()), 2)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCustomerRepositorySearch(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	repo := NewCustomerRepository(gormDB)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "customers" WHERE name LIKE \$1 AND "customers"."deleted_at" IS NULL`).
+		WithArgs("%John%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`SELECT \* FROM "customers" WHERE name LIKE \$1 AND "customers"."deleted_at" IS NULL ORDER BY customers.id LIMIT \$2 OFFSET \$3`).
+		WithArgs("%John%", 1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(3, customerName, customerEmail))
+	mock.ExpectQuery(`SELECT customer_id, account_number FROM "bank_accounts"`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"customer_id", "account_number"}))
+	mock.ExpectQuery(`SELECT customer_id, name, balance FROM "pockets"`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"customer_id", "name", "balance"}))
+	mock.ExpectQuery(`SELECT customer_id, amount, duration FROM "term_deposits"`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"customer_id", "amount", "duration"}))
+
+	customers, total, err := repo.Search(tenant.Global(context.Background()), model.CustomerFilter{Name: "John", Limit: 1, Offset: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, customers, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCustomerRepositoryTenantScope(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	repo := NewCustomerRepository(gormDB)
+	branchCtx := tenant.WithScope(context.Background(), tenant.Scope{TenantID: "JKT"})
+
+	t.Run("search and preloads scoped to tenant", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT "customers"."id",.* FROM "customers" JOIN bank_accounts ON bank_accounts.customer_id = customers.id WHERE bank_accounts.account_number = \$1 AND "customers"."tenant_id" = \$2 AND "customers"."deleted_at" IS NULL`).
+			WithArgs("123456", "JKT").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name"}).AddRow(1, "JKT", customerName))
+		mock.ExpectQuery(`SELECT customer_id, account_number FROM "bank_accounts" WHERE "bank_accounts"."customer_id" = \$1 AND "bank_accounts"."tenant_id" = \$2`).
+			WithArgs(1, "JKT").
+			WillReturnRows(sqlmock.NewRows([]string{"customer_id", "account_number"}).AddRow(1, "123456"))
+		mock.ExpectQuery(`SELECT customer_id, name, balance FROM "pockets" WHERE "pockets"."customer_id" = \$1 AND "pockets"."tenant_id" = \$2`).
+			WithArgs(1, "JKT").
+			WillReturnRows(sqlmock.NewRows([]string{"customer_id", "name", "balance"}))
+		mock.ExpectQuery(`SELECT customer_id, amount, duration FROM "term_deposits" WHERE "term_deposits"."customer_id" = \$1 AND "term_deposits"."tenant_id" = \$2`).
+			WithArgs(1, "JKT").
+			WillReturnRows(sqlmock.NewRows([]string{"customer_id", "amount", "duration"}))
+
+		customers, err := repo.FindByName(branchCtx, "", "", "123456")
+
+		assert.NoError(t, err)
+		assert.Len(t, customers, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("find by id scoped to tenant", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT \* FROM "customers" WHERE "customers"."id" = \$1 AND "customers"."tenant_id" = \$2 AND "customers"."deleted_at" IS NULL`).
+			WithArgs(1, "JKT", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.FindByID(branchCtx, 1)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("missing scope rejected", func(t *testing.T) {
+		_, err := repo.FindByName(context.Background(), customerName, "", "")
+		assert.ErrorIs(t, err, tenant.ErrMissingScope)
+
+		_, err = repo.FindByID(tenant.WithScope(context.Background(), tenant.Scope{}), 1)
+		assert.ErrorIs(t, err, tenant.ErrMissingScope)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/danisasmita/customer-search/pkg/tenant"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupMockDB membuka GORM dengan dialect postgres di atas sqlmock untuk
+// memeriksa SQL yang dikirim ke Postgres.
+func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a gorm database connection", err)
+	}
+	if err := tenant.Register(gormDB); err != nil {
+		t.Fatalf("an error '%s' was not expected when registering tenant callbacks", err)
+	}
+
+	return gormDB, mock
+}
+
+// setupSQLiteDB membuka SQLite in-memory dengan tabel untuk models, untuk
+// test yang memeriksa perilaku query terhadap data sungguhan. Konfigurasi
+// GORM sama dengan aplikasi sehingga error unique menjadi
+// gorm.ErrDuplicatedKey.
+func setupSQLiteDB(t *testing.T, models ...any) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db
+}
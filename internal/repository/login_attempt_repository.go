@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"github.com/danisasmita/customer-search/internal/model"
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepository interface {
+	Find(key string) (*model.LoginAttempt, error)
+	Save(attempt *model.LoginAttempt) error
+	Delete(key string) error
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Find(key string) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	err := r.db.Where("key = ?", key).First(&attempt).Error
+	return &attempt, err
+}
+
+func (r *loginAttemptRepository) Save(attempt *model.LoginAttempt) error {
+	return r.db.Save(attempt).Error
+}
+
+func (r *loginAttemptRepository) Delete(key string) error {
+	return r.db.Where("key = ?", key).Delete(&model.LoginAttempt{}).Error
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestLoginAttemptRepository(t *testing.T) {
+	db := setupSQLiteDB(t, &model.LoginAttempt{})
+
+	repo := NewLoginAttemptRepository(db)
+
+	_, err := repo.Find("user:john")
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	attempt := &model.LoginAttempt{Key: "user:john", Failures: 1, LastFailureAt: time.Now()}
+	assert.NoError(t, repo.Save(attempt))
+	attempt.Failures = 2
+	assert.NoError(t, repo.Save(attempt))
+
+	found, err := repo.Find("user:john")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, found.Failures)
+
+	assert.NoError(t, repo.Delete("user:john"))
+	_, err = repo.Find("user:john")
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestLoginAttemptRepositorySaveUpsert(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	repo := NewLoginAttemptRepository(gormDB)
+	now := time.Now()
+
+	// Jika UPDATE tidak mengenai baris, misalnya karena baris sudah dihapus
+	// lewat Delete, GORM menyisipkannya ulang dengan ON CONFLICT.
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "login_attempts" SET "key"=\$1,"failures"=\$2,"last_failure_at"=\$3,"locked_until"=\$4,"updated_at"=\$5 WHERE "id" = \$6`).
+		WithArgs("user:john", 3, now, nil, sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "login_attempts" .* ON CONFLICT \("id"\) DO UPDATE SET .*"failures"="excluded"."failures".* RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectCommit()
+
+	attempt := &model.LoginAttempt{ID: 7, Key: "user:john", Failures: 3, LastFailureAt: now}
+	assert.NoError(t, repo.Save(attempt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"gorm.io/gorm"
+)
+
+type MFARecoveryCodeRepository interface {
+	Replace(userID uint, codes []model.MFARecoveryCode) error
+	Use(userID uint, codeHash string, at time.Time) (bool, error)
+	DeleteByUser(userID uint) error
+}
+
+type mfaRecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARecoveryCodeRepository(db *gorm.DB) MFARecoveryCodeRepository {
+	return &mfaRecoveryCodeRepository{db: db}
+}
+
+// Replace menghapus kode lama milik user dan menyimpan kode baru dalam satu
+// transaksi.
+func (r *mfaRecoveryCodeRepository) Replace(userID uint, codes []model.MFARecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// Use menandai kode terpakai. Nilai false berarti kode tidak ada atau sudah
+// pernah dipakai.
+func (r *mfaRecoveryCodeRepository) Use(userID uint, codeHash string, at time.Time) (bool, error) {
+	result := r.db.Model(&model.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *mfaRecoveryCodeRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error
+}
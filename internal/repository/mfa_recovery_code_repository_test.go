@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFARecoveryCodeRepository(t *testing.T) {
+	db := setupSQLiteDB(t, &model.MFARecoveryCode{})
+
+	repo := NewMFARecoveryCodeRepository(db)
+	now := time.Now()
+
+	assert.NoError(t, repo.Replace(1, []model.MFARecoveryCode{{UserID: 1, CodeHash: "old"}}))
+	assert.NoError(t, repo.Replace(1, []model.MFARecoveryCode{{UserID: 1, CodeHash: "a"}, {UserID: 1, CodeHash: "b"}}))
+
+	used, err := repo.Use(1, "old", now)
+	assert.NoError(t, err)
+	assert.False(t, used, "replaced codes must not work")
+
+	used, err = repo.Use(2, "a", now)
+	assert.NoError(t, err)
+	assert.False(t, used, "codes belong to one user")
+
+	used, err = repo.Use(1, "a", now)
+	assert.NoError(t, err)
+	assert.True(t, used)
+
+	used, err = repo.Use(1, "a", now)
+	assert.NoError(t, err)
+	assert.False(t, used, "codes are single use")
+
+	assert.NoError(t, repo.DeleteByUser(1))
+	var count int64
+	db.Model(&model.MFARecoveryCode{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
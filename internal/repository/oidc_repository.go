@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"github.com/danisasmita/customer-search/internal/model"
+	"gorm.io/gorm"
+)
+
+type OIDCRepository interface {
+	CreateState(state *model.OIDCState) error
+	ConsumeState(stateHash string) (*model.OIDCState, error)
+	FindIdentity(issuer, subject string) (*model.ExternalIdentity, error)
+	CreateUserWithIdentity(user *model.User, identity *model.ExternalIdentity) error
+}
+
+type oidcRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCRepository(db *gorm.DB) OIDCRepository {
+	return &oidcRepository{db: db}
+}
+
+func (r *oidcRepository) CreateState(state *model.OIDCState) error {
+	return r.db.Create(state).Error
+}
+
+// ConsumeState mengambil lalu menghapus state. Jika dua callback datang
+// bersamaan dengan state yang sama, hanya satu yang berhasil menghapus.
+func (r *oidcRepository) ConsumeState(stateHash string) (*model.OIDCState, error) {
+	var state model.OIDCState
+	if err := r.db.Where("state_hash = ?", stateHash).First(&state).Error; err != nil {
+		return nil, err
+	}
+
+	result := r.db.Where("id = ?", state.ID).Delete(&model.OIDCState{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &state, nil
+}
+
+func (r *oidcRepository) FindIdentity(issuer, subject string) (*model.ExternalIdentity, error) {
+	var identity model.ExternalIdentity
+	err := r.db.Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+	return &identity, err
+}
+
+// CreateUserWithIdentity membuat user dan identitas eksternalnya dalam satu
+// transaksi agar tidak ada user SSO tanpa identitas.
+func (r *oidcRepository) CreateUserWithIdentity(user *model.User, identity *model.ExternalIdentity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	})
+}
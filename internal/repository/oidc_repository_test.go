@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestOIDCRepository(t *testing.T) {
+	db := setupSQLiteDB(t, &model.User{}, &model.OIDCState{}, &model.ExternalIdentity{})
+
+	repo := NewOIDCRepository(db)
+
+	assert.NoError(t, repo.CreateState(&model.OIDCState{StateHash: "state", Nonce: "nonce", ExpiresAt: time.Now().Add(time.Minute)}))
+
+	state, err := repo.ConsumeState("state")
+	assert.NoError(t, err)
+	assert.Equal(t, "nonce", state.Nonce)
+
+	_, err = repo.ConsumeState("state")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	user := &model.User{Username: "jane", Role: model.RoleAgent}
+	identity := &model.ExternalIdentity{Issuer: "https://idp", Subject: "abc123"}
+	assert.NoError(t, repo.CreateUserWithIdentity(user, identity))
+	assert.Equal(t, user.ID, identity.UserID)
+
+	found, err := repo.FindIdentity("https://idp", "abc123")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, found.UserID)
+
+	// Subject yang sama tidak bisa dipakai dua kali, dan user ikut batal.
+	err = repo.CreateUserWithIdentity(&model.User{Username: "jane2"}, &model.ExternalIdentity{Issuer: "https://idp", Subject: "abc123"})
+	assert.Error(t, err)
+	var count int64
+	db.Model(&model.User{}).Where("username = ?", "jane2").Count(&count)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"gorm.io/gorm"
+)
+
+type PasswordResetRepository interface {
+	Create(token *model.PasswordResetToken) error
+	FindByHash(tokenHash string) (*model.PasswordResetToken, error)
+	MarkUsed(id uint, at time.Time) (bool, error)
+	DeleteByUser(userID uint) error
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Create(token *model.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *passwordResetRepository) FindByHash(tokenHash string) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	return &token, err
+}
+
+// MarkUsed hanya berhasil untuk token yang belum dipakai, sehingga dua
+// request bersamaan dengan token yang sama tidak bisa sama-sama lolos.
+func (r *passwordResetRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&model.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *passwordResetRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.PasswordResetToken{}).Error
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestPasswordResetRepository(t *testing.T) {
+	db := setupSQLiteDB(t, &model.PasswordResetToken{})
+
+	repo := NewPasswordResetRepository(db)
+	now := time.Now()
+
+	token := &model.PasswordResetToken{UserID: 1, TokenHash: "hash", ExpiresAt: now.Add(time.Hour)}
+	assert.NoError(t, repo.Create(token))
+
+	found, err := repo.FindByHash("hash")
+	assert.NoError(t, err)
+	assert.Equal(t, token.ID, found.ID)
+
+	used, err := repo.MarkUsed(token.ID, now)
+	assert.NoError(t, err)
+	assert.True(t, used)
+
+	used, err = repo.MarkUsed(token.ID, now)
+	assert.NoError(t, err)
+	assert.False(t, used)
+
+	assert.NoError(t, repo.DeleteByUser(1))
+	_, err = repo.FindByHash("hash")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
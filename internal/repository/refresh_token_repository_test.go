@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenRepository(t *testing.T) {
+	repo := NewRefreshTokenRepository(setupSQLiteDB(t, &model.RefreshToken{}))
+	expiresAt := time.Now().Add(time.Hour)
+
+	first := &model.RefreshToken{UserID: 1, FamilyID: "family-a", TokenHash: "hash-1", ExpiresAt: expiresAt}
+	second := &model.RefreshToken{UserID: 1, FamilyID: "family-a", TokenHash: "hash-2", ExpiresAt: expiresAt}
+	other := &model.RefreshToken{UserID: 2, FamilyID: "family-b", TokenHash: "hash-3", ExpiresAt: expiresAt}
+	for _, token := range []*model.RefreshToken{first, second, other} {
+		assert.NoError(t, repo.Create(token))
+	}
+
+	found, err := repo.FindByHash("hash-2")
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, found.ID)
+
+	marked, err := repo.MarkUsed(first.ID, time.Now())
+	assert.NoError(t, err)
+	assert.True(t, marked)
+
+	marked, err = repo.MarkUsed(first.ID, time.Now())
+	assert.NoError(t, err)
+	assert.False(t, marked, "token yang sudah dipakai tidak boleh ditandai ulang")
+
+	assert.NoError(t, repo.RevokeFamily("family-a", time.Now()))
+
+	found, _ = repo.FindByHash("hash-2")
+	assert.NotNil(t, found.RevokedAt)
+	found, _ = repo.FindByHash("hash-3")
+	assert.Nil(t, found.RevokedAt)
+}
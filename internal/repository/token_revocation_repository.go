@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"gorm.io/gorm"
+)
+
+type TokenRevocationRepository interface {
+	Create(revocation *model.TokenRevocation) error
+	FindActiveAfter(lastID uint, now time.Time) ([]model.TokenRevocation, error)
+	DeleteExpired(now time.Time) error
+}
+
+type tokenRevocationRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRevocationRepository(db *gorm.DB) TokenRevocationRepository {
+	return &tokenRevocationRepository{db: db}
+}
+
+func (r *tokenRevocationRepository) Create(revocation *model.TokenRevocation) error {
+	return r.db.Create(revocation).Error
+}
+
+// FindActiveAfter mengambil pencabutan yang belum kedaluwarsa dengan ID lebih
+// besar dari lastID, sehingga cache hanya perlu membaca baris baru.
+func (r *tokenRevocationRepository) FindActiveAfter(lastID uint, now time.Time) ([]model.TokenRevocation, error) {
+	var revocations []model.TokenRevocation
+	err := r.db.Where("id > ? AND expires_at > ?", lastID, now).
+		Order("id").
+		Find(&revocations).Error
+	return revocations, err
+}
+
+func (r *tokenRevocationRepository) DeleteExpired(now time.Time) error {
+	return r.db.Where("expires_at <= ?", now).Delete(&model.TokenRevocation{}).Error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenRevocationRepository(t *testing.T) {
+	db := setupSQLiteDB(t, &model.TokenRevocation{})
+
+	repo := NewTokenRevocationRepository(db)
+	now := time.Now()
+
+	expired := &model.TokenRevocation{JTI: "old", UserID: 1, ExpiresAt: now.Add(-time.Minute)}
+	first := &model.TokenRevocation{JTI: "a", UserID: 1, ExpiresAt: now.Add(time.Hour)}
+	second := &model.TokenRevocation{UserID: 2, IssuedBefore: now, ExpiresAt: now.Add(time.Hour)}
+	for _, revocation := range []*model.TokenRevocation{expired, first, second} {
+		assert.NoError(t, repo.Create(revocation))
+	}
+
+	active, err := repo.FindActiveAfter(0, now)
+	assert.NoError(t, err)
+	assert.Len(t, active, 2)
+
+	active, err = repo.FindActiveAfter(first.ID, now)
+	assert.NoError(t, err)
+	assert.Len(t, active, 1)
+	assert.Equal(t, second.ID, active[0].ID)
+
+	assert.NoError(t, repo.DeleteExpired(now))
+	var count int64
+	db.Model(&model.TokenRevocation{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
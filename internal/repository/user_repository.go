@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"strings"
+
 	"github.com/danisasmita/customer-search/internal/model"
 	"gorm.io/gorm"
 )
@@ -8,6 +10,17 @@ import (
 type UserRepository interface {
 	CreateUser(user *model.User) error
 	FindUserByUsername(username string) (*model.User, error)
+	FindUserByID(id uint) (*model.User, error)
+	UpdateUser(user *model.User) error
+	FindUsers(filter model.UserFilter) ([]model.User, int64, error)
+	DeleteUser(id uint) error
+	UpdatePassword(id uint, hash string) error
+	UpdateRole(id uint, role string) error
+	UpdateTenant(id uint, tenantID string) error
+	AdvanceMFAStep(id uint, step int64) (bool, error)
+	ResetMFA(id uint) error
+	SetMFASecret(id uint, secret string) error
+	EnableMFA(id uint, secret string, step int64) (bool, error)
 }
 
 type userRepository struct {
@@ -27,3 +40,100 @@ func (r *userRepository) FindUserByUsername(username string) (*model.User, error
 	err := r.db.Where("username = ?", username).First(&user).Error
 	return &user, err
 }
+
+func (r *userRepository) FindUserByID(id uint) (*model.User, error) {
+	var user model.User
+	err := r.db.First(&user, id).Error
+	return &user, err
+}
+
+func (r *userRepository) UpdateUser(user *model.User) error {
+	return r.db.Save(user).Error
+}
+
+// FindUsers mengembalikan satu halaman user beserta jumlah total yang cocok
+// dengan filter.
+func (r *userRepository) FindUsers(filter model.UserFilter) ([]model.User, int64, error) {
+	query := r.db.Model(&model.User{})
+	if filter.Query != "" {
+		query = query.Where("LOWER(username) LIKE ?", "%"+strings.ToLower(filter.Query)+"%")
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Disabled != nil {
+		query = query.Where("disabled = ?", *filter.Disabled)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []model.User
+	err := query.Order("id").Limit(filter.Limit).Offset(filter.Offset).Find(&users).Error
+	return users, total, err
+}
+
+func (r *userRepository) DeleteUser(id uint) error {
+	return r.db.Delete(&model.User{}, id).Error
+}
+
+// UpdatePassword hanya menulis kolom password, tidak seperti UpdateUser
+// yang menyimpan seluruh baris dan bisa menimpa perubahan admin yang
+// terjadi bersamaan.
+func (r *userRepository) UpdatePassword(id uint, hash string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("password", hash).Error
+}
+
+// UpdateRole hanya menulis kolom role agar perubahan lain pada user yang
+// terjadi bersamaan, misalnya dinonaktifkan, tidak tertimpa.
+func (r *userRepository) UpdateRole(id uint, role string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
+// UpdateTenant hanya menulis kolom tenant_id, seperti UpdateRole.
+func (r *userRepository) UpdateTenant(id uint, tenantID string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("tenant_id", tenantID).Error
+}
+
+// AdvanceMFAStep menyimpan step TOTP yang baru dipakai hanya jika lebih
+// besar dari step tersimpan, dalam satu UPDATE. false berarti step itu
+// sudah dipakai, termasuk oleh login lain yang berjalan bersamaan.
+func (r *userRepository) AdvanceMFAStep(id uint, step int64) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND mfa_last_step < ?", id, step).
+		Update("mfa_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ResetMFA hanya mengosongkan kolom MFA agar perubahan lain pada user yang
+// terjadi bersamaan tidak tertimpa.
+func (r *userRepository) ResetMFA(id uint) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Updates(map[string]any{
+		"mfa_secret":    "",
+		"mfa_enabled":   false,
+		"mfa_last_step": 0,
+	}).Error
+}
+
+// SetMFASecret menyimpan secret enrollment baru tanpa mengaktifkan MFA.
+func (r *userRepository) SetMFASecret(id uint, secret string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Updates(map[string]any{
+		"mfa_secret":    secret,
+		"mfa_last_step": 0,
+	}).Error
+}
+
+// EnableMFA mengaktifkan MFA hanya jika secret yang dikonfirmasi masih
+// tersimpan dan MFA belum aktif. false berarti enrollment sudah diganti
+// atau direset oleh request lain.
+func (r *userRepository) EnableMFA(id uint, secret string, step int64) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND mfa_secret = ? AND mfa_enabled = ?", id, secret, false).
+		Updates(map[string]any{
+			"mfa_enabled":   true,
+			"mfa_last_step": step,
+		})
+	return result.RowsAffected == 1, result.Error
+}
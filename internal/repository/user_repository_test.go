@@ -46,6 +46,13 @@ func TestRepositoryCreateUser(t *testing.T) {
 						sqlmock.AnyArg(),
 						args.model.Username,
 						args.model.Password,
+						model.RoleAgent,
+						args.model.Disabled,
+						args.model.TenantID,
+						args.model.Language,
+						args.model.MFASecret,
+						args.model.MFAEnabled,
+						args.model.MFALastStep,
 					).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
@@ -71,6 +78,13 @@ func TestRepositoryCreateUser(t *testing.T) {
 						sqlmock.AnyArg(),
 						args.model.Username,
 						args.model.Password,
+						model.RoleAgent,
+						args.model.Disabled,
+						args.model.TenantID,
+						args.model.Language,
+						args.model.MFASecret,
+						args.model.MFAEnabled,
+						args.model.MFALastStep,
 					).
 					WillReturnError(assert.AnError)
 
@@ -158,3 +172,200 @@ func TestRepositoryFindUserByUsername(t *testing.T) {
 		})
 	}
 }
+
+func TestRepositoryFindUserByID(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	repo := &userRepository{db: gormDB}
+
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "username", "password", "role"}).
+			AddRow(1, "admin", "hashedpassword", model.RoleAdmin)
+
+		mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1 AND "users"."deleted_at" IS NULL ORDER BY "users"."id" LIMIT \$2`).
+			WithArgs(1, 1).
+			WillReturnRows(rows)
+
+		user, err := repo.FindUserByID(1)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), user.ID)
+		assert.Equal(t, model.RoleAdmin, user.Role)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error - user not found", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1 AND "users"."deleted_at" IS NULL ORDER BY "users"."id" LIMIT \$2`).
+			WithArgs(2, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.FindUserByID(2)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryUpdateUser(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	repo := &userRepository{db: gormDB}
+
+	user := &model.User{Username: "admin", Role: model.RoleAdmin, MFASecret: "SECRET"}
+	user.ID = 1
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET (.+) WHERE "users"."deleted_at" IS NULL AND "id" = \$\d+`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.UpdateUser(user))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositoryUpdatePassword(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	repo := &userRepository{db: gormDB}
+
+	// Hanya kolom password (dan updated_at) yang ditulis
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "password"=\$1,"updated_at"=\$2 WHERE id = \$3 AND "users"."deleted_at" IS NULL`).
+		WithArgs("$argon2id$hash", sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.UpdatePassword(1, "$argon2id$hash"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositoryUpdateRole(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	repo := &userRepository{db: gormDB}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "role"=\$1,"updated_at"=\$2 WHERE id = \$3 AND "users"."deleted_at" IS NULL`).
+		WithArgs(model.RoleAdmin, sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.UpdateRole(2, model.RoleAdmin))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositoryUpdateTenant(t *testing.T) {
+	gormDB, mock := setupMockDB(t)
+	repo := &userRepository{db: gormDB}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "tenant_id"=\$1,"updated_at"=\$2 WHERE id = \$3 AND "users"."deleted_at" IS NULL`).
+		WithArgs("SBY", sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.UpdateTenant(2, "SBY"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositoryFindUsers(t *testing.T) {
+	db := setupSQLiteDB(t, &model.User{})
+
+	repo := NewUserRepository(db)
+	for _, user := range []*model.User{
+		{Username: "Agent.Smith", Role: model.RoleAgent},
+		{Username: "agent_jones", Role: model.RoleAgent, Disabled: true},
+		{Username: "root_admin", Role: model.RoleAdmin},
+	} {
+		assert.NoError(t, repo.CreateUser(user))
+	}
+
+	users, total, err := repo.FindUsers(model.UserFilter{Query: "agent", Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "Agent.Smith", users[0].Username)
+
+	disabled := true
+	users, total, err = repo.FindUsers(model.UserFilter{Disabled: &disabled, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "agent_jones", users[0].Username)
+
+	users, _, err = repo.FindUsers(model.UserFilter{Role: model.RoleAdmin, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	assert.NoError(t, repo.DeleteUser(users[0].ID))
+	_, total, err = repo.FindUsers(model.UserFilter{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestRepositoryMFAStep(t *testing.T) {
+	db := setupSQLiteDB(t, &model.User{})
+
+	repo := NewUserRepository(db)
+	user := &model.User{Username: "john_doe", Role: model.RoleAgent, MFASecret: "SECRET", MFAEnabled: true}
+	assert.NoError(t, repo.CreateUser(user))
+
+	advanced, err := repo.AdvanceMFAStep(user.ID, 100)
+	assert.NoError(t, err)
+	assert.True(t, advanced)
+
+	// Step yang sama atau lebih lama ditolak
+	for _, step := range []int64{100, 99} {
+		advanced, err = repo.AdvanceMFAStep(user.ID, step)
+		assert.NoError(t, err)
+		assert.False(t, advanced, step)
+	}
+
+	assert.NoError(t, repo.ResetMFA(user.ID))
+	found, err := repo.FindUserByID(user.ID)
+	assert.NoError(t, err)
+	assert.False(t, found.MFAEnabled)
+	assert.Empty(t, found.MFASecret)
+	assert.Zero(t, found.MFALastStep)
+	assert.Equal(t, "john_doe", found.Username)
+	assert.Equal(t, model.RoleAgent, found.Role)
+}
+
+func TestRepositoryMFAEnrollment(t *testing.T) {
+	db := setupSQLiteDB(t, &model.User{})
+
+	repo := NewUserRepository(db)
+	user := &model.User{Username: "john_doe", Role: model.RoleAgent}
+	assert.NoError(t, repo.CreateUser(user))
+
+	assert.NoError(t, repo.SetMFASecret(user.ID, "FIRST"))
+	assert.NoError(t, repo.SetMFASecret(user.ID, "SECOND"))
+
+	// Konfirmasi dengan secret yang sudah diganti ditolak
+	enabled, err := repo.EnableMFA(user.ID, "FIRST", 100)
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+
+	// Perubahan lain yang terjadi bersamaan tidak tertimpa
+	assert.NoError(t, db.Model(&model.User{}).Where("id = ?", user.ID).Update("disabled", true).Error)
+
+	enabled, err = repo.EnableMFA(user.ID, "SECOND", 100)
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+	enabled, err = repo.EnableMFA(user.ID, "SECOND", 101)
+	assert.NoError(t, err)
+	assert.False(t, enabled, "already enabled")
+
+	found, err := repo.FindUserByID(user.ID)
+	assert.NoError(t, err)
+	assert.True(t, found.MFAEnabled)
+	assert.Equal(t, "SECOND", found.MFASecret)
+	assert.Equal(t, int64(100), found.MFALastStep)
+	assert.True(t, found.Disabled)
+}
+
+func TestRepositoryCreateUserDuplicate(t *testing.T) {
+	db := setupSQLiteDB(t, &model.User{})
+
+	repo := NewUserRepository(db)
+	first := &model.User{Username: "john_doe"}
+	assert.NoError(t, repo.CreateUser(first))
+	assert.ErrorIs(t, repo.CreateUser(&model.User{Username: "john_doe"}), gorm.ErrDuplicatedKey)
+
+	// Username user yang sudah dihapus boleh dipakai lagi
+	assert.NoError(t, repo.DeleteUser(first.ID))
+	assert.NoError(t, repo.CreateUser(&model.User{Username: "john_doe"}))
+}
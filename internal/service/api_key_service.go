@@ -0,0 +1,177 @@
+package service
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/repository"
+	"github.com/danisasmita/customer-search/pkg/message"
+)
+
+const (
+	// apiKeyPrefix membuat key mudah dikenali oleh secret scanner.
+	apiKeyPrefix       = "csk_"
+	apiKeyDisplayChars = 12
+
+	// lastUsedResolution membatasi penulisan last_used_at agar request
+	// beruntun dengan key yang sama tidak selalu menulis ke database.
+	lastUsedResolution = time.Minute
+)
+
+var (
+	ErrAPIKeyNotFound     = NewError(ErrNotFound, message.CodeAPIKeyNotFound, message.APIKeyNotFound)
+	ErrInvalidAPIKey      = NewError(ErrUnauthorized, message.CodeUnauthorized, message.Unauthorized)
+	ErrInvalidScope       = &Error{Kind: ErrValidation, Code: message.CodeInvalidScope, Message: message.InvalidScope, Details: map[string]interface{}{"scopes": model.APIKeyScopes}}
+	ErrInvalidExpiry      = NewError(ErrValidation, message.CodeInvalidExpiry, message.InvalidExpiry)
+	ErrAPIKeyNameRequired = NewValidationError(message.CodeValidationFailed, message.ValidationFailed,
+		FieldError{Field: "name", Code: message.CodeRequired, Message: message.APIKeyNameRequired})
+)
+
+// APIKeyService mengelola API key untuk akses antar layanan. Nilai key
+// hanya dikembalikan sekali saat dibuat.
+type APIKeyService interface {
+	Create(request model.CreateAPIKeyRequest, adminID uint) (*model.APIKeyResponse, error)
+	List(userID uint) ([]model.APIKeyResponse, error)
+	Revoke(id, adminID uint) error
+	Authenticate(key string) (*model.APIKey, error)
+}
+
+type apiKeyService struct {
+	keys   repository.APIKeyRepository
+	users  repository.UserRepository
+	audit  AuditService
+	logger *slog.Logger
+}
+
+func NewAPIKeyService(keys repository.APIKeyRepository, users repository.UserRepository, audit AuditService, logger *slog.Logger) APIKeyService {
+	return &apiKeyService{keys: keys, users: users, audit: audit, logger: logger}
+}
+
+func (s *apiKeyService) Create(request model.CreateAPIKeyRequest, adminID uint) (*model.APIKeyResponse, error) {
+	if strings.TrimSpace(request.Name) == "" {
+		return nil, ErrAPIKeyNameRequired
+	}
+	if len(request.Scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+	for _, scope := range request.Scopes {
+		if !model.ValidScope(scope) {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	var expiresAt *time.Time
+	if request.ExpiresIn != "" {
+		ttl, err := time.ParseDuration(request.ExpiresIn)
+		if err != nil || ttl <= 0 {
+			return nil, ErrInvalidExpiry
+		}
+		at := time.Now().Add(ttl)
+		expiresAt = &at
+	}
+
+	if request.UserID == 0 {
+		request.UserID = adminID
+	}
+	user, err := s.users.FindUserByID(request.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	plain := apiKeyPrefix + token
+
+	key := &model.APIKey{
+		Name:      request.Name,
+		UserID:    user.ID,
+		CreatedBy: adminID,
+		Prefix:    plain[:apiKeyDisplayChars],
+		KeyHash:   hashToken(plain),
+		Scopes:    strings.Join(request.Scopes, ","),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.keys.Create(key); err != nil {
+		return nil, err
+	}
+
+	err = recordAdminAction(s.audit, model.AuditActionAPIKeyCreate, adminID, "api_key_id", key.ID, map[string]string{
+		"user_id": formatID(user.ID),
+		"scopes":  key.Scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := model.NewAPIKeyResponse(key)
+	response.Key = plain
+	return &response, nil
+}
+
+func (s *apiKeyService) List(userID uint) ([]model.APIKeyResponse, error) {
+	keys, err := s.keys.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		responses = append(responses, model.NewAPIKeyResponse(&keys[i]))
+	}
+	return responses, nil
+}
+
+// Revoke pada key yang sudah dicabut tetap dianggap berhasil.
+func (s *apiKeyService) Revoke(id, adminID uint) error {
+	key, err := s.keys.FindByID(id)
+	if err != nil {
+		return ErrAPIKeyNotFound
+	}
+
+	revoked, err := s.keys.Revoke(key.ID, time.Now())
+	if err != nil || !revoked {
+		return err
+	}
+
+	return recordAdminAction(s.audit, model.AuditActionAPIKeyRevoke, adminID, "api_key_id", key.ID, map[string]string{
+		"user_id": formatID(key.UserID),
+	})
+}
+
+// Authenticate menolak key yang dicabut, kedaluwarsa, atau milik user yang
+// sudah dinonaktifkan maupun dihapus.
+func (s *apiKeyService) Authenticate(plain string) (*model.APIKey, error) {
+	if !strings.HasPrefix(plain, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.keys.FindByHash(hashToken(plain))
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.users.FindUserByID(key.UserID)
+	if err != nil || user.Disabled {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.keys.TouchLastUsed(key.ID, now); err != nil {
+			s.logger.Error("failed to update api key last used", "api_key_id", key.ID, "error", err)
+		}
+		key.LastUsedAt = &now
+	}
+	return key, nil
+}
@@ -0,0 +1,159 @@
+package service_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type memoryAPIKeyRepository struct {
+	keys []*model.APIKey
+}
+
+func (r *memoryAPIKeyRepository) Create(key *model.APIKey) error {
+	key.ID = uint(len(r.keys) + 1)
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) FindByID(id uint) (*model.APIKey, error) {
+	for _, key := range r.keys {
+		if key.ID == id {
+			found := *key
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryAPIKeyRepository) FindByHash(keyHash string) (*model.APIKey, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			found := *key
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryAPIKeyRepository) List(userID uint) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	for _, key := range r.keys {
+		if userID == 0 || key.UserID == userID {
+			keys = append(keys, *key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *memoryAPIKeyRepository) Revoke(id uint, at time.Time) (bool, error) {
+	for _, key := range r.keys {
+		if key.ID == id && key.RevokedAt == nil {
+			key.RevokedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryAPIKeyRepository) TouchLastUsed(id uint, at time.Time) error {
+	for _, key := range r.keys {
+		if key.ID == id {
+			key.LastUsedAt = &at
+		}
+	}
+	return nil
+}
+
+func newTestAPIKeyService() (*MockUserRepository, *memoryAPIKeyRepository, *memoryAuditRepository, service.APIKeyService) {
+	users := new(MockUserRepository)
+	keys := &memoryAPIKeyRepository{}
+	audits := &memoryAuditRepository{}
+	return users, keys, audits, service.NewAPIKeyService(keys, users, service.NewAuditService(audits), logging.Discard())
+}
+
+func TestAPIKeyServiceCreate(t *testing.T) {
+	users, keys, audits, apiKeys := newTestAPIKeyService()
+	users.On("FindUserByID", uint(7)).Return(&model.User{Model: gorm.Model{ID: 7}, Username: "batch_job"}, nil)
+	users.On("FindUserByID", uint(8)).Return(&model.User{Model: gorm.Model{ID: 8}, Disabled: true}, nil)
+
+	_, err := apiKeys.Create(model.CreateAPIKeyRequest{UserID: 7, Scopes: []string{model.ScopeCustomersRead}}, 1)
+	assert.ErrorIs(t, err, service.ErrAPIKeyNameRequired)
+
+	_, err = apiKeys.Create(model.CreateAPIKeyRequest{Name: "batch", UserID: 7, Scopes: []string{"customers:write"}}, 1)
+	assert.ErrorIs(t, err, service.ErrInvalidScope)
+
+	_, err = apiKeys.Create(model.CreateAPIKeyRequest{Name: "batch", UserID: 7, Scopes: []string{model.ScopeCustomersRead}, ExpiresIn: "soon"}, 1)
+	assert.ErrorIs(t, err, service.ErrInvalidExpiry)
+
+	_, err = apiKeys.Create(model.CreateAPIKeyRequest{Name: "batch", UserID: 8, Scopes: []string{model.ScopeCustomersRead}}, 1)
+	assert.ErrorIs(t, err, service.ErrUserDisabled)
+
+	created, err := apiKeys.Create(model.CreateAPIKeyRequest{Name: "batch", UserID: 7, Scopes: []string{model.ScopeCustomersRead}, ExpiresIn: "720h"}, 1)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+	assert.Equal(t, []string{model.ScopeCustomersRead}, created.Scopes)
+	assert.NotNil(t, created.ExpiresAt)
+
+	// Hanya hash yang disimpan.
+	assert.NotEqual(t, created.Key, keys.keys[0].KeyHash)
+	assert.NotContains(t, keys.keys[0].KeyHash, created.Key)
+
+	listed, err := apiKeys.List(7)
+	assert.NoError(t, err)
+	if assert.Len(t, listed, 1) {
+		assert.Empty(t, listed[0].Key)
+	}
+
+	if assert.Len(t, audits.logs, 1) {
+		assert.Equal(t, model.AuditActionAPIKeyCreate, audits.logs[0].Action)
+		assert.JSONEq(t, `{"api_key_id":"1","user_id":"7","scopes":"customers:read"}`, audits.logs[0].Query)
+	}
+}
+
+func TestAPIKeyServiceAuthenticate(t *testing.T) {
+	users, keys, audits, apiKeys := newTestAPIKeyService()
+	owner := &model.User{Model: gorm.Model{ID: 7}, Username: "batch_job"}
+	users.On("FindUserByID", uint(7)).Return(owner, nil)
+
+	created, err := apiKeys.Create(model.CreateAPIKeyRequest{Name: "batch", UserID: 7, Scopes: []string{model.ScopeCustomersRead}}, 1)
+	assert.NoError(t, err)
+
+	key, err := apiKeys.Authenticate(created.Key)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), key.UserID)
+	assert.NotNil(t, keys.keys[0].LastUsedAt)
+
+	_, err = apiKeys.Authenticate(created.Key + "x")
+	assert.ErrorIs(t, err, service.ErrInvalidAPIKey)
+	_, err = apiKeys.Authenticate("not-a-key")
+	assert.ErrorIs(t, err, service.ErrInvalidAPIKey)
+
+	owner.Disabled = true
+	_, err = apiKeys.Authenticate(created.Key)
+	assert.ErrorIs(t, err, service.ErrInvalidAPIKey)
+	owner.Disabled = false
+
+	expired := time.Now().Add(-time.Minute)
+	keys.keys[0].ExpiresAt = &expired
+	_, err = apiKeys.Authenticate(created.Key)
+	assert.ErrorIs(t, err, service.ErrInvalidAPIKey)
+	keys.keys[0].ExpiresAt = nil
+
+	assert.NoError(t, apiKeys.Revoke(created.ID, 1))
+	assert.NoError(t, apiKeys.Revoke(created.ID, 1))
+	_, err = apiKeys.Authenticate(created.Key)
+	assert.ErrorIs(t, err, service.ErrInvalidAPIKey)
+
+	assert.ErrorIs(t, apiKeys.Revoke(99, 1), service.ErrAPIKeyNotFound)
+
+	// Revoke kedua tidak dicatat ulang.
+	assert.Len(t, audits.logs, 2)
+	assert.Equal(t, model.AuditActionAPIKeyRevoke, audits.logs[1].Action)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/repository"
+	"github.com/danisasmita/customer-search/pkg/message"
+)
+
+const auditVerifyBatchSize = 500
+
+var errStopWalk = errors.New("stop walk")
+
+type AuditService interface {
+	Record(entry *model.AuditLog) error
+	Query(filter model.AuditFilter) ([]model.AuditLog, error)
+	Verify() (*model.AuditVerification, error)
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+	mu   sync.Mutex
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+// Record menambahkan entry ke audit log dan merangkainya dengan hash
+// entry sebelumnya.
+func (s *auditService) Record(entry *model.AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Postgres menyimpan timestamp dalam mikrodetik, jadi presisi yang
+	// di-hash harus sama dengan yang tersimpan.
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	return s.repo.Append(entry, func(prev *model.AuditLog) {
+		entry.Seq = 1
+		entry.PrevHash = ""
+		if prev != nil {
+			entry.Seq = prev.Seq + 1
+			entry.PrevHash = prev.Hash
+		}
+		entry.Hash = hashAuditLog(entry)
+	})
+}
+
+func (s *auditService) Query(filter model.AuditFilter) ([]model.AuditLog, error) {
+	return s.repo.Find(filter)
+}
+
+// Verify memeriksa seluruh rantai dari Seq 1: urutan tidak boleh bolong,
+// PrevHash harus sama dengan Hash baris sebelumnya, dan Hash harus cocok
+// dengan isi baris.
+func (s *auditService) Verify() (*model.AuditVerification, error) {
+	result := &model.AuditVerification{Valid: true}
+	expectedSeq := uint64(1)
+	prevHash := ""
+
+	err := s.repo.Walk(auditVerifyBatchSize, func(batch []model.AuditLog) error {
+		for i := range batch {
+			entry := &batch[i]
+			switch {
+			case entry.Seq != expectedSeq:
+				result.Reason = message.AuditSequenceGap
+			case entry.PrevHash != prevHash:
+				result.Reason = message.AuditPrevHashMismatch
+			case entry.Hash != hashAuditLog(entry):
+				result.Reason = message.AuditHashMismatch
+			}
+			if result.Reason != "" {
+				result.Valid = false
+				result.BrokenAt = entry.Seq
+				return errStopWalk
+			}
+
+			result.Checked++
+			expectedSeq++
+			prevHash = entry.Hash
+		}
+		return nil
+	})
+	if err != nil && err != errStopWalk {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type auditPayload struct {
+	Seq       uint64 `json:"seq"`
+	CreatedAt string `json:"created_at"`
+	UserID    uint   `json:"user_id"`
+	ActorID   uint   `json:"actor_id,omitempty"`
+	Action    string `json:"action"`
+	Query     string `json:"query,omitempty"`
+	ResultIDs string `json:"result_ids,omitempty"`
+	ClientIP  string `json:"client_ip,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Review    bool   `json:"review_required,omitempty"`
+	PrevHash  string `json:"prev_hash"`
+}
+
+// hashAuditLog menghitung SHA-256 dari representasi JSON kanonik entry.
+// Field opsional memakai omitempty agar penambahan field baru tidak
+// mengubah hash baris lama yang tidak mengisinya.
+func hashAuditLog(entry *model.AuditLog) string {
+	payload, _ := json.Marshal(auditPayload{
+		Seq:       entry.Seq,
+		CreatedAt: entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		UserID:    entry.UserID,
+		ActorID:   entry.ActorID,
+		Action:    entry.Action,
+		Query:     entry.Query,
+		ResultIDs: entry.ResultIDs,
+		ClientIP:  entry.ClientIP,
+		Reason:    entry.Reason,
+		Review:    entry.ReviewRequired,
+		PrevHash:  entry.PrevHash,
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// recordAdminAction mencatat perubahan yang dilakukan admin atas satu
+// objek. Query berisi ID objek di bawah targetKey ditambah details.
+func recordAdminAction(audit AuditService, action string, adminID uint, targetKey string, targetID uint, details map[string]string) error {
+	target := formatID(targetID)
+	query := map[string]string{targetKey: target}
+	for key, value := range details {
+		query[key] = value
+	}
+
+	encoded, err := json.Marshal(query)
+	if err != nil {
+		return err
+	}
+	return audit.Record(&model.AuditLog{
+		UserID:    adminID,
+		Action:    action,
+		Query:     string(encoded),
+		ResultIDs: target,
+	})
+}
+
+func formatID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
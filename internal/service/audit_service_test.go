@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryAuditRepository menyimpan audit log di memori agar rantai hash
+// bisa dimanipulasi langsung saat pengujian.
+type memoryAuditRepository struct {
+	logs []model.AuditLog
+}
+
+func (r *memoryAuditRepository) Append(entry *model.AuditLog, seal func(prev *model.AuditLog)) error {
+	if len(r.logs) == 0 {
+		seal(nil)
+	} else {
+		seal(&r.logs[len(r.logs)-1])
+	}
+	entry.ID = uint(len(r.logs) + 1)
+	r.logs = append(r.logs, *entry)
+	return nil
+}
+
+func (r *memoryAuditRepository) Find(filter model.AuditFilter) ([]model.AuditLog, error) {
+	var result []model.AuditLog
+	for _, entry := range r.logs {
+		if filter.UserID == 0 || entry.UserID == filter.UserID {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryAuditRepository) Walk(batchSize int, fn func(batch []model.AuditLog) error) error {
+	for start := 0; start < len(r.logs); start += batchSize {
+		end := min(start+batchSize, len(r.logs))
+		batch := append([]model.AuditLog(nil), r.logs[start:end]...)
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func seedAuditLog(t *testing.T, n int) (*memoryAuditRepository, service.AuditService) {
+	repo := &memoryAuditRepository{}
+	auditService := service.NewAuditService(repo)
+	for i := 0; i < n; i++ {
+		err := auditService.Record(&model.AuditLog{
+			UserID:    uint(i%2 + 1),
+			Action:    model.AuditActionCustomerSearch,
+			Query:     `{"name":"John"}`,
+			ResultIDs: "1,2",
+			ClientIP:  "10.0.0.1",
+		})
+		assert.NoError(t, err)
+	}
+	return repo, auditService
+}
+
+func TestAuditServiceRecord(t *testing.T) {
+	repo, _ := seedAuditLog(t, 3)
+
+	assert.Len(t, repo.logs, 3)
+	assert.Equal(t, uint64(1), repo.logs[0].Seq)
+	assert.Empty(t, repo.logs[0].PrevHash)
+	assert.NotEmpty(t, repo.logs[0].Hash)
+	assert.Equal(t, uint64(3), repo.logs[2].Seq)
+	assert.Equal(t, repo.logs[1].Hash, repo.logs[2].PrevHash)
+}
+
+func TestAuditServiceQuery(t *testing.T) {
+	_, auditService := seedAuditLog(t, 4)
+
+	logs, err := auditService.Query(model.AuditFilter{UserID: 2})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+}
+
+func TestAuditServiceVerify(t *testing.T) {
+	t.Run("success - intact chain", func(t *testing.T) {
+		_, auditService := seedAuditLog(t, 5)
+
+		result, err := auditService.Verify()
+		assert.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, 5, result.Checked)
+	})
+
+	t.Run("error - tampered row", func(t *testing.T) {
+		repo, auditService := seedAuditLog(t, 5)
+		repo.logs[2].ResultIDs = "1"
+
+		result, err := auditService.Verify()
+		assert.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, uint64(3), result.BrokenAt)
+		assert.Equal(t, message.AuditHashMismatch, result.Reason)
+	})
+
+	t.Run("error - deleted row", func(t *testing.T) {
+		repo, auditService := seedAuditLog(t, 5)
+		repo.logs = append(repo.logs[:1], repo.logs[2:]...)
+
+		result, err := auditService.Verify()
+		assert.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, uint64(3), result.BrokenAt)
+		assert.Equal(t, message.AuditSequenceGap, result.Reason)
+	})
+
+	t.Run("error - rewritten chain", func(t *testing.T) {
+		repo, auditService := seedAuditLog(t, 3)
+		repo.logs[1].PrevHash = "forged"
+
+		result, err := auditService.Verify()
+		assert.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, uint64(2), result.BrokenAt)
+		assert.Equal(t, message.AuditPrevHashMismatch, result.Reason)
+	})
+}
@@ -2,57 +2,195 @@ package service
 
 import (
 	"errors"
+	"log/slog"
 
 	"github.com/danisasmita/customer-search/internal/model"
 	"github.com/danisasmita/customer-search/internal/repository"
 	"github.com/danisasmita/customer-search/pkg/message"
 	"github.com/danisasmita/customer-search/pkg/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound       = NewError(ErrNotFound, message.CodeUserNotFound, message.UserNotFound)
+	ErrInvalidCredentials = NewError(ErrUnauthorized, message.CodeInvalidCredentials, message.InvalidCredentials)
+	// ErrMFALoginFailed dipakai saat kode MFA salah pada login, sehingga
+	// dijawab 401 dan bukan 400 seperti pada alur enrollment.
+	ErrMFALoginFailed = NewError(ErrUnauthorized, message.CodeInvalidMFACode, message.InvalidMFACode)
 )
 
 type AuthService interface {
 	Register(user *model.User) error
-	Login(request model.UserRequest) (string, error)
+	Login(request model.UserRequest, clientIP string) (*model.LoginResponse, error)
+	LoginMFA(request model.MFALoginRequest, clientIP string) (*model.LoginResponse, error)
+	Refresh(refreshToken string) (*model.LoginResponse, error)
+	Logout(claims *utils.Claims, refreshToken string) error
+	RevokeSessions(userID uint) error
+	Unlock(userID, adminID uint) error
 }
 
 type authService struct {
-	repo repository.UserRepository
+	repo     repository.UserRepository
+	tokens   TokenService
+	throttle LoginThrottle
+	mfa      MFAService
+	policy   PasswordPolicy
+	logger   *slog.Logger
 }
 
-func NewAuthService(repo repository.UserRepository) AuthService {
-	return &authService{repo: repo}
+func NewAuthService(repo repository.UserRepository, tokens TokenService, throttle LoginThrottle, mfa MFAService, policy PasswordPolicy, logger *slog.Logger) AuthService {
+	return &authService{repo: repo, tokens: tokens, throttle: throttle, mfa: mfa, policy: policy, logger: logger}
 }
 
+// Register menolak username yang sudah dipakai dengan ErrUsernameTaken,
+// termasuk saat dua pendaftaran bersamaan lolos pengecekan awal dan
+// ditolak unique index. Field wajib sudah diperiksa oleh validasi request
+// di handler.
 func (s *authService) Register(user *model.User) error {
-	if user.Username == "" || user.Password == "" {
-		return errors.New(message.UsernameRequired + " and " + message.PasswordRequired)
+	if _, err := s.repo.FindUserByUsername(user.Username); err == nil {
+		return ErrUsernameTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err := s.policy.Validate(user.Username, user.Password); err != nil {
+		return err
 	}
 
 	hashedPassword, err := utils.HashPassword(user.Password)
 	if err != nil {
-		return errors.New(message.InternalServerError)
+		return err
 	}
 	user.Password = hashedPassword
-	return s.repo.CreateUser(user)
+	if err := s.repo.CreateUser(user); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrUsernameTaken
+		}
+		return err
+	}
+	return nil
 }
 
-func (s *authService) Login(request model.UserRequest) (string, error) {
-	if request.Username == "" || request.Password == "" {
-		return "", errors.New(message.UsernameRequired + " and " + message.PasswordRequired)
+func (s *authService) Login(request model.UserRequest, clientIP string) (*model.LoginResponse, error) {
+	if err := s.throttle.Check(request.Username, clientIP); err != nil {
+		return nil, err
 	}
 
+	// User yang tidak ada dijawab sama dengan password salah, termasuk
+	// waktu hashing-nya, agar username terdaftar tidak bocor.
 	user, err := s.repo.FindUserByUsername(request.Username)
 	if err != nil {
-		return "", errors.New(message.UserNotFound)
+		utils.VerifyPassword(request.Password, utils.DummyPasswordHash())
+		s.recordFailure(request.Username, clientIP)
+		return nil, ErrInvalidCredentials
+	}
+
+	match, needsRehash := utils.VerifyPassword(request.Password, user.Password)
+	if !match {
+		s.recordFailure(request.Username, clientIP)
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+	if needsRehash {
+		s.rehash(user, request.Password)
 	}
 
-	if !utils.CheckPasswordHash(request.Password, user.Password) {
-		return "", errors.New(message.InvalidCredentials)
+	// Hitungan gagal baru direset setelah langkah MFA selesai.
+	if user.MFAEnabled {
+		challenge, err := s.mfa.Challenge(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.LoginResponse{MFARequired: true, MFAToken: challenge}, nil
 	}
 
-	token, err := utils.GenerateJWT(user.ID)
+	return s.issue(user)
+}
+
+// LoginMFA adalah langkah kedua login untuk user yang sudah mendaftarkan
+// MFA. Kode yang salah dihitung sebagai kegagalan login.
+func (s *authService) LoginMFA(request model.MFALoginRequest, clientIP string) (*model.LoginResponse, error) {
+	userID, err := s.mfa.ValidateChallenge(request.MFAToken)
 	if err != nil {
-		return "", errors.New(message.InternalServerError)
+		return nil, err
 	}
 
-	return token, nil
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	if err := s.throttle.Check(user.Username, clientIP); err != nil {
+		return nil, err
+	}
+
+	if err := s.mfa.Verify(user, request.Code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			s.recordFailure(user.Username, clientIP)
+			return nil, ErrMFALoginFailed
+		}
+		return nil, err
+	}
+
+	return s.issue(user)
+}
+
+func (s *authService) issue(user *model.User) (*model.LoginResponse, error) {
+	if err := s.throttle.RecordSuccess(user.Username); err != nil {
+		s.logger.Error("failed to reset login attempts", "user_id", user.ID, "error", err)
+	}
+
+	return s.tokens.Issue(user.ID, user.TenantID)
+}
+
+func (s *authService) Refresh(refreshToken string) (*model.LoginResponse, error) {
+	return s.tokens.Refresh(refreshToken)
+}
+
+func (s *authService) Logout(claims *utils.Claims, refreshToken string) error {
+	return s.tokens.Logout(claims, refreshToken)
+}
+
+func (s *authService) Unlock(userID, adminID uint) error {
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	return s.throttle.Unlock(user.Username, adminID)
+}
+
+// rehash menyimpan ulang hash dengan algoritma dan parameter aktif.
+// Kegagalan hanya dicatat karena password lama masih valid.
+func (s *authService) rehash(user *model.User, password string) {
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		s.logger.Error("failed to rehash password", "user_id", user.ID, "error", err)
+		return
+	}
+
+	user.Password = hashedPassword
+	if err := s.repo.UpdatePassword(user.ID, hashedPassword); err != nil {
+		s.logger.Error("failed to save rehashed password", "user_id", user.ID, "error", err)
+	}
+}
+
+// recordFailure tidak menggagalkan login jika pencatatan error; respons
+// tetap kredensial salah.
+func (s *authService) recordFailure(username, clientIP string) {
+	if err := s.throttle.RecordFailure(username, clientIP); err != nil {
+		s.logger.Error("failed to record login failure", "error", err)
+	}
+}
+
+func (s *authService) RevokeSessions(userID uint) error {
+	if _, err := s.repo.FindUserByID(userID); err != nil {
+		return ErrUserNotFound
+	}
+	return s.tokens.RevokeUser(userID)
 }
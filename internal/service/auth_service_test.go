@@ -2,17 +2,22 @@ package service_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/danisasmita/customer-search/internal/model"
 	"github.com/danisasmita/customer-search/internal/service"
-	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/logging"
 	"github.com/danisasmita/customer-search/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+const clientIP = "10.0.0.1"
+
 // MockUserRepository adalah mock untuk UserRepository
 type MockUserRepository struct {
 	mock.Mock
@@ -28,17 +33,71 @@ func (m *MockAuthService) Register(user *model.User) error {
 }
 
 // Mock metode FindUserByUsername
-func (m *MockAuthService) Login(request model.UserRequest) (string, error) {
-	args := m.Called(request)
-	return args.String(0), args.Error(1)
+func (m *MockAuthService) Login(request model.UserRequest, clientIP string) (*model.LoginResponse, error) {
+	args := m.Called(request, clientIP)
+	tokens, _ := args.Get(0).(*model.LoginResponse)
+	return tokens, args.Error(1)
+}
+
+// MockTokenService adalah mock untuk TokenService
+type MockTokenService struct {
+	mock.Mock
+}
+
+func (m *MockTokenService) Issue(userID uint, tenantID string) (*model.LoginResponse, error) {
+	args := m.Called(userID, tenantID)
+	tokens, _ := args.Get(0).(*model.LoginResponse)
+	return tokens, args.Error(1)
+}
+
+func (m *MockTokenService) Refresh(refreshToken string) (*model.LoginResponse, error) {
+	args := m.Called(refreshToken)
+	tokens, _ := args.Get(0).(*model.LoginResponse)
+	return tokens, args.Error(1)
+}
+
+func (m *MockTokenService) Logout(claims *utils.Claims, refreshToken string) error {
+	args := m.Called(claims, refreshToken)
+	return args.Error(0)
+}
+
+func (m *MockTokenService) RevokeUser(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// MockLoginThrottle adalah mock untuk LoginThrottle
+type MockLoginThrottle struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottle) Check(username, clientIP string) error {
+	args := m.Called(username, clientIP)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottle) RecordFailure(username, clientIP string) error {
+	args := m.Called(username, clientIP)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottle) RecordSuccess(username string) error {
+	args := m.Called(username)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottle) Unlock(username string, adminID uint) error {
+	args := m.Called(username, adminID)
+	return args.Error(0)
 }
 
 func TestAuthServiceRegister(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	authService := service.NewAuthService(mockRepo)
+	authService := service.NewAuthService(mockRepo, new(MockTokenService), new(MockLoginThrottle), nil, service.PasswordPolicy{}, logging.Discard())
 
 	t.Run("success - register user", func(t *testing.T) {
 		user := &model.User{Username: "john_doe", Password: "password123"}
+		mockRepo.On("FindUserByUsername", "john_doe").Return((*model.User)(nil), gorm.ErrRecordNotFound).Once()
 		mockRepo.On("CreateUser", user).Return(nil)
 
 		err := authService.Register(user)
@@ -46,10 +105,32 @@ func TestAuthServiceRegister(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
-	t.Run("error - missing username or password", func(t *testing.T) {
-		user := &model.User{}
+	t.Run("error - username taken", func(t *testing.T) {
+		mockRepo.On("FindUserByUsername", "taken").Return(&model.User{Username: "taken"}, nil).Once()
+		err := authService.Register(&model.User{Username: "taken", Password: "password123"})
+		assert.ErrorIs(t, err, service.ErrUsernameTaken)
+		assert.ErrorIs(t, err, service.ErrConflict)
+	})
+
+	t.Run("error - username taken concurrently", func(t *testing.T) {
+		user := &model.User{Username: "racer", Password: "password123"}
+		mockRepo.On("FindUserByUsername", "racer").Return((*model.User)(nil), gorm.ErrRecordNotFound).Once()
+		mockRepo.On("CreateUser", user).Return(gorm.ErrDuplicatedKey).Once()
+
 		err := authService.Register(user)
-		assert.EqualError(t, err, message.UsernameRequired+" and "+message.PasswordRequired)
+		assert.ErrorIs(t, err, service.ErrUsernameTaken)
+	})
+
+	t.Run("error - password policy", func(t *testing.T) {
+		strict := service.NewAuthService(mockRepo, new(MockTokenService), new(MockLoginThrottle), nil, service.PasswordPolicy{MinLength: 12}, logging.Discard())
+		mockRepo.On("FindUserByUsername", "jane_doe").Return((*model.User)(nil), gorm.ErrRecordNotFound).Once()
+		err := strict.Register(&model.User{Username: "jane_doe", Password: "short"})
+
+		var policyErr *service.PasswordPolicyError
+		assert.ErrorAs(t, err, &policyErr)
+		mockRepo.AssertNotCalled(t, "CreateUser", mock.MatchedBy(func(user *model.User) bool {
+			return user.Username == "jane_doe"
+		}))
 	})
 }
 
@@ -64,9 +145,66 @@ func (m *MockUserRepository) CreateUser(user *model.User) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) FindUserByID(id uint) (*model.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateUser(user *model.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindUsers(filter model.UserFilter) ([]model.User, int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]model.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) DeleteUser(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassword(id uint, hash string) error {
+	args := m.Called(id, hash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) AdvanceMFAStep(id uint, step int64) (bool, error) {
+	args := m.Called(id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) ResetMFA(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateRole(id uint, role string) error {
+	args := m.Called(id, role)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateTenant(id uint, tenantID string) error {
+	args := m.Called(id, tenantID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetMFASecret(id uint, secret string) error {
+	args := m.Called(id, secret)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) EnableMFA(id uint, secret string, step int64) (bool, error) {
+	args := m.Called(id, secret, step)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestAuthServiceLogin(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	authService := service.NewAuthService(mockRepo)
+	mockTokens := new(MockTokenService)
+	mockThrottle := new(MockLoginThrottle)
+	authService := service.NewAuthService(mockRepo, mockTokens, mockThrottle, nil, service.PasswordPolicy{}, logging.Discard())
 
 	// Hash password untuk simulasi user di database
 	hashedPassword, err := utils.HashPassword("password123")
@@ -83,23 +221,108 @@ func TestAuthServiceLogin(t *testing.T) {
 	// Mocking repository behavior
 	mockRepo.On("FindUserByUsername", "john_doe").Return(validUser, nil)
 	mockRepo.On("FindUserByUsername", "unknown").Return((*model.User)(nil), errors.New("not found"))
+	mockThrottle.On("Check", mock.Anything, clientIP).Return(nil)
+	mockThrottle.On("RecordFailure", mock.Anything, clientIP).Return(nil)
+	mockThrottle.On("RecordSuccess", "john_doe").Return(nil)
 
 	t.Run("success - login", func(t *testing.T) {
+		mockTokens.On("Issue", uint(1), "").Return(&model.LoginResponse{AccessToken: "access", RefreshToken: "refresh"}, nil).Once()
+
 		request := model.UserRequest{Username: "john_doe", Password: "password123"}
-		token, err := authService.Login(request)
+		tokens, err := authService.Login(request, clientIP)
 		assert.NoError(t, err)
-		assert.NotEmpty(t, token)
+		assert.Equal(t, "access", tokens.AccessToken)
+		assert.Equal(t, "refresh", tokens.RefreshToken)
+		mockTokens.AssertExpectations(t)
 	})
 
 	t.Run("error - user not found", func(t *testing.T) {
 		request := model.UserRequest{Username: "unknown", Password: "password123"}
-		_, err := authService.Login(request)
-		assert.EqualError(t, err, message.UserNotFound)
+		_, err := authService.Login(request, clientIP)
+		assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+		mockThrottle.AssertCalled(t, "RecordFailure", "unknown", clientIP)
 	})
 
 	t.Run("error - wrong password", func(t *testing.T) {
 		request := model.UserRequest{Username: "john_doe", Password: "wrongpassword"}
-		_, err := authService.Login(request)
-		assert.EqualError(t, err, message.InvalidCredentials)
+		_, err := authService.Login(request, clientIP)
+		assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+		mockThrottle.AssertCalled(t, "RecordFailure", "john_doe", clientIP)
+	})
+
+	t.Run("error - throttled", func(t *testing.T) {
+		throttle := new(MockLoginThrottle)
+		throttled := service.NewAuthService(mockRepo, mockTokens, throttle, nil, service.PasswordPolicy{}, logging.Discard())
+		throttle.On("Check", "john_doe", clientIP).Return(&service.LoginThrottledError{RetryAfter: time.Minute})
+
+		request := model.UserRequest{Username: "john_doe", Password: "password123"}
+		_, err := throttled.Login(request, clientIP)
+
+		var throttledErr *service.LoginThrottledError
+		assert.ErrorAs(t, err, &throttledErr)
+		assert.Equal(t, time.Minute, throttledErr.RetryAfter)
+	})
+}
+
+func TestAuthServiceLoginRehash(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	user := &model.User{Model: gorm.Model{ID: 1}, Username: "john_doe", Password: string(legacy)}
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindUserByUsername", "john_doe").Return(user, nil)
+	mockRepo.On("UpdatePassword", uint(1), mock.MatchedBy(func(hash string) bool {
+		return strings.HasPrefix(hash, "$argon2id$")
+	})).Return(nil).Once()
+
+	mockTokens := new(MockTokenService)
+	mockTokens.On("Issue", uint(1), "").Return(&model.LoginResponse{AccessToken: "access"}, nil)
+	mockThrottle := new(MockLoginThrottle)
+	mockThrottle.On("Check", "john_doe", clientIP).Return(nil)
+	mockThrottle.On("RecordSuccess", "john_doe").Return(nil)
+
+	authService := service.NewAuthService(mockRepo, mockTokens, mockThrottle, nil, service.PasswordPolicy{}, logging.Discard())
+	_, err = authService.Login(model.UserRequest{Username: "john_doe", Password: "password123"}, clientIP)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// Hash baru tetap cocok dengan password yang sama
+	assert.True(t, utils.CheckPasswordHash("password123", user.Password))
+}
+
+func TestAuthServiceLoginDisabledNotRehashed(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	user := &model.User{Model: gorm.Model{ID: 1}, Username: "john_doe", Password: string(legacy), Disabled: true}
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindUserByUsername", "john_doe").Return(user, nil)
+	mockThrottle := new(MockLoginThrottle)
+	mockThrottle.On("Check", "john_doe", clientIP).Return(nil)
+
+	authService := service.NewAuthService(mockRepo, new(MockTokenService), mockThrottle, nil, service.PasswordPolicy{}, logging.Discard())
+	_, err = authService.Login(model.UserRequest{Username: "john_doe", Password: "password123"}, clientIP)
+	assert.ErrorIs(t, err, service.ErrUserDisabled)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+	assert.Equal(t, string(legacy), user.Password)
+}
+
+func TestAuthServiceRevokeSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokens := new(MockTokenService)
+	authService := service.NewAuthService(mockRepo, mockTokens, new(MockLoginThrottle), nil, service.PasswordPolicy{}, logging.Discard())
+
+	mockRepo.On("FindUserByID", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}}, nil)
+	mockRepo.On("FindUserByID", uint(2)).Return((*model.User)(nil), errors.New("not found"))
+
+	t.Run("success - revoke user", func(t *testing.T) {
+		mockTokens.On("RevokeUser", uint(1)).Return(nil).Once()
+
+		assert.NoError(t, authService.RevokeSessions(1))
+		mockTokens.AssertExpectations(t)
+	})
+
+	t.Run("error - user not found", func(t *testing.T) {
+		assert.ErrorIs(t, authService.RevokeSessions(2), service.ErrUserNotFound)
 	})
 }
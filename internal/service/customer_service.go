@@ -1,12 +1,31 @@
 package service
 
 import (
+	"context"
+	"errors"
+
 	"github.com/danisasmita/customer-search/internal/model"
 	"github.com/danisasmita/customer-search/internal/repository"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
 )
 
+var ErrCustomerNotFound = NewError(ErrNotFound, message.CodeCustomerNotFound, message.CustomerNotFound)
+
+// searchParamsAttribute berisi nama parameter pencarian yang diisi. Nilainya
+// tidak dicatat di span karena berisi data nasabah.
+const searchParamsAttribute = "customer.search.params"
+
+var tracer = otel.Tracer("github.com/danisasmita/customer-search/internal/service")
+
 type CustomerService interface {
-	SearchByName(name, email, accountNumber string) ([]model.Customer, error)
+	SearchByName(ctx context.Context, name, email, accountNumber string) ([]model.Customer, error)
+	Search(ctx context.Context, filter model.CustomerFilter) ([]model.Customer, int64, error)
+	GetByID(ctx context.Context, id uint) (*model.Customer, error)
 }
 
 // CustomerServiceImpl adalah implementasi dari CustomerService
@@ -20,10 +39,15 @@ func NewCustomerService(repo repository.CustomerRepository) *CustomerServiceImpl
 }
 
 // SearchByName mencari pelanggan berdasarkan nama, email, dan nomor akun
-func (s *CustomerServiceImpl) SearchByName(name, email, accountNumber string) ([]model.Customer, error) {
-	customers, err := s.repo.FindByName(name, email, accountNumber)
+func (s *CustomerServiceImpl) SearchByName(ctx context.Context, name, email, accountNumber string) ([]model.Customer, error) {
+	ctx, span := tracer.Start(ctx, "CustomerService.SearchByName", trace.WithAttributes(
+		attribute.StringSlice(searchParamsAttribute, searchParams(name, email, accountNumber))))
+	defer span.End()
+
+	customers, err := s.repo.FindByName(ctx, name, email, accountNumber)
 
 	if err != nil {
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
@@ -34,3 +58,56 @@ func (s *CustomerServiceImpl) SearchByName(name, email, accountNumber string) ([
 
 	return customers, nil
 }
+
+// Search mengembalikan satu halaman hasil pencarian beserta jumlah seluruh
+// hasil. Tidak ada hasil bukan error.
+func (s *CustomerServiceImpl) Search(ctx context.Context, filter model.CustomerFilter) ([]model.Customer, int64, error) {
+	ctx, span := tracer.Start(ctx, "CustomerService.Search", trace.WithAttributes(
+		attribute.StringSlice(searchParamsAttribute, searchParams(filter.Name, filter.Email, filter.AccountNumber)),
+		attribute.Int("customer.search.limit", filter.Limit),
+		attribute.Int("customer.search.offset", filter.Offset)))
+	defer span.End()
+
+	customers, total, err := s.repo.Search(ctx, filter)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, 0, err
+	}
+	if customers == nil {
+		customers = []model.Customer{}
+	}
+	return customers, total, nil
+}
+
+// GetByID mengambil satu pelanggan beserta produknya. Customer milik cabang
+// lain dianggap tidak ada.
+func (s *CustomerServiceImpl) GetByID(ctx context.Context, id uint) (*model.Customer, error) {
+	ctx, span := tracer.Start(ctx, "CustomerService.GetByID")
+	defer span.End()
+
+	customer, err := s.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCustomerNotFound
+	}
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// searchParams mengembalikan nama parameter pencarian yang tidak kosong.
+func searchParams(name, email, accountNumber string) []string {
+	params := []string{}
+	if name != "" {
+		params = append(params, "name")
+	}
+	if email != "" {
+		params = append(params, "email")
+	}
+	if accountNumber != "" {
+		params = append(params, "account_number")
+	}
+	return params
+}
@@ -1,13 +1,20 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/danisasmita/customer-search/internal/model"
 	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/tracing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
 )
 
 const (
@@ -26,11 +33,23 @@ type MockCustomerRepository struct {
 }
 
 // FindByName adalah metode mock untuk mencari customer berdasarkan nama, email, dan nomor akun
-func (m *MockCustomerRepository) FindByName(name, email, accountNumber string) ([]model.Customer, error) {
+func (m *MockCustomerRepository) FindByName(ctx context.Context, name, email, accountNumber string) ([]model.Customer, error) {
 	args := m.Called(name, email, accountNumber)
 	return args.Get(0).([]model.Customer), args.Error(1)
 }
 
+func (m *MockCustomerRepository) Search(ctx context.Context, filter model.CustomerFilter) ([]model.Customer, int64, error) {
+	args := m.Called(filter)
+	customers, _ := args.Get(0).([]model.Customer)
+	return customers, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCustomerRepository) FindByID(ctx context.Context, id uint) (*model.Customer, error) {
+	args := m.Called(id)
+	customer, _ := args.Get(0).(*model.Customer)
+	return customer, args.Error(1)
+}
+
 func TestCustomerServiceSearchByName(t *testing.T) {
 	// Buat instance mock repository
 	mockRepo := new(MockCustomerRepository)
@@ -62,7 +81,7 @@ func TestCustomerServiceSearchByName(t *testing.T) {
 			Once()
 
 		// Panggil method yang di-test
-		result, err := customerService.SearchByName(customerName1, customerEmail1, accountNumber1)
+		result, err := customerService.SearchByName(context.Background(), customerName1, customerEmail1, accountNumber1)
 
 		// Assert hasil
 		assert.NoError(t, err)
@@ -78,7 +97,7 @@ func TestCustomerServiceSearchByName(t *testing.T) {
 			Once()
 
 		// Panggil method yang di-test
-		result, err := customerService.SearchByName("John Doe", "john.doe@example.com", "123456")
+		result, err := customerService.SearchByName(context.Background(), "John Doe", "john.doe@example.com", "123456")
 
 		// Assert hasil
 		assert.Error(t, err)
@@ -95,7 +114,7 @@ func TestCustomerServiceSearchByName(t *testing.T) {
 			Once()
 
 		// Panggil method yang di-test
-		result, err := customerService.SearchByName("Unknown", "", "")
+		result, err := customerService.SearchByName(context.Background(), "Unknown", "", "")
 
 		// Assert hasil
 		assert.NoError(t, err)
@@ -104,3 +123,90 @@ func TestCustomerServiceSearchByName(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestCustomerServiceSearch(t *testing.T) {
+	mockRepo := new(MockCustomerRepository)
+	customerService := service.NewCustomerService(mockRepo)
+	filter := model.CustomerFilter{Name: "Doe", Limit: 1}
+
+	t.Run("success - page with total", func(t *testing.T) {
+		page := []model.Customer{{Name: customerName1}}
+		mockRepo.On("Search", filter).Return(page, int64(2), nil).Once()
+
+		result, total, err := customerService.Search(context.Background(), filter)
+		assert.NoError(t, err)
+		assert.Equal(t, page, result)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("success - no results is an empty slice", func(t *testing.T) {
+		mockRepo.On("Search", filter).Return(nil, int64(0), nil).Once()
+
+		result, total, err := customerService.Search(context.Background(), filter)
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Empty(t, result)
+		assert.Zero(t, total)
+	})
+
+	t.Run("error - repository error", func(t *testing.T) {
+		mockRepo.On("Search", filter).Return(nil, int64(0), errors.New("db down")).Once()
+
+		_, _, err := customerService.Search(context.Background(), filter)
+		assert.Error(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestCustomerServiceSearchTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewTracerProvider("customer-search", exporter)
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	mockRepo := new(MockCustomerRepository)
+	customerService := service.NewCustomerService(mockRepo)
+	filter := model.CustomerFilter{Name: customerName1, AccountNumber: accountNumber1, Limit: 20}
+	mockRepo.On("Search", filter).Return(nil, int64(0), errors.New("db down")).Once()
+
+	_, _, err := customerService.Search(context.Background(), filter)
+	assert.Error(t, err)
+	assert.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "CustomerService.Search", span.Name)
+	assert.Equal(t, codes.Error, span.Status.Code)
+	assert.Equal(t, "db down", span.Status.Description)
+	assert.Contains(t, span.Attributes, attribute.StringSlice("customer.search.params", []string{"name", "account_number"}))
+	for _, attr := range span.Attributes {
+		assert.NotContains(t, attr.Value.Emit(), customerName1)
+		assert.NotContains(t, attr.Value.Emit(), accountNumber1)
+	}
+}
+
+func TestCustomerServiceGetByID(t *testing.T) {
+	mockRepo := new(MockCustomerRepository)
+	customerService := service.NewCustomerService(mockRepo)
+
+	t.Run("success - found customer", func(t *testing.T) {
+		customer := &model.Customer{Name: customerName1, Email: customerEmail1}
+		mockRepo.On("FindByID", uint(1)).Return(customer, nil).Once()
+
+		result, err := customerService.GetByID(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, customer, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		mockRepo.On("FindByID", uint(2)).Return(&model.Customer{}, gorm.ErrRecordNotFound).Once()
+
+		result, err := customerService.GetByID(context.Background(), 2)
+		assert.ErrorIs(t, err, service.ErrCustomerNotFound)
+		assert.Nil(t, result)
+		mockRepo.AssertExpectations(t)
+	})
+}
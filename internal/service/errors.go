@@ -0,0 +1,50 @@
+package service
+
+import "errors"
+
+// Jenis error domain. Handler memetakan jenis ini ke status HTTP, jadi
+// service cukup memilih jenis dan kode yang stabil.
+var (
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+)
+
+// FieldError menjelaskan satu field input yang tidak valid. Params dipakai
+// untuk menerjemahkan pesan, misalnya panjang minimal.
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+	Params  map[string]interface{}
+}
+
+// Error adalah error domain dengan kode stabil untuk klien. errors.Is
+// terhadap Kind bernilai true, misalnya errors.Is(ErrUserNotFound,
+// ErrNotFound). Details ikut dikirim ke klien, misalnya daftar role valid.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Fields  []FieldError
+	Details map[string]interface{}
+}
+
+func NewError(kind error, code, msg string) *Error {
+	return &Error{Kind: kind, Code: code, Message: msg}
+}
+
+// NewValidationError membuat ErrValidation dengan detail per field.
+func NewValidationError(code, msg string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: msg, Fields: fields}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
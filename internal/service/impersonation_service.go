@@ -0,0 +1,85 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/repository"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/utils"
+)
+
+var (
+	ErrCannotImpersonate           = NewError(ErrForbidden, message.CodeCannotImpersonate, message.CannotImpersonate)
+	ErrImpersonationReasonRequired = NewValidationError(message.CodeValidationFailed, message.ValidationFailed,
+		FieldError{Field: "reason", Code: message.CodeRequired, Message: message.ImpersonationReasonRequired})
+)
+
+// ImpersonationPolicy.GlobalRoles sama dengan TENANT_GLOBAL_ROLES; admin
+// di luar role itu hanya boleh memakai user di cabangnya sendiri.
+type ImpersonationPolicy struct {
+	TTL         time.Duration
+	GlobalRoles []string
+}
+
+// ImpersonationService menerbitkan token agar admin melihat aplikasi
+// persis seperti user target, termasuk masking dan batas cabang.
+type ImpersonationService interface {
+	Start(adminID, userID uint, reason string) (*model.ImpersonationResponse, error)
+}
+
+type impersonationService struct {
+	users  repository.UserRepository
+	audit  AuditService
+	policy ImpersonationPolicy
+}
+
+func NewImpersonationService(users repository.UserRepository, audit AuditService, policy ImpersonationPolicy) ImpersonationService {
+	return &impersonationService{users: users, audit: audit, policy: policy}
+}
+
+// Start hanya mengizinkan target dengan hak lebih rendah dari admin agar
+// impersonation tidak pernah menambah hak akses.
+func (s *impersonationService) Start(adminID, userID uint, reason string) (*model.ImpersonationResponse, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrImpersonationReasonRequired
+	}
+	if userID == adminID {
+		return nil, ErrCannotImpersonate
+	}
+
+	admin, err := s.users.FindUserByID(adminID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	target, err := s.users.FindUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if target.Disabled || model.RoleRank(target.Role) <= model.RoleRank(admin.Role) {
+		return nil, ErrCannotImpersonate
+	}
+	if !slices.Contains(s.policy.GlobalRoles, admin.Role) && target.TenantID != admin.TenantID {
+		return nil, ErrCannotImpersonate
+	}
+
+	token, err := utils.GenerateImpersonationJWT(admin.ID, target.ID, target.TenantID, s.policy.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]string{"reason": reason, "ttl": s.policy.TTL.String()}
+	if err := recordAdminAction(s.audit, model.AuditActionImpersonate, admin.ID, "user_id", target.ID, details); err != nil {
+		return nil, err
+	}
+
+	return &model.ImpersonationResponse{
+		AccessToken: token,
+		ExpiresIn:   int64(s.policy.TTL.Seconds()),
+		UserID:      target.ID,
+	}, nil
+}
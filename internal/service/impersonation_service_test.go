@@ -0,0 +1,69 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestImpersonationServiceStart(t *testing.T) {
+	users := new(MockUserRepository)
+	users.On("FindUserByID", uint(1)).Return(&model.User{Model: gorm.Model{ID: 1}, Role: model.RoleAdmin}, nil)
+	users.On("FindUserByID", uint(2)).Return(&model.User{Model: gorm.Model{ID: 2}, Role: model.RoleAgent, TenantID: "JKT"}, nil)
+	users.On("FindUserByID", uint(3)).Return(&model.User{Model: gorm.Model{ID: 3}, Role: model.RoleAdmin}, nil)
+	users.On("FindUserByID", uint(4)).Return(&model.User{Model: gorm.Model{ID: 4}, Role: model.RoleAgent, Disabled: true}, nil)
+	users.On("FindUserByID", uint(5)).Return(&model.User{Model: gorm.Model{ID: 5}, Role: model.RoleAdmin, TenantID: "SBY"}, nil)
+	users.On("FindUserByID", uint(9)).Return((*model.User)(nil), gorm.ErrRecordNotFound)
+
+	audits := &memoryAuditRepository{}
+	impersonation := service.NewImpersonationService(users, service.NewAuditService(audits), service.ImpersonationPolicy{
+		TTL:         10 * time.Minute,
+		GlobalRoles: []string{"auditor"},
+	})
+
+	tests := []struct {
+		name    string
+		adminID uint
+		userID  uint
+		reason  string
+		wantErr error
+	}{
+		{name: "missing reason", adminID: 1, userID: 2, wantErr: service.ErrImpersonationReasonRequired},
+		{name: "self", adminID: 1, userID: 1, reason: "ticket 42", wantErr: service.ErrCannotImpersonate},
+		{name: "unknown user", adminID: 1, userID: 9, reason: "ticket 42", wantErr: service.ErrUserNotFound},
+		{name: "same role", adminID: 1, userID: 3, reason: "ticket 42", wantErr: service.ErrCannotImpersonate},
+		{name: "disabled user", adminID: 1, userID: 4, reason: "ticket 42", wantErr: service.ErrCannotImpersonate},
+		{name: "other branch", adminID: 5, userID: 2, reason: "ticket 42", wantErr: service.ErrCannotImpersonate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := impersonation.Start(tt.adminID, tt.userID, tt.reason)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+	assert.Empty(t, audits.logs)
+
+	// Admin tanpa role global cukup berada di cabang yang sama
+	users.On("FindUserByID", uint(6)).Return(&model.User{Model: gorm.Model{ID: 6}, Role: model.RoleAdmin, TenantID: "JKT"}, nil)
+	response, err := impersonation.Start(6, 2, "ticket 42")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), response.UserID)
+	assert.Equal(t, int64(600), response.ExpiresIn)
+
+	claims, err := utils.ValidateJWT(response.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), claims.UserID)
+	assert.Equal(t, uint(6), claims.ActorID())
+	assert.Equal(t, "JKT", claims.TenantID)
+
+	if assert.Len(t, audits.logs, 1) {
+		assert.Equal(t, model.AuditActionImpersonate, audits.logs[0].Action)
+		assert.Equal(t, uint(6), audits.logs[0].UserID)
+		assert.JSONEq(t, `{"user_id":"2","reason":"ticket 42","ttl":"10m0s"}`, audits.logs[0].Query)
+	}
+}
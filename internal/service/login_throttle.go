@@ -0,0 +1,197 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/repository"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"gorm.io/gorm"
+)
+
+// LoginThrottledError dikembalikan saat login harus ditunda.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return message.TooManyLoginAttempts
+}
+
+type LoginThrottlePolicy struct {
+	// KeyPrefix memisahkan hitungan throttle lain, misalnya permintaan
+	// reset password, dari hitungan login.
+	KeyPrefix       string
+	MaxFailures     int
+	IPMaxFailures   int
+	LockoutDuration time.Duration
+	BackoffBase     time.Duration
+	BackoffMax      time.Duration
+}
+
+type LoginThrottle interface {
+	Check(username, clientIP string) error
+	RecordFailure(username, clientIP string) error
+	RecordSuccess(username string) error
+	Unlock(username string, adminID uint) error
+}
+
+type loginThrottle struct {
+	repo   repository.LoginAttemptRepository
+	audit  AuditService
+	policy LoginThrottlePolicy
+	mu     sync.Mutex
+}
+
+func NewLoginThrottle(repo repository.LoginAttemptRepository, audit AuditService, policy LoginThrottlePolicy) LoginThrottle {
+	return &loginThrottle{repo: repo, audit: audit, policy: policy}
+}
+
+// Check menolak login jika username atau IP sedang dikunci atau masih dalam
+// masa backoff sejak kegagalan terakhir.
+func (t *loginThrottle) Check(username, clientIP string) error {
+	now := time.Now()
+	var wait time.Duration
+
+	for _, key := range []string{t.userKey(username), t.ipKey(clientIP)} {
+		attempt, err := t.find(key)
+		if err != nil {
+			return err
+		}
+		if attempt == nil {
+			continue
+		}
+		if until := t.blockedUntil(attempt, now); until.After(now) {
+			wait = max(wait, until.Sub(now))
+		}
+	}
+
+	if wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+func (t *loginThrottle) RecordFailure(username, clientIP string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if err := t.fail(t.userKey(username), t.policy.MaxFailures, clientIP, now); err != nil {
+		return err
+	}
+	return t.fail(t.ipKey(clientIP), t.policy.IPMaxFailures, clientIP, now)
+}
+
+// RecordSuccess hanya mereset hitungan username. Hitungan IP dibiarkan
+// meluruh sendiri agar penyerang tidak bisa mereset dengan login ke akun
+// miliknya.
+func (t *loginThrottle) RecordSuccess(username string) error {
+	return t.repo.Delete(t.userKey(username))
+}
+
+func (t *loginThrottle) Unlock(username string, adminID uint) error {
+	if err := t.repo.Delete(t.userKey(username)); err != nil {
+		return err
+	}
+	return t.record(model.AuditActionAccountUnlock, adminID, t.userKey(username), "")
+}
+
+func (t *loginThrottle) fail(key string, maxFailures int, clientIP string, now time.Time) error {
+	attempt, err := t.find(key)
+	if err != nil {
+		return err
+	}
+	if attempt == nil || t.expired(attempt, now) {
+		existing := attempt
+		attempt = &model.LoginAttempt{Key: key}
+		if existing != nil {
+			attempt.ID = existing.ID
+		}
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = now
+
+	locked := maxFailures > 0 && attempt.Failures >= maxFailures
+	if locked {
+		lockedUntil := now.Add(t.policy.LockoutDuration)
+		attempt.LockedUntil = &lockedUntil
+	}
+
+	if err := t.repo.Save(attempt); err != nil {
+		return err
+	}
+
+	if locked {
+		return t.record(model.AuditActionLoginLockout, 0, key, clientIP)
+	}
+	return nil
+}
+
+// blockedUntil adalah akhir kunci akun atau akhir backoff eksponensial
+// sejak kegagalan terakhir, mana yang lebih lama.
+func (t *loginThrottle) blockedUntil(attempt *model.LoginAttempt, now time.Time) time.Time {
+	if t.expired(attempt, now) {
+		return time.Time{}
+	}
+
+	until := attempt.LastFailureAt.Add(t.backoff(attempt.Failures))
+	if attempt.LockedUntil != nil && attempt.LockedUntil.After(until) {
+		until = *attempt.LockedUntil
+	}
+	return until
+}
+
+func (t *loginThrottle) backoff(failures int) time.Duration {
+	if failures <= 0 || t.policy.BackoffBase <= 0 {
+		return 0
+	}
+	delay := t.policy.BackoffBase
+	for i := 1; i < failures && delay < t.policy.BackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, t.policy.BackoffMax)
+}
+
+// expired bernilai true jika kunci sudah lewat atau kegagalan terakhir
+// sudah lebih lama dari durasi lockout, sehingga hitungan mulai dari nol.
+func (t *loginThrottle) expired(attempt *model.LoginAttempt, now time.Time) bool {
+	if attempt.LockedUntil != nil {
+		return !attempt.LockedUntil.After(now)
+	}
+	return now.Sub(attempt.LastFailureAt) > t.policy.LockoutDuration
+}
+
+func (t *loginThrottle) find(key string) (*model.LoginAttempt, error) {
+	attempt, err := t.repo.Find(key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return attempt, err
+}
+
+func (t *loginThrottle) record(action string, userID uint, key, clientIP string) error {
+	query, err := json.Marshal(map[string]string{"key": key})
+	if err != nil {
+		return err
+	}
+	return t.audit.Record(&model.AuditLog{
+		UserID:   userID,
+		Action:   action,
+		Query:    string(query),
+		ClientIP: clientIP,
+	})
+}
+
+func (t *loginThrottle) userKey(username string) string {
+	return t.policy.KeyPrefix + "user:" + strings.ToLower(username)
+}
+
+func (t *loginThrottle) ipKey(clientIP string) string {
+	return t.policy.KeyPrefix + "ip:" + clientIP
+}
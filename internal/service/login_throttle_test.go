@@ -0,0 +1,106 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// memoryLoginAttemptRepository menyimpan hitungan gagal login di memori.
+type memoryLoginAttemptRepository struct {
+	attempts map[string]model.LoginAttempt
+}
+
+func (r *memoryLoginAttemptRepository) Find(key string) (*model.LoginAttempt, error) {
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &attempt, nil
+}
+
+func (r *memoryLoginAttemptRepository) Save(attempt *model.LoginAttempt) error {
+	r.attempts[attempt.Key] = *attempt
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) Delete(key string) error {
+	delete(r.attempts, key)
+	return nil
+}
+
+func newTestLoginThrottle() (*memoryAuditRepository, service.LoginThrottle) {
+	audits := &memoryAuditRepository{}
+	repo := &memoryLoginAttemptRepository{attempts: map[string]model.LoginAttempt{}}
+	throttle := service.NewLoginThrottle(repo, service.NewAuditService(audits), service.LoginThrottlePolicy{
+		MaxFailures:     3,
+		IPMaxFailures:   5,
+		LockoutDuration: 15 * time.Minute,
+		BackoffBase:     time.Second,
+		BackoffMax:      4 * time.Second,
+	})
+	return audits, throttle
+}
+
+func retryAfter(t *testing.T, err error) time.Duration {
+	throttled, ok := err.(*service.LoginThrottledError)
+	if !assert.True(t, ok, "expected LoginThrottledError, got %v", err) {
+		return 0
+	}
+	return throttled.RetryAfter
+}
+
+func TestLoginThrottleBackoffAndLockout(t *testing.T) {
+	audits, throttle := newTestLoginThrottle()
+
+	assert.NoError(t, throttle.Check("john", "10.0.0.1"))
+
+	assert.NoError(t, throttle.RecordFailure("john", "10.0.0.1"))
+	wait := retryAfter(t, throttle.Check("john", "10.0.0.2"))
+	assert.True(t, wait > 0 && wait <= time.Second)
+
+	assert.NoError(t, throttle.RecordFailure("John", "10.0.0.1"))
+	assert.NoError(t, throttle.RecordFailure("john", "10.0.0.1"))
+	wait = retryAfter(t, throttle.Check("john", "10.0.0.2"))
+	assert.True(t, wait > 14*time.Minute)
+
+	// Kunci akun tercatat di audit trail
+	if assert.Len(t, audits.logs, 1) {
+		assert.Equal(t, model.AuditActionLoginLockout, audits.logs[0].Action)
+		assert.Equal(t, `{"key":"user:john"}`, audits.logs[0].Query)
+		assert.Equal(t, "10.0.0.1", audits.logs[0].ClientIP)
+	}
+
+	assert.NoError(t, throttle.Unlock("john", 1))
+	assert.NoError(t, throttle.Check("john", "10.0.0.2"))
+	if assert.Len(t, audits.logs, 2) {
+		assert.Equal(t, model.AuditActionAccountUnlock, audits.logs[1].Action)
+		assert.Equal(t, uint(1), audits.logs[1].UserID)
+	}
+}
+
+func TestLoginThrottleTracksIP(t *testing.T) {
+	audits, throttle := newTestLoginThrottle()
+
+	for _, username := range []string{"a", "b", "c", "d", "e"} {
+		assert.NoError(t, throttle.RecordFailure(username, "10.0.0.9"))
+	}
+
+	// Username lain dari IP yang sama ikut tertahan
+	wait := retryAfter(t, throttle.Check("fresh", "10.0.0.9"))
+	assert.True(t, wait > 14*time.Minute)
+	assert.NoError(t, throttle.Check("fresh", "10.0.0.10"))
+	assert.Len(t, audits.logs, 1)
+}
+
+func TestLoginThrottleSuccessResetsUser(t *testing.T) {
+	_, throttle := newTestLoginThrottle()
+
+	assert.NoError(t, throttle.RecordFailure("john", "10.0.0.1"))
+	assert.NoError(t, throttle.RecordSuccess("john"))
+	assert.NoError(t, throttle.Check("john", "10.0.0.2"))
+}
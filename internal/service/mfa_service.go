@@ -0,0 +1,203 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/repository"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/utils"
+)
+
+var (
+	ErrMFAAlreadyEnabled   = NewError(ErrConflict, message.CodeMFAAlreadyEnabled, message.MFAAlreadyEnabled)
+	ErrMFANotEnrolled      = NewError(ErrValidation, message.CodeMFANotEnrolled, message.MFANotEnrolled)
+	ErrInvalidMFACode      = NewError(ErrValidation, message.CodeInvalidMFACode, message.InvalidMFACode)
+	ErrInvalidMFAChallenge = NewError(ErrUnauthorized, message.CodeInvalidMFAChallenge, message.InvalidMFAChallenge)
+)
+
+const recoveryCodeCount = 10
+
+type MFAPolicy struct {
+	Issuer       string
+	ChallengeTTL time.Duration
+}
+
+type MFAService interface {
+	Enroll(userID uint) (*model.MFAEnrollment, error)
+	Confirm(userID uint, code string) ([]string, error)
+	Disable(userID, adminID uint) error
+	Verify(user *model.User, code string) error
+	Challenge(userID uint) (string, error)
+	ValidateChallenge(token string) (uint, error)
+}
+
+type mfaService struct {
+	users         repository.UserRepository
+	recoveryCodes repository.MFARecoveryCodeRepository
+	tokens        TokenService
+	audit         AuditService
+	policy        MFAPolicy
+}
+
+func NewMFAService(users repository.UserRepository, recoveryCodes repository.MFARecoveryCodeRepository, tokens TokenService, audit AuditService, policy MFAPolicy) MFAService {
+	return &mfaService{users: users, recoveryCodes: recoveryCodes, tokens: tokens, audit: audit, policy: policy}
+}
+
+// Enroll membuat secret baru. MFA belum aktif sampai Confirm menerima kode
+// pertama, sehingga enrollment yang tidak selesai tidak mengunci user.
+func (s *mfaService) Enroll(userID uint) (*model.MFAEnrollment, error) {
+	user, err := s.users.FindUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.users.SetMFASecret(user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &model.MFAEnrollment{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(s.policy.Issuer, user.Username, secret),
+	}, nil
+}
+
+// Confirm mengaktifkan MFA dan mengembalikan kode pemulihan dalam bentuk
+// teks. Kode hanya ditampilkan sekali; database menyimpan hash-nya saja.
+func (s *mfaService) Confirm(userID uint, code string) ([]string, error) {
+	user, err := s.users.FindUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFASecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	step, ok := utils.ValidateTOTP(user.MFASecret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, stored, err := newRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recoveryCodes.Replace(user.ID, stored); err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.users.EnableMFA(user.ID, user.MFASecret, step)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrMFANotEnrolled
+	}
+	return codes, nil
+}
+
+// Disable dipakai admin saat user kehilangan perangkat authenticator.
+// Semua sesi user dicabut karena perangkat yang hilang mungkin masih
+// memegang token, dan reset dicatat di audit log atas nama admin.
+func (s *mfaService) Disable(userID, adminID uint) error {
+	user, err := s.users.FindUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.recoveryCodes.DeleteByUser(user.ID); err != nil {
+		return err
+	}
+	if err := s.users.ResetMFA(user.ID); err != nil {
+		return err
+	}
+	if err := s.tokens.RevokeUser(user.ID); err != nil {
+		return err
+	}
+
+	return recordAdminAction(s.audit, model.AuditActionMFAReset, adminID, "user_id", user.ID, nil)
+}
+
+// Verify menerima kode TOTP atau kode pemulihan. Kode TOTP dari step yang
+// sudah pernah dipakai ditolak; pengecekan dan penyimpanan step terjadi di
+// satu UPDATE sehingga dua login bersamaan dengan kode yang sama tidak
+// bisa sama-sama lolos.
+func (s *mfaService) Verify(user *model.User, code string) error {
+	if !user.MFAEnabled {
+		return ErrMFANotEnrolled
+	}
+
+	if step, ok := utils.ValidateTOTP(user.MFASecret, code, time.Now()); ok {
+		advanced, err := s.users.AdvanceMFAStep(user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !advanced {
+			return ErrInvalidMFACode
+		}
+		user.MFALastStep = step
+		return nil
+	}
+
+	used, err := s.recoveryCodes.Use(user.ID, hashToken(normalizeRecoveryCode(code)), time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *mfaService) Challenge(userID uint) (string, error) {
+	return utils.GenerateMFAChallenge(userID, s.policy.ChallengeTTL)
+}
+
+func (s *mfaService) ValidateChallenge(token string) (uint, error) {
+	claims, err := utils.ValidateMFAChallenge(token)
+	if err != nil {
+		return 0, ErrInvalidMFAChallenge
+	}
+	return claims.UserID, nil
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCodes membuat kode 80 bit berformat xxxx-xxxx-xxxx-xxxx.
+func newRecoveryCodes(userID uint) ([]string, []model.MFARecoveryCode, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	stored := make([]model.MFARecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		code := raw[0:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:16]
+
+		codes = append(codes, code)
+		stored = append(stored, model.MFARecoveryCode{UserID: userID, CodeHash: hashToken(raw)})
+	}
+	return codes, stored, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return strings.ToLower(code)
+}
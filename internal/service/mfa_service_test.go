@@ -0,0 +1,221 @@
+package service_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// memoryRecoveryCodeRepository menyimpan hash kode pemulihan di memori.
+type memoryRecoveryCodeRepository struct {
+	codes map[string]*model.MFARecoveryCode
+}
+
+func (r *memoryRecoveryCodeRepository) Replace(userID uint, codes []model.MFARecoveryCode) error {
+	_ = r.DeleteByUser(userID)
+	for i := range codes {
+		r.codes[codes[i].CodeHash] = &codes[i]
+	}
+	return nil
+}
+
+func (r *memoryRecoveryCodeRepository) Use(userID uint, codeHash string, at time.Time) (bool, error) {
+	code, ok := r.codes[codeHash]
+	if !ok || code.UserID != userID || code.UsedAt != nil {
+		return false, nil
+	}
+	code.UsedAt = &at
+	return true, nil
+}
+
+func (r *memoryRecoveryCodeRepository) DeleteByUser(userID uint) error {
+	for hash, code := range r.codes {
+		if code.UserID == userID {
+			delete(r.codes, hash)
+		}
+	}
+	return nil
+}
+
+func newTestMFAService(user *model.User) (*MockUserRepository, *memoryRecoveryCodeRepository, service.MFAService) {
+	users, codes, _, _, mfa := newTestMFAServiceWithAudit(user)
+	return users, codes, mfa
+}
+
+// newTestMFAServiceWithAudit juga mengembalikan mock token service dan
+// audit log untuk memeriksa reset MFA oleh admin.
+func newTestMFAServiceWithAudit(user *model.User) (*MockUserRepository, *memoryRecoveryCodeRepository, *MockTokenService, *memoryAuditRepository, service.MFAService) {
+	users := new(MockUserRepository)
+	users.On("FindUserByID", user.ID).Return(user, nil)
+	users.On("SetMFASecret", user.ID, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		user.MFASecret = args.String(1)
+		user.MFALastStep = 0
+	}).Return(nil)
+	users.On("EnableMFA", user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("int64")).Run(func(args mock.Arguments) {
+		user.MFAEnabled = true
+		user.MFALastStep = args.Get(2).(int64)
+	}).Return(true, nil)
+	// Step pertama yang diajukan diterima; step yang sama setelahnya ditolak
+	// seperti UPDATE bersyarat di repository.
+	users.On("AdvanceMFAStep", user.ID, mock.AnythingOfType("int64")).Return(true, nil).Once()
+	users.On("AdvanceMFAStep", user.ID, mock.AnythingOfType("int64")).Return(false, nil)
+	users.On("ResetMFA", user.ID).Run(func(mock.Arguments) {
+		user.MFASecret = ""
+		user.MFAEnabled = false
+		user.MFALastStep = 0
+	}).Return(nil)
+
+	tokens := new(MockTokenService)
+	tokens.On("RevokeUser", user.ID).Return(nil)
+
+	codes := &memoryRecoveryCodeRepository{codes: map[string]*model.MFARecoveryCode{}}
+	audits := &memoryAuditRepository{}
+	mfa := service.NewMFAService(users, codes, tokens, service.NewAuditService(audits), service.MFAPolicy{Issuer: "Customer Search", ChallengeTTL: time.Minute})
+	return users, codes, tokens, audits, mfa
+}
+
+func TestMFAServiceEnrollAndConfirm(t *testing.T) {
+	user := &model.User{Model: gorm.Model{ID: 1}, Username: "john_doe"}
+	_, codes, mfa := newTestMFAService(user)
+
+	_, err := mfa.Confirm(1, "123456")
+	assert.ErrorIs(t, err, service.ErrMFANotEnrolled)
+
+	enrollment, err := mfa.Enroll(1)
+	assert.NoError(t, err)
+	assert.Equal(t, user.MFASecret, enrollment.Secret)
+	assert.Contains(t, enrollment.OTPAuthURI, "otpauth://totp/Customer%20Search:john_doe")
+	assert.False(t, user.MFAEnabled, "MFA must not be active before confirmation")
+
+	_, err = mfa.Confirm(1, "000000")
+	assert.ErrorIs(t, err, service.ErrInvalidMFACode)
+
+	code, err := utils.TOTPCode(enrollment.Secret, time.Now())
+	assert.NoError(t, err)
+	recoveryCodes, err := mfa.Confirm(1, code)
+	assert.NoError(t, err)
+	assert.True(t, user.MFAEnabled)
+	assert.Len(t, recoveryCodes, 10)
+	assert.Len(t, codes.codes, 10)
+
+	// Kode teks tidak pernah disimpan
+	for _, recoveryCode := range recoveryCodes {
+		assert.NotContains(t, codes.codes, recoveryCode)
+	}
+
+	_, err = mfa.Enroll(1)
+	assert.ErrorIs(t, err, service.ErrMFAAlreadyEnabled)
+}
+
+func TestMFAServiceConfirmReplacedEnrollment(t *testing.T) {
+	user := &model.User{Model: gorm.Model{ID: 1}, Username: "john_doe"}
+	users, _, mfa := newTestMFAService(user)
+	users.ExpectedCalls = slices.DeleteFunc(users.ExpectedCalls, func(call *mock.Call) bool {
+		return call.Method == "EnableMFA"
+	})
+	users.On("EnableMFA", user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("int64")).Return(false, nil)
+
+	enrollment, err := mfa.Enroll(1)
+	assert.NoError(t, err)
+
+	// Enrollment diganti atau direset request lain sebelum dikonfirmasi
+	code, _ := utils.TOTPCode(enrollment.Secret, time.Now())
+	_, err = mfa.Confirm(1, code)
+	assert.ErrorIs(t, err, service.ErrMFANotEnrolled)
+	assert.False(t, user.MFAEnabled)
+}
+
+func TestMFAServiceVerify(t *testing.T) {
+	user := &model.User{Model: gorm.Model{ID: 1}, Username: "john_doe"}
+	users, _, tokens, audits, mfa := newTestMFAServiceWithAudit(user)
+
+	enrollment, err := mfa.Enroll(1)
+	assert.NoError(t, err)
+
+	// Konfirmasi memakai step sebelumnya agar step sekarang masih bisa dipakai
+	previous, _ := utils.TOTPCode(enrollment.Secret, time.Now().Add(-30*time.Second))
+	recoveryCodes, err := mfa.Confirm(1, previous)
+	assert.NoError(t, err)
+
+	current, _ := utils.TOTPCode(enrollment.Secret, time.Now())
+	assert.NoError(t, mfa.Verify(user, current))
+	assert.ErrorIs(t, mfa.Verify(user, current), service.ErrInvalidMFACode, "TOTP codes are single use")
+
+	assert.NoError(t, mfa.Verify(user, recoveryCodes[0]))
+	assert.ErrorIs(t, mfa.Verify(user, recoveryCodes[0]), service.ErrInvalidMFACode, "recovery codes are single use")
+	assert.ErrorIs(t, mfa.Verify(user, "not-a-code"), service.ErrInvalidMFACode)
+
+	assert.NoError(t, mfa.Disable(1, 9))
+	assert.False(t, user.MFAEnabled)
+	assert.Empty(t, user.MFASecret)
+	users.AssertCalled(t, "ResetMFA", uint(1))
+	tokens.AssertCalled(t, "RevokeUser", uint(1))
+	if assert.Len(t, audits.logs, 1) {
+		assert.Equal(t, model.AuditActionMFAReset, audits.logs[0].Action)
+		assert.Equal(t, uint(9), audits.logs[0].UserID)
+		assert.JSONEq(t, `{"user_id":"1"}`, audits.logs[0].Query)
+	}
+	assert.ErrorIs(t, mfa.Verify(user, recoveryCodes[1]), service.ErrMFANotEnrolled)
+}
+
+func TestAuthServiceLoginMFA(t *testing.T) {
+	hashedPassword, err := utils.HashPassword("password123")
+	assert.NoError(t, err)
+	secret, err := utils.GenerateTOTPSecret()
+	assert.NoError(t, err)
+
+	user := &model.User{
+		Model:      gorm.Model{ID: 1},
+		Username:   "john_doe",
+		Password:   hashedPassword,
+		MFASecret:  secret,
+		MFAEnabled: true,
+	}
+	users, _, mfa := newTestMFAService(user)
+	users.On("FindUserByUsername", "john_doe").Return(user, nil)
+
+	mockTokens := new(MockTokenService)
+	mockThrottle := new(MockLoginThrottle)
+	mockThrottle.On("Check", "john_doe", clientIP).Return(nil)
+	mockThrottle.On("RecordFailure", "john_doe", clientIP).Return(nil)
+	mockThrottle.On("RecordSuccess", "john_doe").Return(nil)
+	authService := service.NewAuthService(users, mockTokens, mockThrottle, mfa, service.PasswordPolicy{}, logging.Discard())
+
+	response, err := authService.Login(model.UserRequest{Username: "john_doe", Password: "password123"}, clientIP)
+	assert.NoError(t, err)
+	assert.True(t, response.MFARequired)
+	assert.Empty(t, response.AccessToken)
+	mockThrottle.AssertNotCalled(t, "RecordSuccess", "john_doe")
+
+	t.Run("error - wrong code", func(t *testing.T) {
+		_, err := authService.LoginMFA(model.MFALoginRequest{MFAToken: response.MFAToken, Code: "000000"}, clientIP)
+		assert.ErrorIs(t, err, service.ErrMFALoginFailed)
+		mockThrottle.AssertCalled(t, "RecordFailure", "john_doe", clientIP)
+	})
+
+	t.Run("error - access token as challenge", func(t *testing.T) {
+		access, _ := utils.GenerateJWT(1, "")
+		_, err := authService.LoginMFA(model.MFALoginRequest{MFAToken: access, Code: "000000"}, clientIP)
+		assert.ErrorIs(t, err, service.ErrInvalidMFAChallenge)
+	})
+
+	t.Run("success - valid code", func(t *testing.T) {
+		mockTokens.On("Issue", uint(1), "").Return(&model.LoginResponse{AccessToken: "access"}, nil).Once()
+		code, _ := utils.TOTPCode(secret, time.Now())
+
+		tokens, err := authService.LoginMFA(model.MFALoginRequest{MFAToken: response.MFAToken, Code: code}, clientIP)
+		assert.NoError(t, err)
+		assert.Equal(t, "access", tokens.AccessToken)
+		mockThrottle.AssertCalled(t, "RecordSuccess", "john_doe")
+	})
+
+	users.AssertNumberOfCalls(t, "AdvanceMFAStep", 1)
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/repository"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/oidc"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidOIDCState = NewError(ErrValidation, message.CodeInvalidOIDCState, message.InvalidOIDCState)
+	ErrOIDCLoginFailed  = NewError(ErrUnauthorized, message.CodeOIDCLoginFailed, message.OIDCLoginFailed)
+	ErrNoMappedRole     = NewError(ErrForbidden, message.CodeNoMappedRole, message.NoMappedRole)
+)
+
+// OIDCProvider dipenuhi oleh *oidc.Client.
+type OIDCProvider interface {
+	AuthCodeURL(state, nonce, challenge string) string
+	Exchange(ctx context.Context, code, verifier string) (*oidc.TokenResponse, error)
+	Verify(ctx context.Context, rawIDToken, nonce string) (*oidc.Identity, error)
+}
+
+// OIDCPolicy memetakan group dari identity provider ke role lokal. User
+// tanpa group yang terpetakan mendapat DefaultRole, atau ditolak jika
+// DefaultRole kosong. Cabang diambil dari claim tenant di ID token; user
+// baru tanpa claim itu mendapat DefaultTenant.
+type OIDCPolicy struct {
+	RoleMapping   map[string]string
+	DefaultRole   string
+	DefaultTenant string
+	StateTTL      time.Duration
+}
+
+// OIDCService menjalankan login SSO. Setelah identitas diverifikasi, token
+// yang diterbitkan sama dengan login password.
+type OIDCService interface {
+	Begin() (authURL, state string, err error)
+	Callback(ctx context.Context, state, code string) (*model.LoginResponse, error)
+}
+
+type oidcService struct {
+	provider OIDCProvider
+	repo     repository.OIDCRepository
+	users    repository.UserRepository
+	tokens   TokenService
+	mfa      MFAService
+	policy   OIDCPolicy
+	logger   *slog.Logger
+}
+
+func NewOIDCService(provider OIDCProvider, repo repository.OIDCRepository, users repository.UserRepository, tokens TokenService, mfa MFAService, policy OIDCPolicy, logger *slog.Logger) OIDCService {
+	return &oidcService{provider: provider, repo: repo, users: users, tokens: tokens, mfa: mfa, policy: policy, logger: logger}
+}
+
+// Begin menyimpan state, nonce, dan code verifier PKCE lalu mengembalikan
+// URL login identity provider. state juga harus diikat ke browser oleh
+// pemanggil, misalnya lewat cookie.
+func (s *oidcService) Begin() (string, string, error) {
+	state, err := oidc.RandomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oidc.RandomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := oidc.NewVerifier()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = s.repo.CreateState(&model.OIDCState{
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(s.policy.StateTTL),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return s.provider.AuthCodeURL(state, nonce, oidc.S256Challenge(verifier)), state, nil
+}
+
+func (s *oidcService) Callback(ctx context.Context, state, code string) (*model.LoginResponse, error) {
+	if state == "" || code == "" {
+		return nil, ErrInvalidOIDCState
+	}
+
+	stored, err := s.repo.ConsumeState(hashToken(state))
+	if err != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidOIDCState
+	}
+
+	token, err := s.provider.Exchange(ctx, code, stored.CodeVerifier)
+	if err != nil {
+		s.logger.WarnContext(ctx, "oidc code exchange failed", "error", err)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	identity, err := s.provider.Verify(ctx, token.IDToken, stored.Nonce)
+	if err != nil {
+		s.logger.WarnContext(ctx, "oidc id token rejected", "error", err)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	role := s.mapRole(identity.Groups)
+	if role == "" {
+		return nil, ErrNoMappedRole
+	}
+
+	if identity.Tenant != "" && !model.ValidTenant(identity.Tenant) {
+		s.logger.WarnContext(ctx, "oidc tenant claim rejected", "tenant_id", identity.Tenant)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	user, err := s.findOrCreateUser(identity, role)
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	if user.MFAEnabled {
+		challenge, err := s.mfa.Challenge(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.LoginResponse{MFARequired: true, MFAToken: challenge}, nil
+	}
+
+	return s.tokens.Issue(user.ID, user.TenantID)
+}
+
+// findOrCreateUser membuat user saat login pertama. Role, dan cabang jika
+// identity provider mengirimnya, disamakan setiap kali login; tanpa claim
+// cabang, cabang yang diatur admin dipertahankan. User lokal dengan username
+// yang sama tidak dihubungkan otomatis agar akun itu tidak bisa diambil
+// alih lewat identity provider.
+func (s *oidcService) findOrCreateUser(identity *oidc.Identity, role string) (*model.User, error) {
+	existing, err := s.repo.FindIdentity(identity.Issuer, identity.Subject)
+	if err == nil {
+		user, err := s.users.FindUserByID(existing.UserID)
+		if err != nil {
+			return nil, ErrUserNotFound
+		}
+		if user.Role != role {
+			if err := s.users.UpdateRole(user.ID, role); err != nil {
+				return nil, err
+			}
+			user.Role = role
+		}
+		if identity.Tenant != "" && user.TenantID != identity.Tenant {
+			if err := s.users.UpdateTenant(user.ID, identity.Tenant); err != nil {
+				return nil, err
+			}
+			user.TenantID = identity.Tenant
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	username := oidcUsername(identity)
+	if _, err := s.users.FindUserByUsername(username); err == nil {
+		return nil, ErrUsernameTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Password kosong tidak pernah cocok saat login password.
+	tenantID := identity.Tenant
+	if tenantID == "" {
+		tenantID = s.policy.DefaultTenant
+	}
+	user := &model.User{Username: username, Role: role, TenantID: tenantID}
+	err = s.repo.CreateUserWithIdentity(user, &model.ExternalIdentity{
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrUsernameTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// mapRole memilih role dengan hak tertinggi sesuai urutan model.Roles.
+func (s *oidcService) mapRole(groups []string) string {
+	mapped := map[string]bool{}
+	for _, group := range groups {
+		if role, ok := s.policy.RoleMapping[group]; ok {
+			mapped[role] = true
+		}
+	}
+
+	for _, role := range model.Roles {
+		if mapped[role] {
+			return role
+		}
+	}
+	return s.policy.DefaultRole
+}
+
+func oidcUsername(identity *oidc.Identity) string {
+	switch {
+	case identity.PreferredUsername != "":
+		return strings.ToLower(identity.PreferredUsername)
+	case identity.Email != "":
+		return strings.ToLower(identity.Email)
+	default:
+		return "oidc-" + identity.Subject
+	}
+}
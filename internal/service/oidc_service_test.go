@@ -0,0 +1,198 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/danisasmita/customer-search/pkg/oidc"
+	"github.com/danisasmita/customer-search/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type memoryOIDCRepository struct {
+	states     map[string]model.OIDCState
+	identities []model.ExternalIdentity
+	users      []*model.User
+	// createErr dikembalikan CreateUserWithIdentity jika diisi, misalnya
+	// untuk meniru unique index yang menolak username.
+	createErr error
+}
+
+func (r *memoryOIDCRepository) CreateState(state *model.OIDCState) error {
+	r.states[state.StateHash] = *state
+	return nil
+}
+
+func (r *memoryOIDCRepository) ConsumeState(stateHash string) (*model.OIDCState, error) {
+	state, ok := r.states[stateHash]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	delete(r.states, stateHash)
+	return &state, nil
+}
+
+func (r *memoryOIDCRepository) FindIdentity(issuer, subject string) (*model.ExternalIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryOIDCRepository) CreateUserWithIdentity(user *model.User, identity *model.ExternalIdentity) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	user.ID = uint(len(r.users) + 100)
+	identity.UserID = user.ID
+	r.users = append(r.users, user)
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func TestOIDCServiceLogin(t *testing.T) {
+	server := oidctest.NewServer("customer-search", "s3cret")
+	defer server.Close()
+
+	client, err := oidc.Discover(context.Background(), oidc.Config{
+		Issuer:       server.Issuer(),
+		ClientID:     "customer-search",
+		ClientSecret: "s3cret",
+		RedirectURL:  "http://localhost:8080/oidc/callback",
+		TenantClaim:  "branch",
+	}, nil)
+	require.NoError(t, err)
+
+	users := new(MockUserRepository)
+	tokens := new(MockTokenService)
+	repo := &memoryOIDCRepository{states: map[string]model.OIDCState{}}
+	oidcService := service.NewOIDCService(client, repo, users, tokens, nil, service.OIDCPolicy{
+		RoleMapping:   map[string]string{"cs-admins": model.RoleAdmin, "cs-agents": model.RoleAgent},
+		DefaultTenant: "JKT",
+		StateTTL:      time.Minute,
+	}, logging.Discard())
+
+	// authorize mengembalikan state dan code dari redirect identity provider.
+	authorize := func() (string, string) {
+		authURL, state, err := oidcService.Begin()
+		require.NoError(t, err)
+
+		noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}}
+		resp, err := noRedirect.Get(authURL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		location, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, state, location.Query().Get("state"))
+		return state, location.Query().Get("code")
+	}
+
+	ctx := context.Background()
+
+	t.Run("first login creates user", func(t *testing.T) {
+		server.SetUser(oidctest.User{Subject: "sub-1", PreferredUsername: "Jane", Groups: []string{"cs-agents", "cs-admins"}})
+		users.On("FindUserByUsername", "jane").Return((*model.User)(nil), gorm.ErrRecordNotFound).Once()
+		tokens.On("Issue", uint(100), "JKT").Return(&model.LoginResponse{AccessToken: "access"}, nil).Once()
+
+		state, code := authorize()
+		response, err := oidcService.Callback(ctx, state, code)
+		require.NoError(t, err)
+		assert.Equal(t, "access", response.AccessToken)
+
+		require.Len(t, repo.users, 1)
+		assert.Equal(t, "jane", repo.users[0].Username)
+		assert.Equal(t, model.RoleAdmin, repo.users[0].Role)
+		assert.Equal(t, "JKT", repo.users[0].TenantID, "default branch")
+		assert.Empty(t, repo.users[0].Password)
+
+		// State hanya bisa dipakai sekali.
+		_, err = oidcService.Callback(ctx, state, code)
+		assert.ErrorIs(t, err, service.ErrInvalidOIDCState)
+	})
+
+	t.Run("next login syncs role and branch", func(t *testing.T) {
+		server.SetUser(oidctest.User{Subject: "sub-1", PreferredUsername: "jane", Groups: []string{"cs-agents"}, Branch: "SBY"})
+		existing := repo.users[0]
+		users.On("FindUserByID", uint(100)).Return(existing, nil).Once()
+		users.On("UpdateRole", uint(100), model.RoleAgent).Return(nil).Once()
+		users.On("UpdateTenant", uint(100), "SBY").Return(nil).Once()
+		tokens.On("Issue", uint(100), "SBY").Return(&model.LoginResponse{AccessToken: "access"}, nil).Once()
+
+		state, code := authorize()
+		_, err := oidcService.Callback(ctx, state, code)
+		require.NoError(t, err)
+		assert.Equal(t, model.RoleAgent, existing.Role)
+		assert.Equal(t, "SBY", existing.TenantID)
+		assert.Len(t, repo.users, 1)
+	})
+
+	t.Run("missing branch claim keeps assigned branch", func(t *testing.T) {
+		server.SetUser(oidctest.User{Subject: "sub-1", PreferredUsername: "jane", Groups: []string{"cs-agents"}})
+		existing := repo.users[0]
+		users.On("FindUserByID", uint(100)).Return(existing, nil).Once()
+		tokens.On("Issue", uint(100), "SBY").Return(&model.LoginResponse{AccessToken: "access"}, nil).Once()
+
+		state, code := authorize()
+		_, err := oidcService.Callback(ctx, state, code)
+		require.NoError(t, err)
+		assert.Equal(t, "SBY", existing.TenantID)
+	})
+
+	t.Run("invalid branch claim rejected", func(t *testing.T) {
+		server.SetUser(oidctest.User{Subject: "sub-1", PreferredUsername: "jane", Groups: []string{"cs-agents"}, Branch: "JKT SBY"})
+
+		state, code := authorize()
+		_, err := oidcService.Callback(ctx, state, code)
+		assert.ErrorIs(t, err, service.ErrOIDCLoginFailed)
+	})
+
+	t.Run("unmapped groups rejected", func(t *testing.T) {
+		server.SetUser(oidctest.User{Subject: "sub-2", PreferredUsername: "bob", Groups: []string{"marketing"}})
+
+		state, code := authorize()
+		_, err := oidcService.Callback(ctx, state, code)
+		assert.ErrorIs(t, err, service.ErrNoMappedRole)
+	})
+
+	t.Run("local username not linked", func(t *testing.T) {
+		server.SetUser(oidctest.User{Subject: "sub-3", Email: "Admin@Example.com", Groups: []string{"cs-agents"}})
+		users.On("FindUserByUsername", "admin@example.com").Return(&model.User{Username: "admin@example.com"}, nil).Once()
+
+		state, code := authorize()
+		_, err := oidcService.Callback(ctx, state, code)
+		assert.ErrorIs(t, err, service.ErrUsernameTaken)
+	})
+
+	t.Run("username taken concurrently", func(t *testing.T) {
+		server.SetUser(oidctest.User{Subject: "sub-4", PreferredUsername: "racer", Groups: []string{"cs-agents"}})
+		users.On("FindUserByUsername", "racer").Return((*model.User)(nil), gorm.ErrRecordNotFound).Once()
+		repo.createErr = gorm.ErrDuplicatedKey
+		defer func() { repo.createErr = nil }()
+
+		state, code := authorize()
+		_, err := oidcService.Callback(ctx, state, code)
+		assert.ErrorIs(t, err, service.ErrUsernameTaken)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		state, _ := authorize()
+		_, err := oidcService.Callback(ctx, state, "forged")
+		assert.ErrorIs(t, err, service.ErrOIDCLoginFailed)
+	})
+
+	users.AssertExpectations(t)
+	tokens.AssertExpectations(t)
+}
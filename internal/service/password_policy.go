@@ -0,0 +1,128 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/danisasmita/customer-search/pkg/message"
+)
+
+// Kode aturan stabil agar frontend bisa menampilkan pesan per aturan.
+const (
+	PasswordRuleMinLength        = "min_length"
+	PasswordRuleUppercase        = "uppercase"
+	PasswordRuleLowercase        = "lowercase"
+	PasswordRuleDigit            = "digit"
+	PasswordRuleSymbol           = "symbol"
+	PasswordRuleContainsUsername = "contains_username"
+	PasswordRuleCommon           = "common_password"
+)
+
+// Params mengisi placeholder pesan terjemahan, misalnya {min}.
+type PasswordRuleFailure struct {
+	Rule    string                 `json:"rule"`
+	Message string                 `json:"message"`
+	Params  map[string]interface{} `json:"-"`
+}
+
+// PasswordPolicyError berisi semua aturan yang gagal, bukan hanya yang
+// pertama, agar user bisa memperbaiki password sekaligus.
+type PasswordPolicyError struct {
+	Failures []PasswordRuleFailure
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return message.PasswordPolicyViolation
+}
+
+// PasswordPolicy dengan nilai nol hanya mewajibkan password tidak kosong.
+type PasswordPolicy struct {
+	MinLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSymbol  bool
+	RejectUsername bool
+	Common         map[string]struct{}
+}
+
+// Validate mengembalikan *PasswordPolicyError jika ada aturan yang gagal.
+func (p PasswordPolicy) Validate(username, password string) error {
+	var failures []PasswordRuleFailure
+	fail := func(rule, text string) {
+		failures = append(failures, PasswordRuleFailure{Rule: rule, Message: text})
+	}
+
+	if len([]rune(password)) < p.MinLength {
+		failures = append(failures, PasswordRuleFailure{
+			Rule:    PasswordRuleMinLength,
+			Message: fmt.Sprintf(message.PasswordTooShort, p.MinLength),
+			Params:  map[string]interface{}{"min": p.MinLength},
+		})
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		fail(PasswordRuleUppercase, message.PasswordNeedsUppercase)
+	}
+	if p.RequireLower && !lower {
+		fail(PasswordRuleLowercase, message.PasswordNeedsLowercase)
+	}
+	if p.RequireDigit && !digit {
+		fail(PasswordRuleDigit, message.PasswordNeedsDigit)
+	}
+	if p.RequireSymbol && !symbol {
+		fail(PasswordRuleSymbol, message.PasswordNeedsSymbol)
+	}
+
+	lowered := strings.ToLower(password)
+	if p.RejectUsername && username != "" && strings.Contains(lowered, strings.ToLower(username)) {
+		fail(PasswordRuleContainsUsername, message.PasswordContainsUsername)
+	}
+	if _, ok := p.Common[lowered]; ok {
+		fail(PasswordRuleCommon, message.PasswordTooCommon)
+	}
+
+	if len(failures) > 0 {
+		return &PasswordPolicyError{Failures: failures}
+	}
+	return nil
+}
+
+// LoadCommonPasswords membaca daftar password umum, satu per baris dan
+// terurut dari yang paling sering dipakai, lalu mengambil limit baris
+// pertama. limit <= 0 berarti seluruh file.
+func LoadCommonPasswords(path string, limit int) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	passwords := map[string]struct{}{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if limit > 0 && len(passwords) >= limit {
+			break
+		}
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			passwords[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return passwords, scanner.Err()
+}
@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func failedRules(err error) []string {
+	policyErr, ok := err.(*service.PasswordPolicyError)
+	if !ok {
+		return nil
+	}
+	var rules []string
+	for _, failure := range policyErr.Failures {
+		rules = append(rules, failure.Rule)
+	}
+	return rules
+}
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := service.PasswordPolicy{
+		MinLength:      10,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSymbol:  true,
+		RejectUsername: true,
+		Common:         map[string]struct{}{"password123!": {}},
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		expected []string
+	}{
+		{name: "Valid", username: "john", password: "Tr0ub4dor&3x", expected: nil},
+		{
+			name:     "Single Character",
+			username: "john",
+			password: "1",
+			expected: []string{
+				service.PasswordRuleMinLength,
+				service.PasswordRuleUppercase,
+				service.PasswordRuleLowercase,
+				service.PasswordRuleSymbol,
+			},
+		},
+		{name: "Contains Username", username: "john", password: "MyJohn#2024pass", expected: []string{service.PasswordRuleContainsUsername}},
+		{name: "Common", username: "jane", password: "Password123!", expected: []string{service.PasswordRuleCommon}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.username, tt.password)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.expected, failedRules(err))
+		})
+	}
+
+	// Policy kosong menerima password apa pun
+	assert.NoError(t, service.PasswordPolicy{}.Validate("john", "john"))
+}
+
+func TestLoadCommonPasswords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "common.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("# top passwords\n123456\nPassword\n\nqwerty\n"), 0o600))
+
+	passwords, err := service.LoadCommonPasswords(path, 2)
+	assert.NoError(t, err)
+	assert.Len(t, passwords, 2)
+	assert.Contains(t, passwords, "password")
+	assert.NotContains(t, passwords, "qwerty")
+
+	_, err = service.LoadCommonPasswords(filepath.Join(t.TempDir(), "missing.txt"), 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/repository"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/notifier"
+	"github.com/danisasmita/customer-search/pkg/utils"
+)
+
+var (
+	ErrInvalidPassword   = NewError(ErrUnauthorized, message.CodeInvalidCurrentPassword, message.InvalidCurrentPassword)
+	ErrInvalidResetToken = NewError(ErrValidation, message.CodeInvalidResetToken, message.InvalidResetToken)
+)
+
+type PasswordService interface {
+	Change(userID uint, oldPassword, newPassword, clientIP string) (*model.LoginResponse, error)
+	AdminReset(userID, adminID uint) error
+	RequestReset(username, clientIP string) error
+	Reset(token, newPassword string) error
+}
+
+type passwordService struct {
+	users         repository.UserRepository
+	resets        repository.PasswordResetRepository
+	tokens        TokenService
+	audit         AuditService
+	throttle      LoginThrottle
+	resetThrottle LoginThrottle
+	notifier      notifier.Notifier
+	policy        PasswordPolicy
+	resetTTL      time.Duration
+	jobs          *sync.WaitGroup
+	logger        *slog.Logger
+}
+
+// NewPasswordService memakai throttle login untuk pengecekan password lama
+// dan resetThrottle untuk membatasi permintaan reset per username dan IP.
+// Pengiriman token reset di background didaftarkan ke jobs agar shutdown
+// menunggunya sebelum database ditutup.
+func NewPasswordService(users repository.UserRepository, resets repository.PasswordResetRepository, tokens TokenService, audit AuditService, throttle, resetThrottle LoginThrottle, notifier notifier.Notifier, policy PasswordPolicy, resetTTL time.Duration, jobs *sync.WaitGroup, logger *slog.Logger) PasswordService {
+	return &passwordService{
+		users:         users,
+		resets:        resets,
+		tokens:        tokens,
+		audit:         audit,
+		throttle:      throttle,
+		resetThrottle: resetThrottle,
+		notifier:      notifier,
+		policy:        policy,
+		resetTTL:      resetTTL,
+		jobs:          jobs,
+		logger:        logger,
+	}
+}
+
+// Change mengganti password setelah password lama diverifikasi. Semua sesi
+// lama dicabut dan pemanggil mendapat pasangan token baru agar tetap login
+// di perangkat yang sedang dipakai. Password lama yang salah dihitung
+// bersama kegagalan login agar endpoint ini tidak bisa dipakai menebak
+// password tanpa terkena lockout.
+func (s *passwordService) Change(userID uint, oldPassword, newPassword, clientIP string) (*model.LoginResponse, error) {
+	user, err := s.users.FindUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.throttle.Check(user.Username, clientIP); err != nil {
+		return nil, err
+	}
+
+	if !utils.CheckPasswordHash(oldPassword, user.Password) {
+		if err := s.throttle.RecordFailure(user.Username, clientIP); err != nil {
+			s.logger.Error("failed to record login failure", "error", err)
+		}
+		return nil, ErrInvalidPassword
+	}
+	if err := s.throttle.RecordSuccess(user.Username); err != nil {
+		s.logger.Error("failed to reset login attempts", "user_id", user.ID, "error", err)
+	}
+
+	if err := s.setPassword(user, newPassword); err != nil {
+		return nil, err
+	}
+	return s.tokens.Issue(user.ID, user.TenantID)
+}
+
+// AdminReset mengirim token reset ke user, langsung mengakhiri semua
+// sesinya, dan mencatat admin yang memintanya.
+func (s *passwordService) AdminReset(userID, adminID uint) error {
+	user, err := s.users.FindUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.tokens.RevokeUser(user.ID); err != nil {
+		return err
+	}
+	if err := s.sendResetToken(user); err != nil {
+		return err
+	}
+
+	return recordAdminAction(s.audit, model.AuditActionPasswordReset, adminID, "user_id", user.ID, nil)
+}
+
+// RequestReset tidak memberi tahu pemanggil apakah username terdaftar agar
+// endpoint ini tidak bisa dipakai menebak username. Setiap permintaan,
+// terdaftar atau tidak, dihitung oleh resetThrottle. Token dibuat dan
+// dikirim di background sehingga waktu respons tidak bergantung pada
+// terdaftarnya username.
+func (s *passwordService) RequestReset(username, clientIP string) error {
+	if err := s.resetThrottle.Check(username, clientIP); err != nil {
+		return err
+	}
+	if err := s.resetThrottle.RecordFailure(username, clientIP); err != nil {
+		s.logger.Error("failed to record password reset request", "error", err)
+	}
+
+	user, err := s.users.FindUserByUsername(username)
+	if err != nil {
+		return nil
+	}
+
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		if err := s.sendResetToken(user); err != nil {
+			s.logger.Error("failed to send password reset token", "user_id", user.ID, "error", err)
+		}
+	}()
+	return nil
+}
+
+func (s *passwordService) Reset(token, newPassword string) error {
+	stored, err := s.resets.FindByHash(hashToken(token))
+	if err != nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.users.FindUserByID(stored.UserID)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	// Policy dicek sebelum token ditandai terpakai agar password yang
+	// ditolak tidak menghabiskan token.
+	if err := s.policy.Validate(user.Username, newPassword); err != nil {
+		return err
+	}
+
+	marked, err := s.resets.MarkUsed(stored.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return ErrInvalidResetToken
+	}
+
+	return s.setPassword(user, newPassword)
+}
+
+// setPassword menyimpan hash baru, membuang token reset yang tersisa, dan
+// mencabut semua sesi user.
+func (s *passwordService) setPassword(user *model.User, newPassword string) error {
+	if err := s.policy.Validate(user.Username, newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	// Hanya kolom password yang ditulis agar perubahan lain yang terjadi
+	// bersamaan, misalnya user dinonaktifkan admin, tidak tertimpa.
+	if err := s.users.UpdatePassword(user.ID, hashedPassword); err != nil {
+		return err
+	}
+
+	if err := s.resets.DeleteByUser(user.ID); err != nil {
+		s.logger.Error("failed to delete password reset tokens", "user_id", user.ID, "error", err)
+	}
+	return s.tokens.RevokeUser(user.ID)
+}
+
+func (s *passwordService) sendResetToken(user *model.User) error {
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(s.resetTTL)
+	err = s.resets.Create(&model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Send(notifier.Message{
+		To:      user.Username,
+		Subject: message.PasswordResetSubject,
+		Body:    fmt.Sprintf(message.PasswordResetBody, token, expiresAt.UTC().Format(time.RFC3339)),
+	})
+}
@@ -0,0 +1,264 @@
+package service_test
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/danisasmita/customer-search/pkg/notifier"
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// memoryPasswordResetRepository menyimpan token reset di memori.
+type memoryPasswordResetRepository struct {
+	mu     sync.Mutex
+	tokens []*model.PasswordResetToken
+}
+
+func (r *memoryPasswordResetRepository) Create(token *model.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token.ID = uint(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryPasswordResetRepository) FindByHash(tokenHash string) (*model.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryPasswordResetRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.ID == id && token.UsedAt == nil {
+			token.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryPasswordResetRepository) DeleteByUser(userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.tokens[:0]
+	for _, token := range r.tokens {
+		if token.UserID != userID {
+			kept = append(kept, token)
+		}
+	}
+	r.tokens = kept
+	return nil
+}
+
+// recordingNotifier menyimpan pesan yang dikirim agar token bisa dibaca.
+// Reset lewat RequestReset dikirim dari goroutine lain, jadi akses dijaga
+// mutex.
+type recordingNotifier struct {
+	mu       sync.Mutex
+	messages []notifier.Message
+}
+
+func (n *recordingNotifier) Send(msg notifier.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+func (n *recordingNotifier) sent() []notifier.Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]notifier.Message(nil), n.messages...)
+}
+
+// waitFor menunggu sampai count pesan terkirim.
+func (n *recordingNotifier) waitFor(t *testing.T, count int) {
+	t.Helper()
+	assert.Eventually(t, func() bool { return len(n.sent()) >= count }, time.Second, 5*time.Millisecond)
+}
+
+var resetTokenPattern = regexp.MustCompile(`password: (\S+)`)
+
+func (n *recordingNotifier) lastToken() string {
+	messages := n.sent()
+	if len(messages) == 0 {
+		return ""
+	}
+	match := resetTokenPattern.FindStringSubmatch(messages[len(messages)-1].Body)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+func newTestPasswordService(t *testing.T) (*model.User, *MockTokenService, *MockLoginThrottle, *recordingNotifier, service.PasswordService) {
+	user, tokens, throttle, notifications, _, passwords := newTestPasswordServiceWithAudit(t)
+	return user, tokens, throttle, notifications, passwords
+}
+
+// newTestPasswordServiceWithAudit juga mengembalikan audit log untuk
+// memeriksa reset password oleh admin.
+func newTestPasswordServiceWithAudit(t *testing.T) (*model.User, *MockTokenService, *MockLoginThrottle, *recordingNotifier, *memoryAuditRepository, service.PasswordService) {
+	hashedPassword, err := utils.HashPassword("OldPassword1")
+	assert.NoError(t, err)
+	user := &model.User{Model: gorm.Model{ID: 1}, Username: "john_doe", Password: hashedPassword}
+
+	users := new(MockUserRepository)
+	users.On("FindUserByID", uint(1)).Return(user, nil)
+	users.On("FindUserByID", uint(2)).Return((*model.User)(nil), gorm.ErrRecordNotFound)
+	users.On("FindUserByUsername", "john_doe").Return(user, nil)
+	users.On("FindUserByUsername", "ghost").Return((*model.User)(nil), gorm.ErrRecordNotFound)
+	users.On("UpdatePassword", uint(1), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		user.Password = args.String(1)
+	}).Return(nil)
+
+	tokens := new(MockTokenService)
+	tokens.On("RevokeUser", uint(1)).Return(nil)
+	tokens.On("Issue", uint(1), "").Return(&model.LoginResponse{AccessToken: "fresh"}, nil)
+
+	throttle := new(MockLoginThrottle)
+	throttle.On("Check", "john_doe", clientIP).Return(nil)
+	throttle.On("RecordFailure", "john_doe", clientIP).Return(nil)
+	throttle.On("RecordSuccess", "john_doe").Return(nil)
+
+	// Permintaan reset dibatasi dua per username dan tiga per IP
+	resetThrottle := service.NewLoginThrottle(
+		&memoryLoginAttemptRepository{attempts: map[string]model.LoginAttempt{}},
+		service.NewAuditService(&memoryAuditRepository{}),
+		service.LoginThrottlePolicy{KeyPrefix: "reset:", MaxFailures: 2, IPMaxFailures: 3, LockoutDuration: time.Hour},
+	)
+
+	notifications := &recordingNotifier{}
+	audits := &memoryAuditRepository{}
+	passwords := service.NewPasswordService(users, &memoryPasswordResetRepository{}, tokens, service.NewAuditService(audits), throttle, resetThrottle, notifications,
+		service.PasswordPolicy{MinLength: 10}, 30*time.Minute, &sync.WaitGroup{}, logging.Discard())
+	return user, tokens, throttle, notifications, audits, passwords
+}
+
+func TestPasswordServiceChange(t *testing.T) {
+	user, tokens, throttle, _, passwords := newTestPasswordService(t)
+
+	_, err := passwords.Change(1, "wrong", "NewPassword1", clientIP)
+	assert.ErrorIs(t, err, service.ErrInvalidPassword)
+	throttle.AssertCalled(t, "RecordFailure", "john_doe", clientIP)
+
+	_, err = passwords.Change(1, "OldPassword1", "short", clientIP)
+	var policyErr *service.PasswordPolicyError
+	assert.ErrorAs(t, err, &policyErr)
+
+	response, err := passwords.Change(1, "OldPassword1", "NewPassword1", clientIP)
+	assert.NoError(t, err)
+	assert.Equal(t, "fresh", response.AccessToken)
+	assert.True(t, utils.CheckPasswordHash("NewPassword1", user.Password))
+	tokens.AssertCalled(t, "RevokeUser", uint(1))
+	throttle.AssertCalled(t, "RecordSuccess", "john_doe")
+}
+
+func TestPasswordServiceChangeThrottled(t *testing.T) {
+	_, _, throttle, _, passwords := newTestPasswordService(t)
+	throttle.ExpectedCalls = nil
+	throttle.On("Check", "john_doe", clientIP).Return(&service.LoginThrottledError{RetryAfter: time.Minute})
+
+	// Akun yang terkunci tidak bisa dipakai menebak password lama
+	_, err := passwords.Change(1, "OldPassword1", "NewPassword1", clientIP)
+	var throttled *service.LoginThrottledError
+	assert.ErrorAs(t, err, &throttled)
+	throttle.AssertNotCalled(t, "RecordSuccess", "john_doe")
+}
+
+func TestPasswordServiceReset(t *testing.T) {
+	user, tokens, _, notifications, passwords := newTestPasswordService(t)
+
+	// Username yang tidak ada tidak menghasilkan error maupun pesan
+	assert.NoError(t, passwords.RequestReset("ghost", clientIP))
+	assert.Empty(t, notifications.sent())
+
+	assert.NoError(t, passwords.RequestReset("john_doe", clientIP))
+	notifications.waitFor(t, 1)
+	token := notifications.lastToken()
+	assert.NotEmpty(t, token)
+	assert.Equal(t, "john_doe", notifications.sent()[0].To)
+
+	var policyErr *service.PasswordPolicyError
+	assert.ErrorAs(t, passwords.Reset(token, "short"), &policyErr)
+
+	// Password yang ditolak policy tidak menghabiskan token
+	assert.NoError(t, passwords.Reset(token, "ResetPassword1"))
+	assert.True(t, utils.CheckPasswordHash("ResetPassword1", user.Password))
+	tokens.AssertCalled(t, "RevokeUser", uint(1))
+
+	assert.ErrorIs(t, passwords.Reset(token, "AnotherPassword1"), service.ErrInvalidResetToken)
+	assert.ErrorIs(t, passwords.Reset("made-up", "AnotherPassword1"), service.ErrInvalidResetToken)
+}
+
+func TestPasswordServiceAdminReset(t *testing.T) {
+	_, tokens, _, notifications, audits, passwords := newTestPasswordServiceWithAudit(t)
+
+	assert.ErrorIs(t, passwords.AdminReset(2, 9), service.ErrUserNotFound)
+
+	assert.NoError(t, passwords.AdminReset(1, 9))
+	tokens.AssertCalled(t, "RevokeUser", uint(1))
+	assert.NotEmpty(t, notifications.lastToken())
+	if assert.Len(t, audits.logs, 1) {
+		assert.Equal(t, model.AuditActionPasswordReset, audits.logs[0].Action)
+		assert.Equal(t, uint(9), audits.logs[0].UserID)
+		assert.JSONEq(t, `{"user_id":"1"}`, audits.logs[0].Query)
+	}
+}
+
+func TestPasswordServiceRequestResetThrottled(t *testing.T) {
+	_, _, throttle, notifications, passwords := newTestPasswordService(t)
+
+	assert.NoError(t, passwords.RequestReset("john_doe", clientIP))
+	assert.NoError(t, passwords.RequestReset("john_doe", clientIP))
+	var throttled *service.LoginThrottledError
+	assert.ErrorAs(t, passwords.RequestReset("john_doe", clientIP), &throttled)
+	notifications.waitFor(t, 2)
+
+	// Username yang tidak terdaftar dihitung dengan cara yang sama
+	assert.NoError(t, passwords.RequestReset("ghost", "10.0.0.9"))
+	assert.NoError(t, passwords.RequestReset("ghost", clientIP))
+	assert.ErrorAs(t, passwords.RequestReset("ghost", "10.0.0.8"), &throttled, "per-user limit")
+	assert.ErrorAs(t, passwords.RequestReset("jane_doe", clientIP), &throttled, "per-IP limit")
+	assert.Len(t, notifications.sent(), 2)
+
+	// Hitungan reset terpisah dari hitungan login
+	throttle.AssertNotCalled(t, "RecordFailure", "john_doe", clientIP)
+}
+
+func TestPasswordServiceRequestResetTracksJob(t *testing.T) {
+	user := &model.User{Model: gorm.Model{ID: 1}, Username: "john_doe"}
+	users := new(MockUserRepository)
+	users.On("FindUserByUsername", "john_doe").Return(user, nil)
+	resetThrottle := new(MockLoginThrottle)
+	resetThrottle.On("Check", "john_doe", clientIP).Return(nil)
+	resetThrottle.On("RecordFailure", "john_doe", clientIP).Return(nil)
+
+	var jobs sync.WaitGroup
+	notifications := &recordingNotifier{}
+	passwords := service.NewPasswordService(users, &memoryPasswordResetRepository{}, new(MockTokenService), service.NewAuditService(&memoryAuditRepository{}),
+		new(MockLoginThrottle), resetThrottle, notifications, service.PasswordPolicy{}, 30*time.Minute, &jobs, logging.Discard())
+
+	assert.NoError(t, passwords.RequestReset("john_doe", clientIP))
+
+	// Shutdown menunggu jobs, jadi token sudah terkirim setelah Wait
+	jobs.Wait()
+	assert.Len(t, notifications.sent(), 1)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/repository"
+	"github.com/danisasmita/customer-search/pkg/utils"
+)
+
+// RevocationStore menyimpan daftar token yang dicabut di memori agar
+// JWTAuth tidak perlu ke database di setiap request. Tabel
+// token_revocations menjadi sumber kebenaran dan dibaca ulang secara
+// berkala supaya pencabutan dari instance lain ikut terlihat.
+type RevocationStore interface {
+	IsRevoked(claims *utils.Claims) bool
+	RevokeToken(claims *utils.Claims) error
+	RevokeUser(userID uint) error
+	Sync() error
+	Prune() error
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type revokedUser struct {
+	issuedBefore time.Time
+	expiresAt    time.Time
+}
+
+type revocationStore struct {
+	repo   repository.TokenRevocationRepository
+	mu     sync.RWMutex
+	tokens map[string]time.Time
+	users  map[uint]revokedUser
+	lastID uint
+	// tokenTTL adalah masa berlaku access token terpanjang, termasuk token
+	// impersonation, sehingga pencabutan user bertahan selama token
+	// apa pun yang terbit sebelumnya masih berlaku.
+	tokenTTL time.Duration
+	logger   *slog.Logger
+}
+
+func NewRevocationStore(repo repository.TokenRevocationRepository, tokenTTL time.Duration, logger *slog.Logger) RevocationStore {
+	return &revocationStore{
+		repo:     repo,
+		tokens:   map[string]time.Time{},
+		users:    map[uint]revokedUser{},
+		tokenTTL: tokenTTL,
+		logger:   logger,
+	}
+}
+
+// IsRevoked juga memeriksa admin pada token impersonation, sehingga
+// mencabut sesi admin ikut mengakhiri impersonation yang sedang berjalan.
+func (s *revocationStore) IsRevoked(claims *utils.Claims) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.tokens[claims.Id]; ok {
+		return true
+	}
+	issuedAt := time.Unix(claims.IssuedAt, 0)
+	for _, userID := range []uint{claims.UserID, claims.ActorID()} {
+		if user, ok := s.users[userID]; ok && userID != 0 && issuedAt.Before(user.issuedBefore) {
+			return true
+		}
+	}
+	return false
+}
+
+// RevokeToken mencabut satu access token sampai masa berlakunya habis.
+func (s *revocationStore) RevokeToken(claims *utils.Claims) error {
+	return s.save(&model.TokenRevocation{
+		JTI:       claims.Id,
+		UserID:    claims.UserID,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	})
+}
+
+// RevokeUser mencabut semua access token user yang terbit sebelum detik
+// pencabutan. iat hanya berpresisi detik, jadi batasnya dibulatkan ke bawah
+// ke detik: token yang diterbitkan pemanggil sesudahnya, misalnya setelah
+// ganti password, tetap berlaku tanpa harus menunggu detik berikutnya.
+// Token yang terbit di detik yang sama sebelum pencabutan juga lolos; celah
+// kurang dari satu detik ini ditutup oleh LoadUser yang menolak user
+// nonaktif.
+func (s *revocationStore) RevokeUser(userID uint) error {
+	now := time.Now()
+	return s.save(&model.TokenRevocation{
+		UserID:       userID,
+		IssuedBefore: now.Truncate(time.Second),
+		ExpiresAt:    now.Add(s.tokenTTL),
+	})
+}
+
+func (s *revocationStore) save(revocation *model.TokenRevocation) error {
+	if err := s.repo.Create(revocation); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apply(revocation)
+	return nil
+}
+
+// Sync memuat pencabutan baru dari database.
+func (s *revocationStore) Sync() error {
+	s.mu.RLock()
+	lastID := s.lastID
+	s.mu.RUnlock()
+
+	revocations, err := s.repo.FindActiveAfter(lastID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range revocations {
+		s.apply(&revocations[i])
+		s.lastID = max(s.lastID, revocations[i].ID)
+	}
+	return nil
+}
+
+// Prune membuang pencabutan yang token-tokennya sudah kedaluwarsa, dari
+// memori maupun database.
+func (s *revocationStore) Prune() error {
+	now := time.Now()
+
+	s.mu.Lock()
+	for jti, expiresAt := range s.tokens {
+		if !expiresAt.After(now) {
+			delete(s.tokens, jti)
+		}
+	}
+	for userID, user := range s.users {
+		if !user.expiresAt.After(now) {
+			delete(s.users, userID)
+		}
+	}
+	s.mu.Unlock()
+
+	return s.repo.DeleteExpired(now)
+}
+
+// Run menjalankan Sync dan Prune secara berkala sampai ctx selesai.
+func (s *revocationStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Sync(); err != nil {
+				s.logger.ErrorContext(ctx, "failed to sync token revocations", "error", err)
+			}
+			if err := s.Prune(); err != nil {
+				s.logger.ErrorContext(ctx, "failed to prune token revocations", "error", err)
+			}
+		}
+	}
+}
+
+// apply tidak mengubah lastID; hanya Sync yang boleh memajukannya agar
+// baris dari instance lain dengan ID lebih kecil tidak terlewat.
+func (s *revocationStore) apply(revocation *model.TokenRevocation) {
+	if revocation.JTI != "" {
+		s.tokens[revocation.JTI] = revocation.ExpiresAt
+		return
+	}
+
+	current, ok := s.users[revocation.UserID]
+	if !ok || revocation.IssuedBefore.After(current.issuedBefore) {
+		s.users[revocation.UserID] = revokedUser{
+			issuedBefore: revocation.IssuedBefore,
+			expiresAt:    revocation.ExpiresAt,
+		}
+	}
+}
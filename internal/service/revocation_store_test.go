@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryTokenRevocationRepository struct {
+	revocations []model.TokenRevocation
+}
+
+func (r *memoryTokenRevocationRepository) Create(revocation *model.TokenRevocation) error {
+	revocation.ID = uint(len(r.revocations) + 1)
+	r.revocations = append(r.revocations, *revocation)
+	return nil
+}
+
+func (r *memoryTokenRevocationRepository) FindActiveAfter(lastID uint, now time.Time) ([]model.TokenRevocation, error) {
+	var result []model.TokenRevocation
+	for _, revocation := range r.revocations {
+		if revocation.ID > lastID && revocation.ExpiresAt.After(now) {
+			result = append(result, revocation)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryTokenRevocationRepository) DeleteExpired(now time.Time) error {
+	var kept []model.TokenRevocation
+	for _, revocation := range r.revocations {
+		if revocation.ExpiresAt.After(now) {
+			kept = append(kept, revocation)
+		}
+	}
+	r.revocations = kept
+	return nil
+}
+
+func newClaims(userID uint, jti string, issuedAt, expiresAt time.Time) *utils.Claims {
+	return &utils.Claims{
+		UserID: userID,
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti,
+			IssuedAt:  issuedAt.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+}
+
+func TestRevocationStoreRevokeToken(t *testing.T) {
+	store := service.NewRevocationStore(&memoryTokenRevocationRepository{}, time.Hour, logging.Discard())
+	now := time.Now()
+
+	revoked := newClaims(1, "jti-1", now, now.Add(time.Hour))
+	active := newClaims(1, "jti-2", now, now.Add(time.Hour))
+
+	assert.NoError(t, store.RevokeToken(revoked))
+	assert.True(t, store.IsRevoked(revoked))
+	assert.False(t, store.IsRevoked(active))
+}
+
+func TestRevocationStoreRevokeActor(t *testing.T) {
+	store := service.NewRevocationStore(&memoryTokenRevocationRepository{}, time.Hour, logging.Discard())
+	issuedAt := time.Now().Add(-time.Minute)
+
+	impersonation := newClaims(2, "jti-1", issuedAt, issuedAt.Add(time.Hour))
+	impersonation.Act = &utils.Actor{Subject: "1"}
+	other := newClaims(2, "jti-2", issuedAt, issuedAt.Add(time.Hour))
+
+	// Mencabut sesi admin ikut mengakhiri impersonation miliknya
+	assert.NoError(t, store.RevokeUser(1))
+	assert.True(t, store.IsRevoked(impersonation))
+	assert.False(t, store.IsRevoked(other))
+}
+
+func TestRevocationStoreRevokeUser(t *testing.T) {
+	repo := &memoryTokenRevocationRepository{}
+	store := service.NewRevocationStore(repo, 2*time.Hour, logging.Discard())
+
+	// RevokeUser tidak menunggu pergantian detik
+	start := time.Now()
+	assert.NoError(t, store.RevokeUser(1))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	// Batas berpresisi detik seperti iat, dan baris bertahan selama TTL
+	// token terpanjang.
+	require.Len(t, repo.revocations, 1)
+	cutoff := repo.revocations[0].IssuedBefore
+	assert.Equal(t, cutoff.Truncate(time.Second), cutoff)
+	assert.WithinDuration(t, start, cutoff, time.Second)
+	assert.WithinDuration(t, start.Add(2*time.Hour), repo.revocations[0].ExpiresAt, 2*time.Second)
+
+	// Token dari detik sebelumnya dicabut. Token yang terbit di detik
+	// pencabutan, misalnya token baru setelah ganti password, tetap berlaku.
+	assert.True(t, store.IsRevoked(newClaims(1, "jti-1", cutoff.Add(-time.Second), cutoff.Add(time.Hour))))
+	assert.False(t, store.IsRevoked(newClaims(1, "jti-2", cutoff, cutoff.Add(time.Hour))))
+}
+
+func TestRevocationStoreSync(t *testing.T) {
+	repo := &memoryTokenRevocationRepository{}
+	now := time.Now()
+	claims := newClaims(1, "jti-1", now, now.Add(time.Hour))
+
+	// Instance lain mencabut token lewat database yang sama
+	other := service.NewRevocationStore(repo, time.Hour, logging.Discard())
+	assert.NoError(t, other.RevokeToken(claims))
+
+	store := service.NewRevocationStore(repo, time.Hour, logging.Discard())
+	assert.False(t, store.IsRevoked(claims))
+	assert.NoError(t, store.Sync())
+	assert.True(t, store.IsRevoked(claims))
+}
+
+func TestRevocationStorePrune(t *testing.T) {
+	repo := &memoryTokenRevocationRepository{}
+	store := service.NewRevocationStore(repo, time.Hour, logging.Discard())
+	now := time.Now()
+
+	expired := newClaims(1, "jti-old", now.Add(-2*time.Hour), now.Add(-time.Hour))
+	active := newClaims(1, "jti-new", now, now.Add(time.Hour))
+	assert.NoError(t, store.RevokeToken(expired))
+	assert.NoError(t, store.RevokeToken(active))
+
+	assert.NoError(t, store.Prune())
+	assert.Len(t, repo.revocations, 1)
+	assert.False(t, store.IsRevoked(expired))
+	assert.True(t, store.IsRevoked(active))
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/repository"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidRefreshToken = NewError(ErrUnauthorized, message.CodeInvalidRefreshToken, message.InvalidRefreshToken)
+
+type TokenService interface {
+	Issue(userID uint, tenantID string) (*model.LoginResponse, error)
+	Refresh(refreshToken string) (*model.LoginResponse, error)
+	Logout(claims *utils.Claims, refreshToken string) error
+	RevokeUser(userID uint) error
+}
+
+type tokenService struct {
+	repo        repository.RefreshTokenRepository
+	users       repository.UserRepository
+	revocations RevocationStore
+	refreshTTL  time.Duration
+}
+
+func NewTokenService(repo repository.RefreshTokenRepository, users repository.UserRepository, revocations RevocationStore, refreshTTL time.Duration) TokenService {
+	return &tokenService{repo: repo, users: users, revocations: revocations, refreshTTL: refreshTTL}
+}
+
+// Issue membuat access token dan refresh token dari family baru. tenantID
+// ikut disimpan di refresh token sehingga token hasil refresh tetap
+// membawa cabang yang sama.
+func (s *tokenService) Issue(userID uint, tenantID string) (*model.LoginResponse, error) {
+	familyID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(userID, tenantID, familyID)
+}
+
+// Refresh menukar refresh token dengan pasangan token baru dan mematikan
+// token lama. Jika token yang sudah pernah dipakai datang lagi, kemungkinan
+// token itu dicuri, sehingga seluruh family dicabut. User dibaca ulang
+// agar user yang dinonaktifkan atau dihapus tidak bisa refresh dan token
+// baru membawa cabang user saat ini, bukan cabang saat login.
+func (s *tokenService) Refresh(refreshToken string) (*model.LoginResponse, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	stored, err := s.repo.FindByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	now := time.Now()
+	if stored.RevokedAt != nil || now.After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil {
+		return nil, s.revokeFamily(stored.FamilyID, now)
+	}
+
+	user, err := s.users.FindUserByID(stored.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user.Disabled) {
+		return nil, s.revokeFamily(stored.FamilyID, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	marked, err := s.repo.MarkUsed(stored.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		// Request lain memakai token yang sama lebih dulu.
+		return nil, s.revokeFamily(stored.FamilyID, now)
+	}
+
+	return s.issue(stored.UserID, user.TenantID, stored.FamilyID)
+}
+
+// Logout mencabut access token yang sedang dipakai dan, jika dikirim,
+// seluruh family dari refresh token milik user yang sama.
+func (s *tokenService) Logout(claims *utils.Claims, refreshToken string) error {
+	if err := s.revocations.RevokeToken(claims); err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.repo.FindByHash(hashToken(refreshToken))
+	if err != nil || stored.UserID != claims.UserID {
+		return nil
+	}
+	return s.repo.RevokeFamily(stored.FamilyID, time.Now())
+}
+
+// RevokeUser mengakhiri semua sesi user: access token yang sudah terbit
+// dan semua refresh token.
+func (s *tokenService) RevokeUser(userID uint) error {
+	if err := s.repo.RevokeUser(userID, time.Now()); err != nil {
+		return err
+	}
+	return s.revocations.RevokeUser(userID)
+}
+
+func (s *tokenService) issue(userID uint, tenantID, familyID string) (*model.LoginResponse, error) {
+	accessToken, err := utils.GenerateJWT(userID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Create(&model.RefreshToken{
+		UserID:    userID,
+		TenantID:  tenantID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(utils.TokenTTL().Seconds()),
+	}, nil
+}
+
+func (s *tokenService) revokeFamily(familyID string, now time.Time) error {
+	if err := s.repo.RevokeFamily(familyID, now); err != nil {
+		return err
+	}
+	return ErrInvalidRefreshToken
+}
+
+func randomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken cukup memakai SHA-256 karena token opaque sudah acak penuh.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
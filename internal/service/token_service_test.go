@@ -0,0 +1,207 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type memoryRefreshTokenRepository struct {
+	tokens []*model.RefreshToken
+}
+
+func (r *memoryRefreshTokenRepository) Create(token *model.RefreshToken) error {
+	token.ID = uint(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) FindByHash(hash string) (*model.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memoryRefreshTokenRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	token := r.tokens[id-1]
+	if token.UsedAt != nil {
+		return false, nil
+	}
+	token.UsedAt = &usedAt
+	return true, nil
+}
+
+func (r *memoryRefreshTokenRepository) RevokeFamily(familyID string, revokedAt time.Time) error {
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) RevokeUser(userID uint, revokedAt time.Time) error {
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+// activeUsers menganggap setiap user ID aktif di cabang tenantID.
+func activeUsers(tenantID string) *MockUserRepository {
+	users := new(MockUserRepository)
+	users.On("FindUserByID", mock.Anything).Return(&model.User{TenantID: tenantID}, nil)
+	return users
+}
+
+func TestTokenServiceIssue(t *testing.T) {
+	repo := &memoryRefreshTokenRepository{}
+	tokenService := service.NewTokenService(repo, activeUsers(""), service.NewRevocationStore(&memoryTokenRevocationRepository{}, time.Hour, logging.Discard()), time.Hour)
+
+	tokens, err := tokenService.Issue(1, "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.Equal(t, int64(utils.TokenTTL().Seconds()), tokens.ExpiresIn)
+
+	claims, err := utils.ValidateJWT(tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), claims.UserID)
+
+	// Token mentah tidak boleh tersimpan
+	assert.Len(t, repo.tokens, 1)
+	assert.NotEqual(t, tokens.RefreshToken, repo.tokens[0].TokenHash)
+}
+
+func TestTokenServiceRefresh(t *testing.T) {
+	t.Run("success - rotate token", func(t *testing.T) {
+		repo := &memoryRefreshTokenRepository{}
+		tokenService := service.NewTokenService(repo, activeUsers("JKT"), service.NewRevocationStore(&memoryTokenRevocationRepository{}, time.Hour, logging.Discard()), time.Hour)
+		first, _ := tokenService.Issue(1, "JKT")
+
+		second, err := tokenService.Refresh(first.RefreshToken)
+		assert.NoError(t, err)
+		assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+		assert.Equal(t, repo.tokens[0].FamilyID, repo.tokens[1].FamilyID)
+		assert.NotNil(t, repo.tokens[0].UsedAt)
+
+		// Cabang ikut terbawa ke access token hasil refresh
+		claims, err := utils.ValidateJWT(second.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, "JKT", claims.TenantID)
+	})
+
+	t.Run("success - current tenant", func(t *testing.T) {
+		repo := &memoryRefreshTokenRepository{}
+		tokenService := service.NewTokenService(repo, activeUsers("SBY"), service.NewRevocationStore(&memoryTokenRevocationRepository{}, time.Hour, logging.Discard()), time.Hour)
+		first, _ := tokenService.Issue(1, "JKT")
+
+		// User dipindah ke cabang lain setelah login
+		second, err := tokenService.Refresh(first.RefreshToken)
+		assert.NoError(t, err)
+		claims, err := utils.ValidateJWT(second.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, "SBY", claims.TenantID)
+	})
+
+	t.Run("error - user disabled or deleted", func(t *testing.T) {
+		for name, lookup := range map[string][]any{
+			"disabled": {&model.User{Disabled: true}, nil},
+			"deleted":  {(*model.User)(nil), gorm.ErrRecordNotFound},
+		} {
+			users := new(MockUserRepository)
+			users.On("FindUserByID", uint(1)).Return(lookup...)
+			repo := &memoryRefreshTokenRepository{}
+			tokenService := service.NewTokenService(repo, users, service.NewRevocationStore(&memoryTokenRevocationRepository{}, time.Hour, logging.Discard()), time.Hour)
+			first, _ := tokenService.Issue(1, "")
+
+			_, err := tokenService.Refresh(first.RefreshToken)
+			assert.ErrorIs(t, err, service.ErrInvalidRefreshToken, name)
+			assert.NotNil(t, repo.tokens[0].RevokedAt, name)
+		}
+	})
+
+	t.Run("error - reuse revokes family", func(t *testing.T) {
+		repo := &memoryRefreshTokenRepository{}
+		tokenService := service.NewTokenService(repo, activeUsers(""), service.NewRevocationStore(&memoryTokenRevocationRepository{}, time.Hour, logging.Discard()), time.Hour)
+		first, _ := tokenService.Issue(1, "")
+		second, _ := tokenService.Refresh(first.RefreshToken)
+
+		_, err := tokenService.Refresh(first.RefreshToken)
+		assert.ErrorIs(t, err, service.ErrInvalidRefreshToken)
+
+		// Token terbaru milik family yang sama ikut dicabut
+		_, err = tokenService.Refresh(second.RefreshToken)
+		assert.ErrorIs(t, err, service.ErrInvalidRefreshToken)
+		for _, token := range repo.tokens {
+			assert.NotNil(t, token.RevokedAt)
+		}
+	})
+
+	t.Run("error - expired token", func(t *testing.T) {
+		repo := &memoryRefreshTokenRepository{}
+		tokenService := service.NewTokenService(repo, activeUsers(""), service.NewRevocationStore(&memoryTokenRevocationRepository{}, time.Hour, logging.Discard()), -time.Minute)
+		first, _ := tokenService.Issue(1, "")
+
+		_, err := tokenService.Refresh(first.RefreshToken)
+		assert.ErrorIs(t, err, service.ErrInvalidRefreshToken)
+	})
+
+	t.Run("error - unknown token", func(t *testing.T) {
+		tokenService := service.NewTokenService(&memoryRefreshTokenRepository{}, activeUsers(""), service.NewRevocationStore(&memoryTokenRevocationRepository{}, time.Hour, logging.Discard()), time.Hour)
+
+		_, err := tokenService.Refresh("does-not-exist")
+		assert.ErrorIs(t, err, service.ErrInvalidRefreshToken)
+	})
+}
+
+func TestTokenServiceLogout(t *testing.T) {
+	repo := &memoryRefreshTokenRepository{}
+	revocations := service.NewRevocationStore(&memoryTokenRevocationRepository{}, time.Hour, logging.Discard())
+	tokenService := service.NewTokenService(repo, activeUsers(""), revocations, time.Hour)
+
+	tokens, _ := tokenService.Issue(1, "")
+	claims, _ := utils.ValidateJWT(tokens.AccessToken)
+
+	assert.NoError(t, tokenService.Logout(claims, tokens.RefreshToken))
+	assert.True(t, revocations.IsRevoked(claims))
+
+	_, err := tokenService.Refresh(tokens.RefreshToken)
+	assert.ErrorIs(t, err, service.ErrInvalidRefreshToken)
+}
+
+func TestTokenServiceRevokeUser(t *testing.T) {
+	repo := &memoryRefreshTokenRepository{}
+	revocations := service.NewRevocationStore(&memoryTokenRevocationRepository{}, time.Hour, logging.Discard())
+	tokenService := service.NewTokenService(repo, activeUsers(""), revocations, time.Hour)
+
+	tokens, _ := tokenService.Issue(1, "")
+	other, _ := tokenService.Issue(2, "")
+
+	// Token lama diberi iat satu detik lebih awal supaya jelas terbit
+	// sebelum pencabutan.
+	claims, _ := utils.ValidateJWT(tokens.AccessToken)
+	claims.IssuedAt--
+	otherClaims, _ := utils.ValidateJWT(other.AccessToken)
+	otherClaims.IssuedAt--
+
+	assert.NoError(t, tokenService.RevokeUser(1))
+	assert.True(t, revocations.IsRevoked(claims))
+	assert.False(t, revocations.IsRevoked(otherClaims))
+
+	_, err := tokenService.Refresh(tokens.RefreshToken)
+	assert.ErrorIs(t, err, service.ErrInvalidRefreshToken)
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/repository"
+	"github.com/danisasmita/customer-search/pkg/i18n"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUsernameTaken   = NewError(ErrConflict, message.CodeUsernameTaken, message.UsernameTaken)
+	ErrInvalidRole     = &Error{Kind: ErrValidation, Code: message.CodeInvalidRole, Message: message.InvalidRole, Details: map[string]interface{}{"roles": model.Roles}}
+	ErrInvalidTenant   = NewError(ErrValidation, message.CodeInvalidTenant, message.InvalidTenant)
+	ErrSelfModify      = NewError(ErrValidation, message.CodeCannotModifySelf, message.CannotModifySelf)
+	ErrUserDisabled    = NewError(ErrForbidden, message.CodeAccountDisabled, message.AccountDisabled)
+	ErrInvalidLanguage = &Error{Kind: ErrValidation, Code: message.CodeInvalidLanguage, Message: message.InvalidLanguage, Details: map[string]interface{}{"languages": i18n.Languages}}
+)
+
+// UserService berisi operasi admin atas akun user. Setiap perubahan oleh
+// admin dicatat di audit log atas nama admin yang melakukannya.
+// SetLanguage dipakai user untuk akunnya sendiri, Bootstrap oleh perintah
+// CLI -create-admin.
+type UserService interface {
+	List(filter model.UserFilter) ([]model.User, int64, error)
+	Create(request model.CreateUserRequest, adminID uint) (*model.User, error)
+	SetDisabled(userID uint, disabled bool, adminID uint) (*model.User, error)
+	AssignRole(userID uint, role string, adminID uint) (*model.User, error)
+	AssignTenant(userID uint, tenantID string, adminID uint) (*model.User, error)
+	Delete(userID, adminID uint) error
+	SetLanguage(userID uint, language string) (*model.User, error)
+	Bootstrap(username, password, tenantID string) (*model.User, error)
+}
+
+type userService struct {
+	repo   repository.UserRepository
+	tokens TokenService
+	audit  AuditService
+	policy PasswordPolicy
+}
+
+func NewUserService(repo repository.UserRepository, tokens TokenService, audit AuditService, policy PasswordPolicy) UserService {
+	return &userService{repo: repo, tokens: tokens, audit: audit, policy: policy}
+}
+
+func (s *userService) List(filter model.UserFilter) ([]model.User, int64, error) {
+	return s.repo.FindUsers(filter)
+}
+
+func (s *userService) Create(request model.CreateUserRequest, adminID uint) (*model.User, error) {
+	if request.Role == "" {
+		request.Role = model.RoleAgent
+	}
+	if !model.ValidRole(request.Role) {
+		return nil, ErrInvalidRole
+	}
+	if request.TenantID != "" && !model.ValidTenant(request.TenantID) {
+		return nil, ErrInvalidTenant
+	}
+
+	if _, err := s.repo.FindUserByUsername(request.Username); err == nil {
+		return nil, ErrUsernameTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.policy.Validate(request.Username, request.Password); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := utils.HashPassword(request.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.User{Username: request.Username, Password: hashedPassword, Role: request.Role, TenantID: request.TenantID}
+	if err := s.repo.CreateUser(user); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrUsernameTaken
+		}
+		return nil, err
+	}
+
+	return user, s.record(model.AuditActionUserCreate, adminID, user.ID, map[string]string{"role": user.Role, "tenant_id": user.TenantID})
+}
+
+// SetDisabled langsung mengakhiri semua sesi user yang dinonaktifkan.
+func (s *userService) SetDisabled(userID uint, disabled bool, adminID uint) (*model.User, error) {
+	if userID == adminID {
+		return nil, ErrSelfModify
+	}
+
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	user.Disabled = disabled
+	if err := s.repo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	action := model.AuditActionUserEnable
+	if disabled {
+		action = model.AuditActionUserDisable
+		if err := s.tokens.RevokeUser(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, s.record(action, adminID, user.ID, nil)
+}
+
+// AssignRole tidak boleh dipakai admin untuk dirinya sendiri agar admin
+// terakhir tidak bisa menurunkan role-nya tanpa sengaja. Semua sesi user
+// dicabut agar token admin dan impersonation milik admin yang diturunkan
+// tidak berlaku lagi.
+func (s *userService) AssignRole(userID uint, role string, adminID uint) (*model.User, error) {
+	if !model.ValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if userID == adminID {
+		return nil, ErrSelfModify
+	}
+
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	previous := user.Role
+	if err := s.repo.UpdateRole(user.ID, role); err != nil {
+		return nil, err
+	}
+	user.Role = role
+	if err := s.tokens.RevokeUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, s.record(model.AuditActionUserRole, adminID, user.ID, map[string]string{"from": previous, "to": role})
+}
+
+// AssignTenant memindahkan user ke cabang lain. Semua sesinya dicabut
+// karena token lama masih membawa cabang sebelumnya.
+func (s *userService) AssignTenant(userID uint, tenantID string, adminID uint) (*model.User, error) {
+	if !model.ValidTenant(tenantID) {
+		return nil, ErrInvalidTenant
+	}
+
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	previous := user.TenantID
+	user.TenantID = tenantID
+	if err := s.repo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+	if err := s.tokens.RevokeUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, s.record(model.AuditActionUserTenant, adminID, user.ID, map[string]string{"from": previous, "to": tenantID})
+}
+
+func (s *userService) Delete(userID, adminID uint) error {
+	if userID == adminID {
+		return ErrSelfModify
+	}
+
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.tokens.RevokeUser(user.ID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteUser(user.ID); err != nil {
+		return err
+	}
+
+	return s.record(model.AuditActionUserDelete, adminID, user.ID, nil)
+}
+
+// SetLanguage menyimpan bahasa pilihan user. Bahasa kosong menghapus
+// pilihan sehingga Accept-Language dipakai lagi.
+func (s *userService) SetLanguage(userID uint, language string) (*model.User, error) {
+	if language != "" && !i18n.Supported(language) {
+		return nil, ErrInvalidLanguage
+	}
+
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	user.Language = language
+	if err := s.repo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Bootstrap membuat admin dari CLI saat belum ada admin yang bisa memakai
+// /admin, misalnya setelah upgrade ketika semua user lama menjadi agent.
+// Jika username sudah ada, user itu dijadikan admin dan diaktifkan lagi;
+// passwordnya tidak diubah. Perubahan dicatat di audit log dengan user_id 0.
+func (s *userService) Bootstrap(username, password, tenantID string) (*model.User, error) {
+	if tenantID != "" && !model.ValidTenant(tenantID) {
+		return nil, ErrInvalidTenant
+	}
+
+	user, err := s.repo.FindUserByUsername(username)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.Create(model.CreateUserRequest{Username: username, Password: password, Role: model.RoleAdmin, TenantID: tenantID}, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	previous := user.Role
+	user.Role = model.RoleAdmin
+	user.Disabled = false
+	if tenantID != "" {
+		user.TenantID = tenantID
+	}
+	if err := s.repo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+	return user, s.record(model.AuditActionUserRole, 0, user.ID, map[string]string{"from": previous, "to": model.RoleAdmin})
+}
+
+func (s *userService) record(action string, adminID, targetID uint, details map[string]string) error {
+	return recordAdminAction(s.audit, action, adminID, "user_id", targetID, details)
+}
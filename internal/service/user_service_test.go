@@ -0,0 +1,195 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/internal/service"
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newTestUserService() (*MockUserRepository, *MockTokenService, *memoryAuditRepository, service.UserService) {
+	users := new(MockUserRepository)
+	tokens := new(MockTokenService)
+	audits := &memoryAuditRepository{}
+	userService := service.NewUserService(users, tokens, service.NewAuditService(audits), service.PasswordPolicy{MinLength: 10})
+	return users, tokens, audits, userService
+}
+
+func TestUserServiceCreate(t *testing.T) {
+	users, _, audits, userService := newTestUserService()
+	users.On("FindUserByUsername", "taken").Return(&model.User{Username: "taken"}, nil)
+	users.On("FindUserByUsername", "batch_job").Return((*model.User)(nil), gorm.ErrRecordNotFound)
+	users.On("FindUserByUsername", "racer").Return((*model.User)(nil), gorm.ErrRecordNotFound)
+	// Pembuatan bersamaan yang lolos pengecekan awal ditolak unique index
+	users.On("CreateUser", mock.MatchedBy(func(user *model.User) bool {
+		return user.Username == "racer"
+	})).Return(gorm.ErrDuplicatedKey)
+	users.On("CreateUser", mock.AnythingOfType("*model.User")).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.User).ID = 5
+	}).Return(nil)
+
+	_, err := userService.Create(model.CreateUserRequest{Username: "taken", Password: "LongPassword1"}, 1)
+	assert.ErrorIs(t, err, service.ErrUsernameTaken)
+
+	_, err = userService.Create(model.CreateUserRequest{Username: "racer", Password: "LongPassword1"}, 1)
+	assert.ErrorIs(t, err, service.ErrUsernameTaken)
+
+	_, err = userService.Create(model.CreateUserRequest{Username: "batch_job", Password: "LongPassword1", Role: "root"}, 1)
+	assert.ErrorIs(t, err, service.ErrInvalidRole)
+
+	_, err = userService.Create(model.CreateUserRequest{Username: "batch_job", Password: "LongPassword1", TenantID: "JKT/SBY"}, 1)
+	assert.ErrorIs(t, err, service.ErrInvalidTenant)
+
+	user, err := userService.Create(model.CreateUserRequest{Username: "batch_job", Password: "LongPassword1", TenantID: "JKT"}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.RoleAgent, user.Role)
+	assert.Equal(t, "JKT", user.TenantID)
+	assert.True(t, utils.CheckPasswordHash("LongPassword1", user.Password))
+
+	if assert.Len(t, audits.logs, 1) {
+		assert.Equal(t, model.AuditActionUserCreate, audits.logs[0].Action)
+		assert.Equal(t, uint(1), audits.logs[0].UserID)
+		assert.Equal(t, "5", audits.logs[0].ResultIDs)
+	}
+}
+
+func TestUserServiceBootstrap(t *testing.T) {
+	users, _, audits, userService := newTestUserService()
+	existing := &model.User{Model: gorm.Model{ID: 2}, Username: "legacy", Role: model.RoleAgent, Disabled: true, Password: "hash"}
+	users.On("FindUserByUsername", "legacy").Return(existing, nil)
+	users.On("FindUserByUsername", "root").Return((*model.User)(nil), gorm.ErrRecordNotFound)
+	users.On("UpdateUser", existing).Return(nil)
+	users.On("CreateUser", mock.AnythingOfType("*model.User")).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.User).ID = 5
+	}).Return(nil)
+
+	_, err := userService.Bootstrap("root", "LongPassword1", "JKT/SBY")
+	assert.ErrorIs(t, err, service.ErrInvalidTenant)
+
+	// Username baru dibuat sebagai admin dengan password policy biasa
+	_, err = userService.Bootstrap("root", "short", "")
+	var policyErr *service.PasswordPolicyError
+	assert.ErrorAs(t, err, &policyErr)
+
+	user, err := userService.Bootstrap("root", "LongPassword1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, model.RoleAdmin, user.Role)
+	assert.True(t, utils.CheckPasswordHash("LongPassword1", user.Password))
+
+	// User yang sudah ada dinaikkan dan diaktifkan, password tetap
+	user, err = userService.Bootstrap("legacy", "ignored", "JKT")
+	assert.NoError(t, err)
+	assert.Equal(t, model.RoleAdmin, user.Role)
+	assert.False(t, user.Disabled)
+	assert.Equal(t, "JKT", user.TenantID)
+	assert.Equal(t, "hash", user.Password)
+
+	if assert.Len(t, audits.logs, 2) {
+		assert.Equal(t, model.AuditActionUserCreate, audits.logs[0].Action)
+		assert.Equal(t, model.AuditActionUserRole, audits.logs[1].Action)
+		assert.Equal(t, uint(0), audits.logs[1].UserID)
+		assert.JSONEq(t, `{"user_id":"2","from":"agent","to":"admin"}`, audits.logs[1].Query)
+	}
+}
+
+func TestUserServiceDisableAndRole(t *testing.T) {
+	users, tokens, audits, userService := newTestUserService()
+	target := &model.User{Model: gorm.Model{ID: 2}, Username: "agent", Role: model.RoleAgent}
+	users.On("FindUserByID", uint(2)).Return(target, nil)
+	users.On("FindUserByID", uint(3)).Return((*model.User)(nil), gorm.ErrRecordNotFound)
+	users.On("UpdateUser", target).Return(nil)
+	users.On("UpdateRole", uint(2), model.RoleAdmin).Return(nil)
+	users.On("DeleteUser", uint(2)).Return(nil)
+	tokens.On("RevokeUser", uint(2)).Return(nil)
+
+	_, err := userService.SetDisabled(1, true, 1)
+	assert.ErrorIs(t, err, service.ErrSelfModify)
+
+	_, err = userService.SetDisabled(3, true, 1)
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+
+	user, err := userService.SetDisabled(2, true, 1)
+	assert.NoError(t, err)
+	assert.True(t, user.Disabled)
+	tokens.AssertCalled(t, "RevokeUser", uint(2))
+
+	user, err = userService.SetDisabled(2, false, 1)
+	assert.NoError(t, err)
+	assert.False(t, user.Disabled)
+
+	_, err = userService.AssignRole(2, "root", 1)
+	assert.ErrorIs(t, err, service.ErrInvalidRole)
+
+	tokens.Calls = nil
+	user, err = userService.AssignRole(2, model.RoleAdmin, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.RoleAdmin, user.Role)
+	users.AssertCalled(t, "UpdateRole", uint(2), model.RoleAdmin)
+	tokens.AssertCalled(t, "RevokeUser", uint(2))
+
+	_, err = userService.AssignTenant(2, "JKT SBY", 1)
+	assert.ErrorIs(t, err, service.ErrInvalidTenant)
+
+	user, err = userService.AssignTenant(2, "JKT", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "JKT", user.TenantID)
+
+	assert.NoError(t, userService.Delete(2, 1))
+	users.AssertCalled(t, "DeleteUser", uint(2))
+
+	var actions []string
+	for _, entry := range audits.logs {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{
+		model.AuditActionUserDisable,
+		model.AuditActionUserEnable,
+		model.AuditActionUserRole,
+		model.AuditActionUserTenant,
+		model.AuditActionUserDelete,
+	}, actions)
+	assert.JSONEq(t, `{"user_id":"2","from":"agent","to":"admin"}`, audits.logs[2].Query)
+}
+
+func TestAuthServiceLoginDisabled(t *testing.T) {
+	hashedPassword, err := utils.HashPassword("password123")
+	assert.NoError(t, err)
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindUserByUsername", "jane").Return(&model.User{Username: "jane", Password: hashedPassword, Disabled: true}, nil)
+	mockThrottle := new(MockLoginThrottle)
+	mockThrottle.On("Check", "jane", clientIP).Return(nil)
+
+	authService := service.NewAuthService(mockRepo, new(MockTokenService), mockThrottle, nil, service.PasswordPolicy{}, logging.Discard())
+	_, err = authService.Login(model.UserRequest{Username: "jane", Password: "password123"}, clientIP)
+	assert.ErrorIs(t, err, service.ErrUserDisabled)
+}
+
+func TestUserServiceSetLanguage(t *testing.T) {
+	users, _, audits, userService := newTestUserService()
+	user := &model.User{Model: gorm.Model{ID: 2}, Username: "agent"}
+	users.On("FindUserByID", uint(2)).Return(user, nil)
+	users.On("FindUserByID", uint(3)).Return((*model.User)(nil), gorm.ErrRecordNotFound)
+	users.On("UpdateUser", user).Return(nil)
+
+	_, err := userService.SetLanguage(2, "fr")
+	assert.ErrorIs(t, err, service.ErrInvalidLanguage)
+	users.AssertNotCalled(t, "FindUserByID", uint(2))
+
+	_, err = userService.SetLanguage(3, "id")
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+
+	updated, err := userService.SetLanguage(2, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "id", updated.Language)
+
+	updated, err = userService.SetLanguage(2, "")
+	assert.NoError(t, err)
+	assert.Empty(t, updated.Language, "an empty language falls back to Accept-Language")
+	assert.Empty(t, audits.logs)
+}
@@ -1,7 +0,0 @@
-package utils
-
-import "golang.org/x/crypto/bcrypt"
-
-func CheckPassword(hashedPassword, password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-}
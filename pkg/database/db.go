@@ -1,10 +1,12 @@
 package database
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/danisasmita/customer-search/internal/config"
 	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/tenant"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
@@ -22,27 +24,55 @@ func ConnectDB(cfg *config.Config) (*gorm.DB, error) {
 	var err error
 
 	if cfg.DBDriver == "sqlite" {
-		db, err = gorm.Open(sqlite.Open(cfg.DBSource), &gorm.Config{})
+		db, err = gorm.Open(sqlite.Open(cfg.DBSource), &gorm.Config{TranslateError: true})
 	} else {
 		dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
 			cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort, cfg.DBSSLMode)
-		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	}
 
 	return db, err
 }
 
+// Models adalah semua tabel yang dibuat AutoMigrate.
+var Models = []interface{}{
+	&model.Customer{},
+	&model.BankAccount{},
+	&model.Pocket{},
+	&model.TermDeposit{},
+	&model.User{},
+	&model.AuditLog{},
+	&model.RefreshToken{},
+	&model.TokenRevocation{},
+	&model.LoginAttempt{},
+	&model.MFARecoveryCode{},
+	&model.PasswordResetToken{},
+	&model.APIKey{},
+	&model.OIDCState{},
+	&model.ExternalIdentity{},
+}
+
+// Close menutup pool koneksi di bawah db. Dipanggil saat shutdown setelah
+// semua request selesai.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&model.Customer{},
-		&model.BankAccount{},
-		&model.Pocket{},
-		&model.TermDeposit{},
-		&model.User{},
-	)
+	return db.AutoMigrate(Models...)
 }
 
+// SeedTenants adalah cabang yang dipakai data contoh, dibagi bergantian.
+var SeedTenants = []string{"JKT", "SBY"}
+
 func SeedData(db *gorm.DB) error {
+	// Seeding bekerja lintas cabang
+	db = db.WithContext(tenant.Global(context.Background()))
+
 	var count int64
 	db.Model(&model.Customer{}).Count(&count)
 	if count > 0 {
@@ -194,5 +224,44 @@ func SeedData(db *gorm.DB) error {
 			},
 		},
 	}
+	for i := range customers {
+		assignTenant(&customers[i], SeedTenants[i%len(SeedTenants)])
+	}
 	return db.Create(&customers).Error
 }
+
+// BackfillTenant mengisi tenant_id yang masih kosong pada nasabah,
+// produknya, dan user dengan tenantID. Dipakai sekali saat upgrade dari
+// versi tanpa cabang, ketika semua data lama milik satu cabang. Hasilnya
+// adalah jumlah baris yang diubah per tabel.
+func BackfillTenant(db *gorm.DB, tenantID string) (map[string]int64, error) {
+	if !model.ValidTenant(tenantID) {
+		return nil, fmt.Errorf("invalid tenant %q", tenantID)
+	}
+
+	updated := map[string]int64{}
+	err := db.WithContext(tenant.Global(context.Background())).Transaction(func(tx *gorm.DB) error {
+		for _, m := range []interface{}{&model.Customer{}, &model.BankAccount{}, &model.Pocket{}, &model.TermDeposit{}, &model.User{}} {
+			result := tx.Model(m).Where("tenant_id = ? OR tenant_id IS NULL", "").Update("tenant_id", tenantID)
+			if result.Error != nil {
+				return result.Error
+			}
+			updated[result.Statement.Table] = result.RowsAffected
+		}
+		return nil
+	})
+	return updated, err
+}
+
+func assignTenant(customer *model.Customer, tenantID string) {
+	customer.TenantID = tenantID
+	for i := range customer.BankAccounts {
+		customer.BankAccounts[i].TenantID = tenantID
+	}
+	for i := range customer.Pockets {
+		customer.Pockets[i].TenantID = tenantID
+	}
+	for i := range customer.TermDeposits {
+		customer.TermDeposits[i].TenantID = tenantID
+	}
+}
@@ -1,10 +1,12 @@
 package database
 
 import (
+	"context"
 	"testing"
 
 	"github.com/danisasmita/customer-search/internal/config"
 	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/tenant"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -81,3 +83,62 @@ func TestSeedDataNoDuplicate(t *testing.T) {
 	db.Model(&model.Customer{}).Count(&count)
 	assert.Equal(t, int64(11), count) // Sesuaikan dengan jumlah data yang di-seed
 }
+
+func TestSeedDataTenants(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, tenant.Register(db))
+	assert.NoError(t, AutoMigrate(db))
+	assert.NoError(t, SeedData(db))
+
+	// Setiap cabang hanya melihat customer dan rekeningnya sendiri
+	var total int64
+	for _, tenantID := range SeedTenants {
+		ctx := tenant.WithScope(context.Background(), tenant.Scope{TenantID: tenantID})
+
+		var customers []model.Customer
+		assert.NoError(t, db.WithContext(ctx).Preload("BankAccounts").Find(&customers).Error)
+		assert.NotEmpty(t, customers)
+		for _, customer := range customers {
+			assert.Equal(t, tenantID, customer.TenantID)
+			assert.Len(t, customer.BankAccounts, 1)
+		}
+		total += int64(len(customers))
+	}
+	assert.Equal(t, int64(11), total)
+}
+
+func TestClose(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, Close(db))
+	assert.Error(t, Ping(context.Background(), db))
+}
+
+func TestBackfillTenant(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, tenant.Register(db))
+	assert.NoError(t, AutoMigrate(db))
+
+	ctx := tenant.Global(context.Background())
+	legacy := model.Customer{Name: "Legacy", BankAccounts: []model.BankAccount{{AccountNumber: "1"}}}
+	assert.NoError(t, db.WithContext(ctx).Create(&legacy).Error)
+	assert.NoError(t, db.WithContext(ctx).Create(&model.Customer{Name: "Surabaya", TenantID: "SBY"}).Error)
+	assert.NoError(t, db.Create(&model.User{Username: "agent"}).Error)
+
+	_, err = BackfillTenant(db, "JKT SBY")
+	assert.Error(t, err)
+
+	updated, err := BackfillTenant(db, "JKT")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), updated["customers"])
+	assert.Equal(t, int64(1), updated["bank_accounts"])
+	assert.Equal(t, int64(1), updated["users"])
+
+	var customers []model.Customer
+	assert.NoError(t, db.WithContext(ctx).Order("id").Find(&customers).Error)
+	assert.Equal(t, "JKT", customers[0].TenantID)
+	assert.Equal(t, "SBY", customers[1].TenantID, "customers with a branch keep it")
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/danisasmita/customer-search/pkg/tenant"
+	"gorm.io/gorm"
+)
+
+// Ping memeriksa koneksi ke database dalam batas waktu ctx.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// PendingMigrations mengembalikan tabel, kolom, dan index dari Models yang
+// belum ada di database, misalnya karena versi baru belum dijalankan
+// dengan -migrate.
+func PendingMigrations(ctx context.Context, db *gorm.DB) ([]string, error) {
+	db = db.WithContext(tenant.Global(ctx))
+	migrator := db.Migrator()
+
+	var pending []string
+	for _, m := range Models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return nil, err
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(m) {
+			pending = append(pending, table)
+			continue
+		}
+
+		columns, err := migrator.ColumnTypes(m)
+		if err != nil {
+			return nil, err
+		}
+		existing := make(map[string]bool, len(columns))
+		for _, column := range columns {
+			existing[column.Name()] = true
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !existing[field.DBName] {
+				pending = append(pending, table+"."+field.DBName)
+			}
+		}
+
+		var indexes []string
+		for name := range stmt.Schema.ParseIndexes() {
+			if !migrator.HasIndex(m, name) {
+				indexes = append(indexes, table+"."+name)
+			}
+		}
+		slices.Sort(indexes)
+		pending = append(pending, indexes...)
+	}
+	return pending, nil
+}
+
+// MigrationCheck membuat pemeriksaan readiness untuk PendingMigrations.
+// Skema tidak mundur setelah migrasi, jadi hasil lengkap diingat dan
+// database tidak diperiksa ulang.
+func MigrationCheck(db *gorm.DB) func(ctx context.Context) error {
+	var migrated atomic.Bool
+	return func(ctx context.Context) error {
+		if migrated.Load() {
+			return nil
+		}
+		pending, err := PendingMigrations(ctx, db)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+		}
+		migrated.Store(true)
+		return nil
+	}
+}
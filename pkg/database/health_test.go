@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestPing(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	assert.NoError(t, Ping(context.Background(), db))
+
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
+	assert.Error(t, Ping(context.Background(), db))
+}
+
+func TestMigrationCheck(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, tenant.Register(db))
+	check := MigrationCheck(db)
+
+	err = check(context.Background())
+	assert.ErrorContains(t, err, "customers")
+
+	// Kolom baru yang belum dimigrasi juga terdeteksi.
+	require.NoError(t, AutoMigrate(db))
+	require.NoError(t, db.Migrator().DropColumn(&model.User{}, "language"))
+	err = check(context.Background())
+	assert.ErrorContains(t, err, "users.language")
+
+	// Begitu juga index baru
+	require.NoError(t, AutoMigrate(db))
+	require.NoError(t, db.Migrator().DropIndex(&model.User{}, "idx_users_username"))
+	err = check(context.Background())
+	assert.ErrorContains(t, err, "users.idx_users_username")
+
+	require.NoError(t, AutoMigrate(db))
+	assert.NoError(t, check(context.Background()))
+}
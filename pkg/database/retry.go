@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RetryPolicy mengatur percobaan koneksi saat start. Jeda dimulai dari
+// BackoffBase dan berlipat dua sampai BackoffMax.
+type RetryPolicy struct {
+	Attempts    int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+// OpenWithRetry memanggil open sampai berhasil, Attempts habis, atau ctx
+// selesai. Dipakai saat start agar server menunggu database yang baru
+// dinyalakan, bukan langsung keluar.
+func OpenWithRetry(ctx context.Context, policy RetryPolicy, open func() (*gorm.DB, error)) (*gorm.DB, error) {
+	backoff := policy.BackoffBase
+	for attempt := 1; ; attempt++ {
+		db, err := open()
+		if err == nil {
+			return db, nil
+		}
+		if attempt >= policy.Attempts {
+			return nil, fmt.Errorf("database unavailable after %d attempts: %w", attempt, err)
+		}
+
+		slog.WarnContext(ctx, "database not ready", "attempt", attempt, "attempts", policy.Attempts, "retry_in", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if policy.BackoffMax > 0 {
+			backoff = min(backoff, policy.BackoffMax)
+		}
+	}
+}
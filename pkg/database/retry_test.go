@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestOpenWithRetry(t *testing.T) {
+	policy := RetryPolicy{Attempts: 3, BackoffBase: time.Millisecond, BackoffMax: 2 * time.Millisecond}
+	errRefused := errors.New("connection refused")
+
+	t.Run("succeeds after failures", func(t *testing.T) {
+		calls := 0
+		db, err := OpenWithRetry(context.Background(), policy, func() (*gorm.DB, error) {
+			calls++
+			if calls < 3 {
+				return nil, errRefused
+			}
+			return &gorm.DB{}, nil
+		})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, db)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("gives up after attempts", func(t *testing.T) {
+		calls := 0
+		_, err := OpenWithRetry(context.Background(), policy, func() (*gorm.DB, error) {
+			calls++
+			return nil, errRefused
+		})
+
+		assert.ErrorIs(t, err, errRefused)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("stops when context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := OpenWithRetry(ctx, RetryPolicy{Attempts: 10, BackoffBase: time.Hour}, func() (*gorm.DB, error) {
+			return nil, errRefused
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
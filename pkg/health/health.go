@@ -0,0 +1,84 @@
+// Package health menjalankan pemeriksaan readiness, misalnya koneksi
+// database, dengan batas waktu per pemeriksaan.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Check mengembalikan error jika dependency belum siap. Check harus
+// berhenti saat ctx selesai.
+type Check func(ctx context.Context) error
+
+type Checker struct {
+	timeout time.Duration
+	names   []string
+	checks  map[string]Check
+}
+
+// Report berisi status keseluruhan dan hasil tiap pemeriksaan: "ok" atau
+// pesan error.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout, checks: map[string]Check{}}
+}
+
+func (c *Checker) Add(name string, check Check) {
+	if _, exists := c.checks[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Run menjalankan semua pemeriksaan bersamaan. Pemeriksaan yang melewati
+// timeout dilaporkan gagal walaupun belum selesai.
+func (c *Checker) Run(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	results := make([]string, len(c.names))
+	var wg sync.WaitGroup
+	for i, name := range c.names {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}(i, c.checks[name])
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]string, len(c.names))}
+	for i, name := range c.names {
+		report.Checks[name] = results[i]
+		if results[i] != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+	return report
+}
+
+func run(ctx context.Context, check Check) string {
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return err.Error()
+		}
+		return StatusOK
+	case <-ctx.Done():
+		return ctx.Err().Error()
+	}
+}
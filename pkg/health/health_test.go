@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckerRun(t *testing.T) {
+	t.Run("all ok", func(t *testing.T) {
+		checker := NewChecker(time.Second)
+		checker.Add("database", func(ctx context.Context) error { return nil })
+		checker.Add("signing_key", func(ctx context.Context) error { return nil })
+
+		report := checker.Run(context.Background())
+
+		assert.Equal(t, Report{
+			Status: StatusOK,
+			Checks: map[string]string{"database": "ok", "signing_key": "ok"},
+		}, report)
+	})
+
+	t.Run("failed check", func(t *testing.T) {
+		checker := NewChecker(time.Second)
+		checker.Add("database", func(ctx context.Context) error { return nil })
+		checker.Add("migrations", func(ctx context.Context) error { return errors.New("pending migrations: users") })
+
+		report := checker.Run(context.Background())
+
+		assert.Equal(t, StatusUnavailable, report.Status)
+		assert.Equal(t, "ok", report.Checks["database"])
+		assert.Equal(t, "pending migrations: users", report.Checks["migrations"])
+	})
+
+	t.Run("check ignoring timeout", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+
+		checker := NewChecker(10 * time.Millisecond)
+		checker.Add("database", func(ctx context.Context) error {
+			<-block
+			return nil
+		})
+
+		report := checker.Run(context.Background())
+
+		assert.Equal(t, StatusUnavailable, report.Status)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"])
+	})
+}
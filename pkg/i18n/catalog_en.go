@@ -0,0 +1,87 @@
+package i18n
+
+import "github.com/danisasmita/customer-search/pkg/message"
+
+// en memakai teks di pkg/message agar pesan English tetap satu sumber.
+var en = map[string]string{
+	message.CodeBadRequest:       message.BadRequest,
+	message.CodeValidationFailed: message.ValidationFailed,
+	message.CodeRequired:         "{field} is required",
+	message.CodeInvalidField:     "{field} is invalid",
+	message.CodeUnauthorized:     message.Unauthorized,
+	message.CodeForbidden:        message.Forbidden,
+	message.CodeInternal:         message.InternalServerError,
+
+	message.CodeUserNotFound:       message.UserNotFound,
+	message.CodeUsernameTaken:      message.UsernameTaken,
+	message.CodeInvalidRole:        message.InvalidRole,
+	message.CodeInvalidLanguage:    message.InvalidLanguage,
+	message.CodeCannotModifySelf:   message.CannotModifySelf,
+	message.CodeAccountDisabled:    message.AccountDisabled,
+	message.CodeRegistrationClosed: message.RegistrationClosed,
+	message.CodeInvalidUserFilter:  message.InvalidUserFilter,
+	message.CodeInvalidCredentials: message.InvalidCredentials,
+	message.CodeInvalidTenant:      message.InvalidTenant,
+	message.CodeNoTenant:           message.NoTenant,
+
+	message.CodeInvalidRefreshToken:  message.InvalidRefreshToken,
+	message.CodeTooManyLoginAttempts: message.TooManyLoginAttempts,
+
+	message.CodeMFAAlreadyEnabled:     message.MFAAlreadyEnabled,
+	message.CodeMFANotEnrolled:        message.MFANotEnrolled,
+	message.CodeMFAEnrollmentRequired: message.MFAEnrollmentRequired,
+	message.CodeInvalidMFACode:        message.InvalidMFACode,
+	message.CodeInvalidMFAChallenge:   message.InvalidMFAChallenge,
+
+	message.CodeInvalidOIDCState: message.InvalidOIDCState,
+	message.CodeOIDCLoginFailed:  message.OIDCLoginFailed,
+	message.CodeNoMappedRole:     message.NoMappedRole,
+
+	message.CodeAPIKeyNotFound:    message.APIKeyNotFound,
+	message.CodeInvalidScope:      message.InvalidScope,
+	message.CodeInvalidExpiry:     message.InvalidExpiry,
+	message.CodeInsufficientScope: message.InsufficientScope,
+
+	message.CodeCannotImpersonate:       message.CannotImpersonate,
+	message.CodeImpersonationNotAllowed: message.ImpersonationNotAllowed,
+
+	message.CodeCustomerNotFound:    message.CustomerNotFound,
+	message.CodeInvalidCustomerID:   message.InvalidCustomerID,
+	message.CodeSearchCriteria:      message.SearchCustomer,
+	message.CodeAccessReasonMissing: message.AccessReasonRequired,
+	message.CodeInvalidAuditFilter:  message.InvalidAuditFilter,
+
+	message.CodePasswordPolicy:         message.PasswordPolicyViolation,
+	message.CodeInvalidCurrentPassword: message.InvalidCurrentPassword,
+	message.CodeInvalidResetToken:      message.InvalidResetToken,
+
+	// Tag validasi request, dipakai sebagai kode di daftar errors.
+	"email":          "{field} must be a valid email address",
+	"min":            "{field} must have a length of at least {param}",
+	"max":            "{field} must have a length of at most {param}",
+	"role":           "{field} must be one of: {values}",
+	"tenant":         "{field} must be a valid branch code",
+	"language":       "{field} must be one of: {values}",
+	"scope":          "{field} must be one of: {values}",
+	"account_number": "{field} must be 10 to 16 digits",
+
+	// Aturan password policy, dipakai sebagai kode di daftar errors.
+	"min_length":        "password must be at least {min} characters",
+	"uppercase":         message.PasswordNeedsUppercase,
+	"lowercase":         message.PasswordNeedsLowercase,
+	"digit":             message.PasswordNeedsDigit,
+	"symbol":            message.PasswordNeedsSymbol,
+	"contains_username": message.PasswordContainsUsername,
+	"common_password":   message.PasswordTooCommon,
+
+	message.KeyUserRegistered:      message.UserRegistered,
+	message.KeyUserDeleted:         message.UserDeleted,
+	message.KeyLoggedOut:           message.LoggedOut,
+	message.KeySessionsRevoked:     message.SessionsRevoked,
+	message.KeyAccountUnlocked:     message.AccountUnlocked,
+	message.KeyMFADisabled:         message.MFADisabled,
+	message.KeyAPIKeyRevoked:       message.APIKeyRevoked,
+	message.KeyPasswordChanged:     message.PasswordChanged,
+	message.KeyPasswordResetSent:   message.PasswordResetSent,
+	message.KeyPasswordResetIssued: message.PasswordResetIssued,
+}
@@ -0,0 +1,84 @@
+package i18n
+
+import "github.com/danisasmita/customer-search/pkg/message"
+
+var id = map[string]string{
+	message.CodeBadRequest:       "permintaan tidak valid",
+	message.CodeValidationFailed: "validasi permintaan gagal",
+	message.CodeRequired:         "{field} wajib diisi",
+	message.CodeInvalidField:     "{field} tidak valid",
+	message.CodeUnauthorized:     "tidak terautentikasi",
+	message.CodeForbidden:        "akses ditolak",
+	message.CodeInternal:         "terjadi kesalahan pada server",
+
+	message.CodeUserNotFound:       "user tidak ditemukan",
+	message.CodeUsernameTaken:      "username sudah dipakai",
+	message.CodeInvalidRole:        "role tidak valid",
+	message.CodeInvalidLanguage:    "bahasa tidak didukung",
+	message.CodeCannotModifySelf:   "admin tidak dapat mengubah akunnya sendiri di sini",
+	message.CodeAccountDisabled:    "akun dinonaktifkan",
+	message.CodeRegistrationClosed: "pendaftaran ditutup",
+	message.CodeInvalidUserFilter:  "filter user tidak valid",
+	message.CodeInvalidCredentials: "username atau password salah",
+	message.CodeInvalidTenant:      "cabang tidak valid",
+	message.CodeNoTenant:           "akun Anda belum ditempatkan di cabang mana pun",
+
+	message.CodeInvalidRefreshToken:  "refresh token tidak valid",
+	message.CodeTooManyLoginAttempts: "terlalu banyak percobaan login, silakan coba lagi nanti",
+
+	message.CodeMFAAlreadyEnabled:     "MFA sudah aktif",
+	message.CodeMFANotEnrolled:        "pendaftaran MFA belum dimulai",
+	message.CodeMFAEnrollmentRequired: "role Anda wajib mendaftarkan MFA",
+	message.CodeInvalidMFACode:        "kode MFA salah",
+	message.CodeInvalidMFAChallenge:   "tantangan MFA tidak valid atau sudah kedaluwarsa",
+
+	message.CodeInvalidOIDCState: "sesi login tidak valid atau sudah kedaluwarsa",
+	message.CodeOIDCLoginFailed:  "login single sign-on gagal",
+	message.CodeNoMappedRole:     "group di identity provider Anda tidak memberikan akses",
+
+	message.CodeAPIKeyNotFound:    "API key tidak ditemukan",
+	message.CodeInvalidScope:      "scope API key tidak valid",
+	message.CodeInvalidExpiry:     "masa berlaku API key tidak valid",
+	message.CodeInsufficientScope: "API key tidak memiliki scope yang dibutuhkan",
+
+	message.CodeCannotImpersonate:       "Anda tidak dapat melakukan impersonation terhadap user ini",
+	message.CodeImpersonationNotAllowed: "tidak diizinkan selama impersonation",
+
+	message.CodeCustomerNotFound:    "nasabah tidak ditemukan",
+	message.CodeInvalidCustomerID:   "ID nasabah tidak valid",
+	message.CodeSearchCriteria:      "Isi minimal name, email, atau account_number untuk pencarian",
+	message.CodeAccessReasonMissing: "alasan akses yang valid wajib diisi",
+	message.CodeInvalidAuditFilter:  "filter audit tidak valid",
+
+	message.CodePasswordPolicy:         "password tidak memenuhi kebijakan password",
+	message.CodeInvalidCurrentPassword: "password saat ini salah",
+	message.CodeInvalidResetToken:      "token reset tidak valid atau sudah kedaluwarsa",
+
+	"email":          "{field} harus berupa alamat email yang valid",
+	"min":            "panjang {field} minimal {param}",
+	"max":            "panjang {field} maksimal {param}",
+	"role":           "{field} harus salah satu dari: {values}",
+	"tenant":         "{field} harus berupa kode cabang yang valid",
+	"language":       "{field} harus salah satu dari: {values}",
+	"scope":          "{field} harus salah satu dari: {values}",
+	"account_number": "{field} harus terdiri dari 10 sampai 16 digit",
+
+	"min_length":        "password minimal {min} karakter",
+	"uppercase":         "password harus mengandung huruf besar",
+	"lowercase":         "password harus mengandung huruf kecil",
+	"digit":             "password harus mengandung angka",
+	"symbol":            "password harus mengandung simbol",
+	"contains_username": "password tidak boleh mengandung username",
+	"common_password":   "password terlalu umum",
+
+	message.KeyUserRegistered:      "user berhasil didaftarkan",
+	message.KeyUserDeleted:         "user dihapus",
+	message.KeyLoggedOut:           "berhasil logout",
+	message.KeySessionsRevoked:     "semua sesi telah dicabut",
+	message.KeyAccountUnlocked:     "akun dibuka kembali",
+	message.KeyMFADisabled:         "MFA dinonaktifkan",
+	message.KeyAPIKeyRevoked:       "API key dicabut",
+	message.KeyPasswordChanged:     "password berhasil diubah",
+	message.KeyPasswordResetSent:   "jika akun terdaftar, token reset password sudah dikirim",
+	message.KeyPasswordResetIssued: "token reset password sudah dikirim ke user",
+}
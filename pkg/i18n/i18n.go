@@ -0,0 +1,103 @@
+// Package i18n menerjemahkan pesan API. Key pesan error sama dengan kode
+// error di pkg/message, jadi klien tetap mencocokkan kode dan hanya teksnya
+// yang mengikuti bahasa user.
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	English    = "en"
+	Indonesian = "id"
+)
+
+// ContextKey adalah key gin.Context tempat middleware menyimpan bahasa
+// yang dipakai untuk request.
+const ContextKey = "language"
+
+// Languages berisi bahasa yang punya katalog.
+var Languages = []string{English, Indonesian}
+
+var catalogs = map[string]map[string]string{
+	English:    en,
+	Indonesian: id,
+}
+
+// Params mengisi placeholder {nama} di teks pesan.
+type Params map[string]interface{}
+
+func Supported(lang string) bool {
+	return slices.Contains(Languages, lang)
+}
+
+// Lookup mencari key di katalog lang lalu di katalog English.
+func Lookup(lang, key string) (string, bool) {
+	if text, ok := catalogs[lang][key]; ok {
+		return text, true
+	}
+	text, ok := en[key]
+	return text, ok
+}
+
+// T menerjemahkan key dan mengisi params. Key yang tidak ada di katalog
+// dikembalikan apa adanya.
+func T(lang, key string, params Params) string {
+	text, ok := Lookup(lang, key)
+	if !ok {
+		text = key
+	}
+	return Format(text, params)
+}
+
+func Format(text string, params Params) string {
+	if len(params) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Negotiate memilih bahasa yang didukung dari header Accept-Language
+// berdasarkan nilai q, misalnya "id-ID,id;q=0.9,en;q=0.8" menjadi "id".
+// fallback dipakai jika tidak ada bahasa yang cocok.
+func Negotiate(header, fallback string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !Supported(lang) {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return fallback
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
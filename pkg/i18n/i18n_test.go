@@ -0,0 +1,53 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogsComplete(t *testing.T) {
+	for _, lang := range Languages {
+		for key := range en {
+			_, ok := catalogs[lang][key]
+			assert.True(t, ok, "%s catalog is missing %q", lang, key)
+		}
+		for key := range catalogs[lang] {
+			_, ok := en[key]
+			assert.True(t, ok, "%s catalog has unknown key %q", lang, key)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, message.UserNotFound, T(English, message.CodeUserNotFound, nil))
+	assert.Equal(t, "user tidak ditemukan", T(Indonesian, message.CodeUserNotFound, nil))
+	assert.Equal(t, "password minimal 10 karakter", T(Indonesian, "min_length", Params{"min": 10}))
+	assert.Equal(t, "username is required", T("", message.CodeRequired, Params{"field": "username"}))
+	assert.Equal(t, message.UserNotFound, T("fr", message.CodeUserNotFound, nil))
+	assert.Equal(t, "unknown_key", T(Indonesian, "unknown_key", nil))
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "Empty", header: "", expected: English},
+		{name: "Region Subtag", header: "id-ID", expected: Indonesian},
+		{name: "Quality Order", header: "en;q=0.5, id;q=0.9", expected: Indonesian},
+		{name: "First Wins Tie", header: "en-US,id", expected: English},
+		{name: "Unsupported Skipped", header: "fr-FR,de;q=0.9,id;q=0.1", expected: Indonesian},
+		{name: "Zero Quality", header: "id;q=0,en;q=0.2", expected: English},
+		{name: "Only Unsupported", header: "fr", expected: English},
+		{name: "Bad Quality", header: "id;q=abc", expected: English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Negotiate(tt.header, English))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type person struct {
+	ID    uint
+	Email string `gorm:"uniqueIndex"`
+}
+
+func TestGORMLogger(t *testing.T) {
+	logger, buf := capture(t)
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: NewGORMLogger(logger, 0)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&person{}))
+	require.NoError(t, db.Create(&person{Email: "jane@example.com"}).Error)
+	assert.Empty(t, buf.String())
+
+	// Record tidak ditemukan bukan error yang perlu dicatat.
+	err = db.Where("email = ?", "missing@example.com").First(&person{}).Error
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	assert.Empty(t, buf.String())
+
+	err = db.Create(&person{Email: "jane@example.com"}).Error
+	require.Error(t, err)
+
+	line := decode(t, buf)
+	assert.Equal(t, "query failed", line["msg"])
+	assert.Contains(t, line["sql"], "INSERT INTO `people`")
+	assert.NotContains(t, buf.String(), "jane@example.com")
+}
+
+func TestGORMLoggerSlowQuery(t *testing.T) {
+	logger, buf := capture(t)
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: NewGORMLogger(logger, time.Nanosecond)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&person{}))
+	buf.Reset()
+
+	require.NoError(t, db.Where("email = ?", "jane@example.com").Find(&[]person{}).Error)
+
+	line := decode(t, buf)
+	assert.Equal(t, "slow query", line["msg"])
+	assert.Contains(t, line["sql"], "WHERE email = ?")
+	assert.NotContains(t, buf.String(), "jane@example.com")
+}
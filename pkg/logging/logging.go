@@ -0,0 +1,81 @@
+// Package logging membuat logger log/slog yang dipakai seluruh aplikasi.
+// Setiap record melewati redaksi sehingga email, nomor rekening, password,
+// dan token tidak tertulis di log, lalu diberi request_id dan trace_id
+// dari context.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Config memilih level dan format log. Level kosong berarti info dan
+// Format kosong berarti JSON.
+type Config struct {
+	Level  string
+	Format string
+}
+
+// New membuat logger yang menulis ke w.
+func New(w io.Writer, cfg Config) (*slog.Logger, error) {
+	level := slog.LevelInfo
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, fmt.Errorf("unsupported log level %q", cfg.Level)
+		}
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	case FormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unsupported log format %q", cfg.Format)
+	}
+	return slog.New(NewContextHandler(NewRedactHandler(handler))), nil
+}
+
+// Discard membuang semua log. Untuk test.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+type (
+	loggerKey    struct{}
+	requestIDKey struct{}
+)
+
+// WithLogger menyimpan logger untuk request di ctx.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext mengembalikan logger dari WithLogger, atau slog.Default jika
+// ctx tidak punya logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// WithRequestID menyimpan ID request agar ikut di setiap log dengan ctx.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
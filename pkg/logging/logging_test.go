@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, Config{Level: "WARN", Format: FormatText})
+	require.NoError(t, err)
+
+	logger.Info("hidden")
+	logger.Warn("shown", "attempt", 2)
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "level=WARN msg=shown attempt=2")
+
+	_, err = New(&buf, Config{Level: "verbose"})
+	assert.Error(t, err)
+	_, err = New(&buf, Config{Format: "xml"})
+	assert.Error(t, err)
+}
+
+func TestContextHandler(t *testing.T) {
+	logger, buf := capture(t)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(ctx, "test")
+	defer span.End()
+	logger.InfoContext(ctx, "with context")
+
+	line := decode(t, buf)
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), line["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), line["span_id"])
+
+	buf.Reset()
+	logger.Info("without context")
+	line = decode(t, buf)
+	assert.NotContains(t, line, "request_id")
+	assert.NotContains(t, line, "trace_id")
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+
+	logger := Discard()
+	assert.Same(t, logger, FromContext(WithLogger(context.Background(), logger)))
+}
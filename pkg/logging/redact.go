@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted menggantikan nilai yang disembunyikan.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys disembunyikan seluruhnya, apa pun nilainya. Key
+// dibandingkan dalam huruf kecil dengan "-" diganti "_".
+var sensitiveKeys = map[string]bool{
+	"email":          true,
+	"account_number": true,
+	"authorization":  true,
+	"cookie":         true,
+	"set_cookie":     true,
+	"api_key":        true,
+	"x_api_key":      true,
+	"dsn":            true,
+	"otp":            true,
+	"mfa_code":       true,
+	"recovery_code":  true,
+}
+
+// sensitiveKeyParts menyembunyikan key yang mengandung salah satu kata ini,
+// misalnya new_password atau refresh_token.
+var sensitiveKeyParts = []string{"password", "token", "secret"}
+
+// valuePatterns mencari data sensitif di dalam teks bebas seperti pesan
+// error. Urutannya penting: JWT dan API key diganti sebelum pola email
+// dan angka.
+var valuePatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), Redacted},
+	{regexp.MustCompile(`(?i)\b(bearer|apikey)\s+\S+`), "$1 " + Redacted},
+	{regexp.MustCompile(`(?i)\b(password|secret|token)=\S+`), "$1=" + Redacted},
+	{regexp.MustCompile(`\bcsk_[A-Za-z0-9_-]+`), Redacted},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), Redacted},
+	// Nomor rekening dan nomor kartu: enam digit atau lebih berturut-turut.
+	{regexp.MustCompile(`\b\d{6,}\b`), Redacted},
+}
+
+type redactHandler struct {
+	next slog.Handler
+}
+
+// NewRedactHandler menyembunyikan attribute dengan key sensitif dan
+// mengganti email, nomor rekening, JWT, dan API key di pesan serta nilai
+// teks sebelum record diteruskan ke next.
+func NewRedactHandler(next slog.Handler) slog.Handler {
+	return redactHandler{next: next}
+}
+
+func (h redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, RedactString(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = redactAttr(attr)
+	}
+	return redactHandler{next: h.next.WithAttrs(redacted)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{next: h.next.WithGroup(name)}
+}
+
+// RedactString mengganti data sensitif di s dengan Redacted.
+func RedactString(s string) string {
+	for _, p := range valuePatterns {
+		s = p.pattern.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+func redactAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if sensitiveKey(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactString(attr.Value.String()))
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, member := range group {
+			redacted[i] = redactAttr(member)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindAny:
+		// Nilai lain, misalnya error atau struct, ditulis sebagai teks agar
+		// bisa diperiksa pola yang sama.
+		value := attr.Value.Any()
+		if err, ok := value.(error); ok {
+			return slog.String(attr.Key, RedactString(err.Error()))
+		}
+		return slog.String(attr.Key, RedactString(fmt.Sprint(value)))
+	default:
+		return attr
+	}
+}
+
+func sensitiveKey(key string) bool {
+	key = strings.ReplaceAll(strings.ToLower(key), "-", "_")
+	if sensitiveKeys[key] {
+		return true
+	}
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package message
+
+// Kode error stabil yang dikirim di field code respons problem+json.
+// Klien mencocokkan kode ini, bukan teks pesan, jadi nilainya tidak boleh
+// diubah setelah dirilis.
+const (
+	CodeBadRequest       = "bad_request"
+	CodeValidationFailed = "validation_failed"
+	CodeRequired         = "required"
+	CodeInvalidField     = "invalid"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeInternal         = "internal_error"
+
+	CodeUserNotFound       = "user_not_found"
+	CodeUsernameTaken      = "username_taken"
+	CodeInvalidRole        = "invalid_role"
+	CodeCannotModifySelf   = "cannot_modify_self"
+	CodeAccountDisabled    = "account_disabled"
+	CodeRegistrationClosed = "registration_closed"
+	CodeInvalidUserFilter  = "invalid_user_filter"
+	CodeInvalidCredentials = "invalid_credentials"
+	CodeInvalidTenant      = "invalid_tenant"
+	CodeNoTenant           = "no_tenant"
+	CodeInvalidLanguage    = "invalid_language"
+
+	CodeInvalidRefreshToken  = "invalid_refresh_token"
+	CodeTooManyLoginAttempts = "too_many_login_attempts"
+
+	CodeMFAAlreadyEnabled     = "mfa_already_enabled"
+	CodeMFANotEnrolled        = "mfa_not_enrolled"
+	CodeMFAEnrollmentRequired = "mfa_enrollment_required"
+	CodeInvalidMFACode        = "invalid_mfa_code"
+	CodeInvalidMFAChallenge   = "invalid_mfa_challenge"
+
+	CodeInvalidOIDCState = "invalid_oidc_state"
+	CodeOIDCLoginFailed  = "oidc_login_failed"
+	CodeNoMappedRole     = "no_mapped_role"
+
+	CodeAPIKeyNotFound    = "api_key_not_found"
+	CodeInvalidScope      = "invalid_scope"
+	CodeInvalidExpiry     = "invalid_expiry"
+	CodeInsufficientScope = "insufficient_scope"
+
+	CodeCannotImpersonate       = "cannot_impersonate"
+	CodeImpersonationNotAllowed = "impersonation_not_allowed"
+
+	CodeCustomerNotFound    = "customer_not_found"
+	CodeInvalidCustomerID   = "invalid_customer_id"
+	CodeSearchCriteria      = "search_criteria_required"
+	CodeAccessReasonMissing = "access_reason_required"
+	CodeInvalidAuditFilter  = "invalid_audit_filter"
+
+	CodePasswordPolicy         = "password_policy"
+	CodeInvalidCurrentPassword = "invalid_current_password"
+	CodeInvalidResetToken      = "invalid_reset_token"
+)
+
+// Key pesan sukses di katalog i18n.
+const (
+	KeyUserRegistered      = "user_registered"
+	KeyUserDeleted         = "user_deleted"
+	KeyLoggedOut           = "logged_out"
+	KeySessionsRevoked     = "sessions_revoked"
+	KeyAccountUnlocked     = "account_unlocked"
+	KeyMFADisabled         = "mfa_disabled"
+	KeyAPIKeyRevoked       = "api_key_revoked"
+	KeyPasswordChanged     = "password_changed"
+	KeyPasswordResetSent   = "password_reset_sent"
+	KeyPasswordResetIssued = "password_reset_issued"
+)
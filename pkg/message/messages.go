@@ -4,18 +4,83 @@ const (
 	Success             = "success"
 	InternalServerError = "internal server error"
 	BadRequest          = "bad request"
+	ValidationFailed    = "request validation failed"
 	Unauthorized        = "unauthorized"
+	Forbidden           = "forbidden"
 
 	UserRegistered     = "user registered successfully"
+	UserDeleted        = "user deleted"
+	UsernameTaken      = "username is already taken"
+	InvalidRole        = "invalid role"
+	InvalidLanguage    = "unsupported language"
+	CannotModifySelf   = "admins cannot change their own account here"
+	AccountDisabled    = "account is disabled"
+	RegistrationClosed = "registration is disabled"
+	InvalidUserFilter  = "invalid user filter"
 	UserNotFound       = "user not found"
 	InvalidCredentials = "invalid credentials"
 
+	InvalidRefreshToken  = "invalid refresh token"
+	LoggedOut            = "logged out successfully"
+	SessionsRevoked      = "all sessions revoked"
+	TooManyLoginAttempts = "too many login attempts, please try again later"
+	AccountUnlocked      = "account unlocked"
+
+	MFAAlreadyEnabled     = "mfa is already enabled"
+	MFANotEnrolled        = "mfa enrollment has not been started"
+	MFAEnrollmentRequired = "mfa enrollment is required for your role"
+	MFADisabled           = "mfa disabled"
+	InvalidMFACode        = "invalid mfa code"
+	InvalidMFAChallenge   = "invalid or expired mfa challenge"
+
+	InvalidOIDCState = "invalid or expired login state"
+	OIDCLoginFailed  = "single sign-on login failed"
+	NoMappedRole     = "your identity provider groups do not grant access"
+
+	APIKeyNotFound     = "api key not found"
+	APIKeyRevoked      = "api key revoked"
+	APIKeyNameRequired = "api key name is required"
+	InvalidScope       = "invalid api key scope"
+	InvalidExpiry      = "invalid api key expiry"
+	InsufficientScope  = "api key does not have the required scope"
+
+	CannotImpersonate           = "you cannot impersonate this user"
+	ImpersonationReasonRequired = "reason is required"
+	ImpersonationNotAllowed     = "not allowed while impersonating"
+
 	CustomerNotFound = "customer not found"
+	NoTenant         = "your account is not assigned to a branch"
+	InvalidTenant    = "invalid branch"
 
 	NameRequired     = "name is required"
 	EmailRequired    = "email is required"
 	UsernameRequired = "username is required"
 	PasswordRequired = "password is required"
 
-	SearchCustomer = "Please provide at least name, email, or account_number for the search"
+	PasswordPolicyViolation  = "password does not meet the password policy"
+	PasswordTooShort         = "password must be at least %d characters"
+	PasswordNeedsUppercase   = "password must contain an uppercase letter"
+	PasswordNeedsLowercase   = "password must contain a lowercase letter"
+	PasswordNeedsDigit       = "password must contain a digit"
+	PasswordNeedsSymbol      = "password must contain a symbol"
+	PasswordContainsUsername = "password must not contain the username"
+	PasswordTooCommon        = "password is too common"
+
+	InvalidCurrentPassword = "current password is incorrect"
+	InvalidResetToken      = "invalid or expired reset token"
+	PasswordChanged        = "password changed successfully"
+	PasswordResetSent      = "if the account exists, a password reset token has been sent"
+	PasswordResetIssued    = "password reset token sent to user"
+	PasswordResetSubject   = "Password reset"
+	PasswordResetBody      = "Use this token to reset your password: %s\nThe token expires at %s and can only be used once."
+
+	SearchCustomer    = "Please provide at least name, email, or account_number for the search"
+	InvalidCustomerID = "invalid customer id"
+
+	AccessReasonRequired = "a valid access reason is required"
+
+	InvalidAuditFilter    = "invalid audit filter"
+	AuditSequenceGap      = "audit sequence gap"
+	AuditPrevHashMismatch = "previous hash mismatch"
+	AuditHashMismatch     = "hash mismatch"
 )
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func TestGORMPlugin(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(GORMPlugin{}))
+	require.NoError(t, db.AutoMigrate(&widget{}))
+
+	require.NoError(t, db.Create(&widget{Name: "a"}).Error)
+	var found []widget
+	require.NoError(t, db.Find(&found).Error)
+	require.NoError(t, db.Model(&widget{}).Where("name = ?", "a").Update("name", "b").Error)
+	require.NoError(t, db.Where("name = ?", "b").Delete(&widget{}).Error)
+
+	w := httptest.NewRecorder()
+	Handler(Default, "").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, operation := range []string{"create", "query", "update", "delete"} {
+		assert.Contains(t, w.Body.String(), `db_query_duration_seconds_count{operation="`+operation+`"}`)
+	}
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	r := prometheus.NewRegistry()
+	RegisterDBStats(r, sqlDB, "test")
+	assert.NoError(t, testutil.GatherAndCompare(r, strings.NewReader(`# HELP go_sql_open_connections The number of established connections both in use and idle.
+# TYPE go_sql_open_connections gauge
+go_sql_open_connections{db_name="test"} 1
+`), "go_sql_open_connections"))
+}
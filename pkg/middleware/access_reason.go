@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	AccessReasonHeader = "X-Access-Reason"
+	BreakGlassHeader   = "X-Break-Glass"
+)
+
+type AccessReasonPolicy struct {
+	Required          bool
+	Codes             []string
+	BreakGlassEnabled bool
+}
+
+type accessReasonBody struct {
+	AccessReason string `json:"access_reason"`
+	BreakGlass   bool   `json:"break_glass"`
+}
+
+// RequireAccessReason membaca alasan akses dari header X-Access-Reason atau
+// field body access_reason dan menyimpannya di context sebagai
+// "accessReason". Jika policy mewajibkan alasan dan when mengembalikan true,
+// request tanpa kode alasan yang valid ditolak, kecuali break-glass aktif;
+// request break-glass diteruskan dengan "breakGlass" = true agar audit
+// menandainya untuk ditinjau supervisor. when nil berarti selalu berlaku.
+func RequireAccessReason(policy AccessReasonPolicy, when func(c *gin.Context) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reason := c.GetHeader(AccessReasonHeader)
+		breakGlass, _ := strconv.ParseBool(c.GetHeader(BreakGlassHeader))
+
+		if c.Request.ContentLength > 0 {
+			var body accessReasonBody
+			if err := c.ShouldBindBodyWith(&body, binding.JSON); err == nil {
+				if reason == "" {
+					reason = body.AccessReason
+				}
+				breakGlass = breakGlass || body.BreakGlass
+			}
+		}
+
+		validReason := slices.Contains(policy.Codes, reason)
+		if validReason {
+			c.Set("accessReason", reason)
+		}
+
+		if !policy.Required || (when != nil && !when(c)) || validReason {
+			c.Next()
+			return
+		}
+
+		if breakGlass && policy.BreakGlassEnabled {
+			// Catatan bebas tetap disimpan agar supervisor tahu konteksnya.
+			c.Set("accessReason", reason)
+			c.Set("breakGlass", true)
+			c.Next()
+			return
+		}
+
+		p := problem.New(http.StatusBadRequest, message.CodeAccessReasonMissing, message.AccessReasonRequired)
+		p.Extensions = map[string]interface{}{"reason_codes": policy.Codes}
+		problem.Write(c, p)
+		c.Abort()
+	}
+}
+
+// HasQuery mengembalikan kondisi untuk RequireAccessReason yang berlaku
+// hanya jika query parameter key diisi.
+func HasQuery(key string) func(c *gin.Context) bool {
+	return func(c *gin.Context) bool {
+		return c.Query(key) != ""
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAccessReason(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	policy := AccessReasonPolicy{
+		Required:          true,
+		Codes:             []string{"CUSTOMER_REQUEST", "FRAUD_INVESTIGATION"},
+		BreakGlassEnabled: true,
+	}
+
+	tests := []struct {
+		name           string
+		policy         AccessReasonPolicy
+		target         string
+		body           string
+		headers        map[string]string
+		expectedStatus int
+		expectedReason string
+		expectedGlass  bool
+	}{
+		{
+			name:           "Reason Header",
+			policy:         policy,
+			target:         "/customers?account_number=123",
+			headers:        map[string]string{AccessReasonHeader: "CUSTOMER_REQUEST"},
+			expectedStatus: http.StatusOK,
+			expectedReason: "CUSTOMER_REQUEST",
+		},
+		{
+			name:           "Reason Body",
+			policy:         policy,
+			target:         "/customers?account_number=123",
+			body:           `{"access_reason":"FRAUD_INVESTIGATION"}`,
+			expectedStatus: http.StatusOK,
+			expectedReason: "FRAUD_INVESTIGATION",
+		},
+		{
+			name:           "Missing Reason",
+			policy:         policy,
+			target:         "/customers?account_number=123",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown Reason",
+			policy:         policy,
+			target:         "/customers?account_number=123",
+			headers:        map[string]string{AccessReasonHeader: "CURIOUS"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Condition Not Met",
+			policy:         policy,
+			target:         "/customers?name=John",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Break Glass",
+			policy:         policy,
+			target:         "/customers?account_number=123",
+			headers:        map[string]string{BreakGlassHeader: "true", AccessReasonHeader: "outage"},
+			expectedStatus: http.StatusOK,
+			expectedReason: "outage",
+			expectedGlass:  true,
+		},
+		{
+			name:           "Break Glass Disabled",
+			policy:         AccessReasonPolicy{Required: true, Codes: policy.Codes},
+			target:         "/customers?account_number=123",
+			headers:        map[string]string{BreakGlassHeader: "true"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Policy Off",
+			policy:         AccessReasonPolicy{Codes: policy.Codes},
+			target:         "/customers?account_number=123",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reason string
+			var breakGlass bool
+
+			r := gin.New()
+			r.GET("/customers", RequireAccessReason(tt.policy, HasQuery("account_number")), func(c *gin.Context) {
+				reason = c.GetString("accessReason")
+				breakGlass = c.GetBool("breakGlass")
+				c.String(http.StatusOK, "success")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, strings.NewReader(tt.body))
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedReason, reason)
+			assert.Equal(t, tt.expectedGlass, breakGlass)
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Contains(t, w.Body.String(), `"code":"access_reason_required"`)
+				assert.Contains(t, w.Body.String(), `"reason_codes":["CUSTOMER_REQUEST","FRAUD_INVESTIGATION"]`)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubAPIKeys map[string]*model.APIKey
+
+func (s stubAPIKeys) Authenticate(key string) (*model.APIKey, error) {
+	if found, ok := s[key]; ok {
+		return found, nil
+	}
+	return nil, errors.New("invalid api key")
+}
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	validToken, err := utils.GenerateJWT(123, "")
+	assert.NoError(t, err)
+
+	apiKeys := stubAPIKeys{
+		"csk_reader": {Model: gorm.Model{ID: 1}, UserID: 7, Scopes: model.ScopeCustomersRead},
+	}
+
+	tests := []struct {
+		name           string
+		header         string
+		value          string
+		expectedStatus int
+		expectedUserID uint
+	}{
+		{name: "X-API-Key", header: APIKeyHeader, value: "csk_reader", expectedStatus: http.StatusOK, expectedUserID: 7},
+		{name: "Authorization ApiKey", header: "Authorization", value: "ApiKey csk_reader", expectedStatus: http.StatusOK, expectedUserID: 7},
+		{name: "Unknown Key", header: APIKeyHeader, value: "csk_unknown", expectedStatus: http.StatusUnauthorized},
+		{name: "Bearer Token", header: "Authorization", value: "Bearer " + validToken, expectedStatus: http.StatusOK, expectedUserID: 123},
+		{name: "Missing Credentials", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(Authenticate(nil, apiKeys))
+			r.GET("/customers", RequireScope(model.ScopeCustomersRead), func(c *gin.Context) {
+				assert.Equal(t, tt.expectedUserID, c.GetUint("userID"))
+				c.String(http.StatusOK, "success")
+			})
+			r.GET("/admin", RequireScope(model.ScopeAdmin), func(c *gin.Context) {
+				c.String(http.StatusOK, "success")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/customers", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			// Key tanpa scope admin ditolak, token JWT tidak terpengaruh scope.
+			if tt.expectedStatus == http.StatusOK {
+				req = httptest.NewRequest(http.MethodGet, "/admin", nil)
+				req.Header.Set(tt.header, tt.value)
+				w = httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				expected := http.StatusOK
+				if tt.header == APIKeyHeader || tt.value == "ApiKey csk_reader" {
+					expected = http.StatusForbidden
+				}
+				assert.Equal(t, expected, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationPolicy menjelaskan route lama yang masih dilayani. Successor
+// adalah prefix versi pengganti, misalnya "/v1".
+type DeprecationPolicy struct {
+	Since     time.Time
+	Sunset    time.Time
+	Successor string
+}
+
+// Deprecated menambahkan header Deprecation (RFC 9745), Sunset (RFC 8594),
+// dan Link ke route pengganti. Header dipasang sebelum handler berjalan
+// sehingga ikut terkirim pada respons error.
+func Deprecated(policy DeprecationPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if policy.Since.IsZero() {
+			c.Header("Deprecation", "true")
+		} else {
+			c.Header("Deprecation", "@"+strconv.FormatInt(policy.Since.Unix(), 10))
+		}
+		if !policy.Sunset.IsZero() {
+			c.Header("Sunset", policy.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if policy.Successor != "" {
+			c.Header("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, policy.Successor, c.Request.URL.Path))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("dates and successor link", func(t *testing.T) {
+		r := gin.New()
+		r.Use(Deprecated(DeprecationPolicy{
+			Since:     time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+			Sunset:    time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+			Successor: "/v1",
+		}))
+		r.GET("/customers/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers/5", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Fri, 30 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		assert.Equal(t, `</v1/customers/5>; rel="successor-version"`, w.Header().Get("Link"))
+	})
+
+	t.Run("without dates", func(t *testing.T) {
+		r := gin.New()
+		r.Use(Deprecated(DeprecationPolicy{}))
+		r.GET("/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/login", nil))
+
+		assert.Equal(t, "true", w.Header().Get("Deprecation"))
+		assert.Empty(t, w.Header().Get("Sunset"))
+		assert.Empty(t, w.Header().Get("Link"))
+	})
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingAudit struct {
+	logs []*model.AuditLog
+	err  error
+}
+
+func (r *recordingAudit) Record(entry *model.AuditLog) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.logs = append(r.logs, entry)
+	return nil
+}
+
+func newImpersonationRouter(actorID uint, handlers ...gin.HandlerFunc) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", uint(2))
+		if actorID != 0 {
+			c.Set("actorID", actorID)
+		}
+	})
+	r.Use(handlers...)
+	r.GET("/customers", func(c *gin.Context) {
+		c.String(http.StatusOK, "success")
+	})
+	return r
+}
+
+func TestAuditImpersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Impersonated Request", func(t *testing.T) {
+		audit := &recordingAudit{}
+		w := httptest.NewRecorder()
+		newImpersonationRouter(1, AuditImpersonation(audit)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers?name=john", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		if assert.Len(t, audit.logs, 1) {
+			assert.Equal(t, uint(2), audit.logs[0].UserID)
+			assert.Equal(t, uint(1), audit.logs[0].ActorID)
+			assert.Equal(t, model.AuditActionImpersonated, audit.logs[0].Action)
+			assert.JSONEq(t, `{"method":"GET","path":"/customers"}`, audit.logs[0].Query)
+		}
+	})
+
+	t.Run("Regular Request", func(t *testing.T) {
+		audit := &recordingAudit{}
+		w := httptest.NewRecorder()
+		newImpersonationRouter(0, AuditImpersonation(audit)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, audit.logs)
+	})
+
+	t.Run("Audit Failure", func(t *testing.T) {
+		audit := &recordingAudit{err: assert.AnError}
+		w := httptest.NewRecorder()
+		newImpersonationRouter(1, AuditImpersonation(audit)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestDenyImpersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	newImpersonationRouter(1, DenyImpersonation()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	newImpersonationRouter(0, DenyImpersonation()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
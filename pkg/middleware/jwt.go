@@ -5,28 +5,52 @@ import (
 	"strings"
 
 	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/metrics"
+	"github.com/danisasmita/customer-search/pkg/problem"
 	"github.com/danisasmita/customer-search/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func JWTAuth() gin.HandlerFunc {
+type RevocationChecker interface {
+	IsRevoked(claims *utils.Claims) bool
+}
+
+// JWTAuth memvalidasi bearer token dan menolak token yang sudah dicabut.
+// revocations boleh nil jika pencabutan tidak dipakai.
+func JWTAuth(revocations RevocationChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": message.Unauthorized})
-			c.Abort()
+			metrics.TokenValidationFailures.WithLabelValues(metrics.TokenMissing).Inc()
+			problem.Abort(c, http.StatusUnauthorized, message.CodeUnauthorized, message.Unauthorized)
+			return
+		}
+
+		scheme, token, found := strings.Cut(authHeader, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") {
+			metrics.TokenValidationFailures.WithLabelValues(metrics.TokenInvalid).Inc()
+			problem.Abort(c, http.StatusUnauthorized, message.CodeUnauthorized, message.Unauthorized)
 			return
 		}
 
-		token := strings.Split(authHeader, " ")[1]
-		claims, err := utils.ValidateJWT(token)
+		claims, err := utils.ValidateJWT(strings.TrimSpace(token))
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": message.Unauthorized})
-			c.Abort()
+			metrics.TokenValidationFailures.WithLabelValues(metrics.TokenInvalid).Inc()
+			problem.Abort(c, http.StatusUnauthorized, message.CodeUnauthorized, message.Unauthorized)
+			return
+		}
+
+		if revocations != nil && revocations.IsRevoked(claims) {
+			metrics.TokenValidationFailures.WithLabelValues(metrics.TokenRevoked).Inc()
+			problem.Abort(c, http.StatusUnauthorized, message.CodeUnauthorized, message.Unauthorized)
 			return
 		}
 
 		c.Set("userID", claims.UserID)
+		c.Set("claims", claims)
+		if actorID := claims.ActorID(); actorID != 0 {
+			c.Set("actorID", actorID)
+		}
 		c.Next()
 	}
 }
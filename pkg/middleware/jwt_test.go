@@ -5,23 +5,46 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/danisasmita/customer-search/pkg/metrics"
 	"github.com/danisasmita/customer-search/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
+type stubRevocations map[string]bool
+
+func (s stubRevocations) IsRevoked(claims *utils.Claims) bool {
+	return s[claims.Id]
+}
+
+const unauthorizedProblem = `{
+	"type": "about:blank",
+	"title": "Unauthorized",
+	"status": 401,
+	"detail": "unauthorized",
+	"instance": "/protected",
+	"code": "unauthorized"
+}`
+
 func TestJWTAuth(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	// Generate a valid token
-	validToken, err := utils.GenerateJWT(123)
+	validToken, err := utils.GenerateJWT(123, "")
+	assert.NoError(t, err)
+
+	revokedToken, err := utils.GenerateJWT(456, "")
+	assert.NoError(t, err)
+	revokedClaims, err := utils.ValidateJWT(revokedToken)
 	assert.NoError(t, err)
+	revocations := stubRevocations{revokedClaims.Id: true}
 
 	tests := []struct {
 		name           string
 		setupHeaders   func(req *http.Request)
 		expectedStatus int
 		expectedBody   string
+		failureReason  string
 	}{
 		{
 			name: "No Authorization Header",
@@ -29,7 +52,8 @@ func TestJWTAuth(t *testing.T) {
 				// Tidak ada header yang disetel untuk menguji kasus tanpa Authorization header
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   "{\"error\":\"unauthorized\"}",
+			expectedBody:   unauthorizedProblem,
+			failureReason:  metrics.TokenMissing,
 		},
 		{
 			name: "Invalid Token",
@@ -37,7 +61,35 @@ func TestJWTAuth(t *testing.T) {
 				req.Header.Set("Authorization", "Bearer invalidtoken")
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   "{\"error\":\"unauthorized\"}",
+			expectedBody:   unauthorizedProblem,
+			failureReason:  metrics.TokenInvalid,
+		},
+		{
+			name: "Bearer Without Token",
+			setupHeaders: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer")
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   unauthorizedProblem,
+			failureReason:  metrics.TokenInvalid,
+		},
+		{
+			name: "Missing Scheme",
+			setupHeaders: func(req *http.Request) {
+				req.Header.Set("Authorization", validToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   unauthorizedProblem,
+			failureReason:  metrics.TokenInvalid,
+		},
+		{
+			name: "Wrong Scheme",
+			setupHeaders: func(req *http.Request) {
+				req.Header.Set("Authorization", "Basic "+validToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   unauthorizedProblem,
+			failureReason:  metrics.TokenInvalid,
 		},
 		{
 
@@ -49,12 +101,21 @@ func TestJWTAuth(t *testing.T) {
 			expectedBody:   "success", // Ini bukan JSON, jadi pakai assert.Equal, bukan assert.JSONEq
 
 		},
+		{
+			name: "Revoked Token",
+			setupHeaders: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer "+revokedToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   unauthorizedProblem,
+			failureReason:  metrics.TokenRevoked,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := gin.New()
-			r.Use(JWTAuth())
+			r.Use(JWTAuth(revocations))
 			r.GET("/protected", func(c *gin.Context) {
 				c.String(http.StatusOK, "success")
 			})
@@ -70,6 +131,9 @@ func TestJWTAuth(t *testing.T) {
 			} else {
 				assert.JSONEq(t, tt.expectedBody, w.Body.String()) // Untuk error response, cek sebagai JSON
 			}
+			if tt.failureReason != "" {
+				assert.Contains(t, metricsOutput(t), `auth_token_validation_failures_total{reason="`+tt.failureReason+`"}`)
+			}
 
 		})
 	}
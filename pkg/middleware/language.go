@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Language memilih bahasa pesan dari header Accept-Language. fallback
+// dipakai jika header kosong atau tidak ada bahasa yang didukung.
+func Language(fallback string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept-Language")
+		setLanguage(c, i18n.Negotiate(c.GetHeader("Accept-Language"), fallback))
+		c.Next()
+	}
+}
+
+// UserLanguage harus dipasang setelah LoadUser. Bahasa yang disimpan user
+// mengganti hasil Accept-Language. Saat impersonation, bahasa admin yang
+// dipakai karena admin yang membaca respons.
+func UserLanguage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := CurrentUser(c)
+		if actor, found := c.Get("actor"); found {
+			user, ok = actor.(*model.User), true
+		}
+
+		if ok && i18n.Supported(user.Language) {
+			setLanguage(c, user.Language)
+		}
+		c.Next()
+	}
+}
+
+func setLanguage(c *gin.Context, lang string) {
+	c.Set(i18n.ContextKey, lang)
+	c.Header("Content-Language", lang)
+}
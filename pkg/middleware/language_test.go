@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/pkg/i18n"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	users := stubUserFinder{
+		1: {Username: "no-preference"},
+		2: {Username: "indonesian", Language: i18n.Indonesian},
+		3: {Username: "english", Language: i18n.English},
+	}
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		userID         uint
+		actorID        uint
+		expected       string
+		expectedDetail string
+	}{
+		{name: "Default", userID: 1, expected: i18n.English, expectedDetail: message.CustomerNotFound},
+		{name: "Accept Language", acceptLanguage: "id-ID,en;q=0.5", userID: 1, expected: i18n.Indonesian, expectedDetail: "nasabah tidak ditemukan"},
+		{name: "User Preference", acceptLanguage: "en", userID: 2, expected: i18n.Indonesian, expectedDetail: "nasabah tidak ditemukan"},
+		{name: "Preference Overrides Header", acceptLanguage: "id", userID: 3, expected: i18n.English, expectedDetail: message.CustomerNotFound},
+		{name: "Impersonating Admin", userID: 3, actorID: 2, expected: i18n.Indonesian, expectedDetail: "nasabah tidak ditemukan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(Language(i18n.English), func(c *gin.Context) {
+				c.Set("userID", tt.userID)
+				if tt.actorID != 0 {
+					c.Set("actorID", tt.actorID)
+				}
+			}, LoadUser(users), UserLanguage())
+			r.GET("/customers/1", func(c *gin.Context) {
+				problem.Abort(c, http.StatusNotFound, message.CodeCustomerNotFound, message.CustomerNotFound)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/customers/1", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Header().Get("Content-Language"))
+			assert.Contains(t, w.Body.String(), `"detail":"`+tt.expectedDetail+`"`)
+			assert.Contains(t, w.Body.String(), `"code":"customer_not_found"`)
+		})
+	}
+}
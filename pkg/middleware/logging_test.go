@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logLines mendekode setiap baris JSON di buf.
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, raw := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal([]byte(raw), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.Config{})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(RequestID(), AccessLog(logger), Recovery())
+	r.GET("/customers/:id", func(c *gin.Context) {
+		c.Set("userID", uint(7))
+		assert.Same(t, logger, logging.FromContext(c.Request.Context()))
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom for jane@example.com")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/customers/1?email=jane@example.com", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := logLines(t, &buf)[0]
+	assert.Equal(t, "request", line["msg"])
+	assert.Equal(t, "INFO", line["level"])
+	assert.Equal(t, "/customers/:id", line["route"])
+	assert.Equal(t, float64(http.StatusNoContent), line["status"])
+	assert.Equal(t, float64(7), line["user_id"])
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.NotContains(t, buf.String(), "jane@example.com")
+
+	buf.Reset()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":500`)
+
+	lines := logLines(t, &buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "panic recovered", lines[0]["msg"])
+	assert.Equal(t, "boom for [REDACTED]", lines[0]["panic"])
+	assert.Equal(t, lines[0]["request_id"], lines[1]["request_id"])
+	assert.Equal(t, "ERROR", lines[1]["level"])
+}
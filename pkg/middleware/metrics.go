@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/danisasmita/customer-search/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute dipakai sebagai label route untuk request yang tidak
+// cocok dengan route mana pun, agar path acak tidak menjadi seri baru.
+const unmatchedRoute = "unmatched"
+
+// Metrics mencatat durasi setiap request ke metrics.HTTPRequestDuration
+// dengan label template route.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Observe(metrics.Since(start))
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireMFA harus dipasang setelah LoadUser. User dengan salah satu role
+// yang diwajibkan MFA ditolak sampai menyelesaikan enrollment, jadi route
+// enrollment sendiri tidak boleh memakai middleware ini. Request dengan API
+// key dilewatkan karena key tidak dipakai untuk login interaktif.
+func RequireMFA(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(roles) == 0 || c.GetUint("apiKeyID") != 0 {
+			c.Next()
+			return
+		}
+
+		user, ok := CurrentUser(c)
+		if !ok {
+			problem.Abort(c, http.StatusUnauthorized, message.CodeUnauthorized, message.Unauthorized)
+			return
+		}
+
+		if slices.Contains(roles, user.Role) && !user.MFAEnabled {
+			problem.Abort(c, http.StatusForbidden, message.CodeMFAEnrollmentRequired, message.MFAEnrollmentRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
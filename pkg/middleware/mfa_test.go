@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	users := stubUserFinder{
+		1: {Username: "admin", Role: model.RoleAdmin},
+		2: {Username: "secure-admin", Role: model.RoleAdmin, MFAEnabled: true},
+		3: {Username: "agent", Role: model.RoleAgent},
+	}
+
+	tests := []struct {
+		name           string
+		userID         uint
+		roles          []string
+		expectedStatus int
+	}{
+		{name: "Not Enrolled", userID: 1, roles: []string{model.RoleAdmin}, expectedStatus: http.StatusForbidden},
+		{name: "Enrolled", userID: 2, roles: []string{model.RoleAdmin}, expectedStatus: http.StatusOK},
+		{name: "Role Not Required", userID: 3, roles: []string{model.RoleAdmin}, expectedStatus: http.StatusOK},
+		{name: "Policy Off", userID: 1, expectedStatus: http.StatusOK},
+		{name: "Unknown User", userID: 9, roles: []string{model.RoleAdmin}, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				c.Set("userID", tt.userID)
+			})
+			r.Use(LoadUser(users), RequireMFA(tt.roles...))
+			r.GET("/customers", func(c *gin.Context) {
+				c.String(http.StatusOK, "success")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/customers", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole harus dipasang setelah LoadUser. Role dibaca dari user di
+// database, bukan dari token, agar perubahan role langsung berlaku tanpa
+// menunggu token kedaluwarsa.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := CurrentUser(c)
+		if !ok {
+			problem.Abort(c, http.StatusUnauthorized, message.CodeUnauthorized, message.Unauthorized)
+			return
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				c.Set("role", user.Role)
+				c.Next()
+				return
+			}
+		}
+
+		problem.Abort(c, http.StatusForbidden, message.CodeForbidden, message.Forbidden)
+	}
+}
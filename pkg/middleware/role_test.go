@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	users := stubUserFinder{
+		1: {Username: "admin", Role: model.RoleAdmin},
+		2: {Username: "agent", Role: model.RoleAgent},
+	}
+
+	tests := []struct {
+		name           string
+		userID         uint
+		expectedStatus int
+	}{
+		{name: "Admin", userID: 1, expectedStatus: http.StatusOK},
+		{name: "Agent", userID: 2, expectedStatus: http.StatusForbidden},
+		{name: "Unknown User", userID: 3, expectedStatus: http.StatusUnauthorized},
+		{name: "Missing User", userID: 0, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tt.userID != 0 {
+					c.Set("userID", tt.userID)
+				}
+			})
+			r.Use(LoadUser(users), RequireRole(model.RoleAdmin))
+			r.GET("/admin", func(c *gin.Context) {
+				c.String(http.StatusOK, "success")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
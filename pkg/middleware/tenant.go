@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/problem"
+	"github.com/danisasmita/customer-search/pkg/tenant"
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// TenantScope harus dipasang setelah LoadUser. Cabang JWT dibaca dari
+// claim tenant_id dan harus sama dengan cabang user di database, sehingga
+// token lama tidak berlaku lagi setelah user dipindah cabang. Request API
+// key memakai cabang pemilik key. User dengan salah satu globalRoles boleh
+// melihat semua cabang.
+func TenantScope(globalRoles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := CurrentUser(c)
+		if !ok {
+			problem.Abort(c, http.StatusUnauthorized, message.CodeUnauthorized, message.Unauthorized)
+			return
+		}
+
+		scope := tenant.Scope{TenantID: user.TenantID}
+		for _, role := range globalRoles {
+			if user.Role == role {
+				scope = tenant.Scope{Global: true}
+				break
+			}
+		}
+
+		if claims, ok := c.Get("claims"); ok {
+			if claims.(*utils.Claims).TenantID != user.TenantID {
+				problem.Abort(c, http.StatusUnauthorized, message.CodeUnauthorized, message.Unauthorized)
+				return
+			}
+		}
+
+		if !scope.Global && scope.TenantID == "" {
+			problem.Abort(c, http.StatusForbidden, message.CodeNoTenant, message.NoTenant)
+			return
+		}
+
+		c.Request = c.Request.WithContext(tenant.WithScope(c.Request.Context(), scope))
+		c.Next()
+	}
+}
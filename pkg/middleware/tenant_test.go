@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/tenant"
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	users := stubUserFinder{
+		1: {Username: "admin", Role: model.RoleAdmin},
+		2: {Username: "agent", Role: model.RoleAgent, TenantID: "JKT"},
+		3: {Username: "orphan", Role: model.RoleAgent},
+	}
+
+	tests := []struct {
+		name           string
+		userID         uint
+		claims         *utils.Claims
+		expectedStatus int
+		expectedScope  tenant.Scope
+	}{
+		{name: "Global Role", userID: 1, claims: &utils.Claims{UserID: 1}, expectedStatus: http.StatusOK, expectedScope: tenant.Scope{Global: true}},
+		{name: "Branch From Token", userID: 2, claims: &utils.Claims{UserID: 2, TenantID: "JKT"}, expectedStatus: http.StatusOK, expectedScope: tenant.Scope{TenantID: "JKT"}},
+		{name: "Branch From API Key Owner", userID: 2, expectedStatus: http.StatusOK, expectedScope: tenant.Scope{TenantID: "JKT"}},
+		{name: "Stale Token Branch", userID: 2, claims: &utils.Claims{UserID: 2, TenantID: "SBY"}, expectedStatus: http.StatusUnauthorized},
+		{name: "No Branch", userID: 3, claims: &utils.Claims{UserID: 3}, expectedStatus: http.StatusForbidden},
+		{name: "Unknown User", userID: 9, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scope tenant.Scope
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				c.Set("userID", tt.userID)
+				if tt.claims != nil {
+					c.Set("claims", tt.claims)
+				}
+			})
+			r.Use(LoadUser(users), TenantScope(model.RoleAdmin))
+			r.GET("/customers", func(c *gin.Context) {
+				scope, _ = tenant.FromContext(c.Request.Context())
+				c.String(http.StatusOK, "success")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/customers", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedScope, scope)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/danisasmita/customer-search/pkg/problem"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing membuat span server otelgin untuk setiap request dan melanjutkan
+// trace dari header traceparent jika ada. Span di service dan GORM menjadi
+// anak span ini lewat context request. Span diberi request ID dan, setelah
+// autentikasi, ID user. Dipasang dengan r.Use(middleware.Tracing(name)...).
+func Tracing(service string) gin.HandlersChain {
+	return gin.HandlersChain{otelgin.Middleware(service), traceAttributes}
+}
+
+func traceAttributes(c *gin.Context) {
+	span := trace.SpanFromContext(c.Request.Context())
+	span.SetAttributes(attribute.String("request.id", c.GetString(problem.RequestIDKey)))
+	c.Next()
+
+	if userID := c.GetUint("userID"); userID != 0 {
+		span.SetAttributes(attribute.Int("enduser.id", int(userID)))
+	}
+}
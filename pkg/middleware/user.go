@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/danisasmita/customer-search/pkg/problem"
+	"github.com/gin-gonic/gin"
+)
+
+type UserFinder interface {
+	FindUserByID(id uint) (*model.User, error)
+}
+
+// LoadUser harus dipasang tepat setelah JWTAuth atau Authenticate. User
+// dibaca sekali dari database dan disimpan di context agar RequireRole,
+// RequireMFA, TenantScope, dan UserLanguage tidak membacanya ulang. Saat
+// impersonation, admin yang memakai token ikut dibaca. User atau admin yang
+// dinonaktifkan langsung ditolak tanpa menunggu sinkronisasi pencabutan
+// token di replica lain.
+func LoadUser(users UserFinder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetUint("userID")
+		if userID == 0 {
+			problem.Abort(c, http.StatusUnauthorized, message.CodeUnauthorized, message.Unauthorized)
+			return
+		}
+
+		user, err := users.FindUserByID(userID)
+		if err != nil || user.Disabled {
+			problem.Abort(c, http.StatusUnauthorized, message.CodeUnauthorized, message.Unauthorized)
+			return
+		}
+		c.Set("user", user)
+
+		if actorID := c.GetUint("actorID"); actorID != 0 {
+			actor, err := users.FindUserByID(actorID)
+			if err != nil || actor.Disabled {
+				problem.Abort(c, http.StatusUnauthorized, message.CodeUnauthorized, message.Unauthorized)
+				return
+			}
+			c.Set("actor", actor)
+		}
+
+		c.Next()
+	}
+}
+
+// CurrentUser mengembalikan user yang disimpan LoadUser.
+func CurrentUser(c *gin.Context) (*model.User, bool) {
+	user, ok := c.Get("user")
+	if !ok {
+		return nil, false
+	}
+	return user.(*model.User), true
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubUserFinder map[uint]*model.User
+
+func (s stubUserFinder) FindUserByID(id uint) (*model.User, error) {
+	if user, ok := s[id]; ok {
+		return user, nil
+	}
+	return nil, errors.New("not found")
+}
+
+// countingUserFinder menghitung berapa kali user dibaca dari database.
+type countingUserFinder struct {
+	stubUserFinder
+	calls int
+}
+
+func (f *countingUserFinder) FindUserByID(id uint) (*model.User, error) {
+	f.calls++
+	return f.stubUserFinder.FindUserByID(id)
+}
+
+func TestLoadUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	users := stubUserFinder{
+		1: {Username: "admin", Role: model.RoleAdmin},
+		2: {Username: "agent", Role: model.RoleAgent},
+		3: {Username: "disabled-admin", Role: model.RoleAdmin, Disabled: true},
+		4: {Username: "disabled-agent", Role: model.RoleAgent, Disabled: true},
+	}
+
+	tests := []struct {
+		name             string
+		userID           uint
+		actorID          uint
+		expectedStatus   int
+		expectedUsername string
+	}{
+		{name: "Found", userID: 2, expectedStatus: http.StatusOK, expectedUsername: "agent"},
+		{name: "Impersonation", userID: 2, actorID: 1, expectedStatus: http.StatusOK, expectedUsername: "agent"},
+		{name: "Unknown User", userID: 9, expectedStatus: http.StatusUnauthorized},
+		{name: "Unknown Actor", userID: 2, actorID: 9, expectedStatus: http.StatusUnauthorized},
+		{name: "Disabled User", userID: 4, expectedStatus: http.StatusUnauthorized},
+		{name: "Disabled Actor", userID: 2, actorID: 3, expectedStatus: http.StatusUnauthorized},
+		{name: "Missing User", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var username string
+			var actor any
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tt.userID != 0 {
+					c.Set("userID", tt.userID)
+				}
+				if tt.actorID != 0 {
+					c.Set("actorID", tt.actorID)
+				}
+			})
+			r.Use(LoadUser(users))
+			r.GET("/customers", func(c *gin.Context) {
+				user, _ := CurrentUser(c)
+				username = user.Username
+				actor, _ = c.Get("actor")
+				c.String(http.StatusOK, "success")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/customers", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedUsername, username)
+			if tt.actorID != 0 && tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, users[tt.actorID], actor)
+			}
+		})
+	}
+}
+
+func TestLoadUserReadsDatabaseOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	users := &countingUserFinder{stubUserFinder: stubUserFinder{
+		1: {Username: "admin", Role: model.RoleAdmin, MFAEnabled: true, Language: i18n.Indonesian},
+	}}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+	})
+	r.Use(LoadUser(users), UserLanguage(), RequireRole(model.RoleAdmin), RequireMFA(model.RoleAdmin), TenantScope(model.RoleAdmin))
+	r.GET("/admin/users", func(c *gin.Context) {
+		c.String(http.StatusOK, "success")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, users.calls)
+}
+
+func TestCurrentUserWithoutLoadUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(RequireRole(model.RoleAdmin))
+	r.GET("/admin/users", func(c *gin.Context) {
+		c.String(http.StatusOK, "success")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,91 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	KindLog  = "log"
+	KindFile = "file"
+)
+
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Notifier mengirim pesan ke user, misalnya token reset password.
+// Implementasi email atau SMS cukup memenuhi interface ini.
+type Notifier interface {
+	Send(msg Message) error
+}
+
+// New membuat notifier bawaan berdasarkan kind. Keduanya hanya untuk
+// pengembangan lokal; isi pesan, termasuk token, hanya bisa dibaca lewat
+// notifier file.
+func New(kind, path string, logger *slog.Logger) (Notifier, error) {
+	switch kind {
+	case "", KindLog:
+		return NewLogNotifier(logger), nil
+	case KindFile:
+		if path == "" {
+			return nil, fmt.Errorf("notifier file path is required")
+		}
+		return NewFileNotifier(path), nil
+	default:
+		return nil, fmt.Errorf("unsupported notifier %q", kind)
+	}
+}
+
+type logNotifier struct {
+	logger *slog.Logger
+}
+
+// NewLogNotifier hanya mencatat bahwa pesan dikirim. Isi pesan tidak
+// ditulis ke log karena berisi token.
+func NewLogNotifier(logger *slog.Logger) Notifier {
+	return logNotifier{logger: logger}
+}
+
+func (n logNotifier) Send(msg Message) error {
+	n.logger.Info("notification sent", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
+
+type fileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileNotifier menambahkan setiap pesan sebagai satu baris JSON ke path.
+func NewFileNotifier(path string) Notifier {
+	return &fileNotifier{path: path}
+}
+
+func (n *fileNotifier) Send(msg Message) error {
+	line, err := json.Marshal(struct {
+		SentAt time.Time `json:"sent_at"`
+		Message
+	}{SentAt: time.Now().UTC(), Message: msg})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
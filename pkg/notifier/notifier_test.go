@@ -0,0 +1,58 @@
+package notifier_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danisasmita/customer-search/pkg/logging"
+	"github.com/danisasmita/customer-search/pkg/notifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	n, err := notifier.New(notifier.KindFile, path, logging.Discard())
+	assert.NoError(t, err)
+
+	assert.NoError(t, n.Send(notifier.Message{To: "john", Subject: "first", Body: "a"}))
+	assert.NoError(t, n.Send(notifier.Message{To: "jane", Subject: "second", Body: "b"}))
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var recipients []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var msg notifier.Message
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &msg))
+		recipients = append(recipients, msg.To)
+	}
+	assert.Equal(t, []string{"john", "jane"}, recipients)
+}
+
+func TestNewNotifier(t *testing.T) {
+	_, err := notifier.New(notifier.KindLog, "", logging.Discard())
+	assert.NoError(t, err)
+
+	_, err = notifier.New(notifier.KindFile, "", logging.Discard())
+	assert.Error(t, err)
+
+	_, err = notifier.New("carrier-pigeon", "", logging.Discard())
+	assert.Error(t, err)
+}
+
+func TestLogNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.Config{})
+	assert.NoError(t, err)
+
+	n := notifier.NewLogNotifier(logger)
+	assert.NoError(t, n.Send(notifier.Message{To: "john", Subject: "Password reset", Body: "token: s3cr3t-reset"}))
+	assert.Contains(t, buf.String(), `"subject":"Password reset"`)
+	assert.NotContains(t, buf.String(), "s3cr3t-reset")
+}
@@ -0,0 +1,380 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/dgrijalva/jwt-go"
+)
+
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+// Config berisi data client yang didaftarkan di identity provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+	// TenantClaim berisi kode cabang user. Kosong berarti cabang tidak
+	// dibaca dari ID token.
+	TenantClaim string
+}
+
+// Provider adalah metadata dari dokumen discovery
+// <issuer>/.well-known/openid-configuration.
+type Provider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Identity adalah isi ID token yang sudah diverifikasi.
+type Identity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	PreferredUsername string
+	Groups            []string
+	Tenant            string
+}
+
+// Client menjalankan authorization code flow dengan PKCE (S256) terhadap
+// satu identity provider.
+type Client struct {
+	config     Config
+	provider   Provider
+	httpClient *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]interface{}
+}
+
+// Discover mengambil dokumen discovery dan memastikan issuer di dalamnya
+// sama dengan issuer yang dikonfigurasi.
+func Discover(ctx context.Context, config Config, httpClient *http.Client) (*Client, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "profile", "email"}
+	}
+
+	var provider Provider
+	wellKnown := strings.TrimSuffix(config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(ctx, httpClient, wellKnown, &provider); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if provider.Issuer != config.Issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match %q", provider.Issuer, config.Issuer)
+	}
+
+	return &Client{config: config, provider: provider, httpClient: httpClient}, nil
+}
+
+// AuthCodeURL membuat URL redirect ke identity provider. challenge adalah
+// hasil S256Challenge dari code verifier yang disimpan sampai callback.
+func (c *Client) AuthCodeURL(state, nonce, challenge string) string {
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.config.ClientID},
+		"redirect_uri":          {c.config.RedirectURL},
+		"scope":                 {strings.Join(c.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(c.provider.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return c.provider.AuthorizationEndpoint + separator + query.Encode()
+}
+
+// Exchange menukar authorization code dengan token. Client secret dikirim
+// lewat HTTP Basic (client_secret_basic) jika dikonfigurasi.
+func (c *Client) Exchange(ctx context.Context, code, verifier string) (*TokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.config.RedirectURL},
+		"client_id":     {c.config.ClientID},
+		"code_verifier": {verifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.config.ClientID), url.QueryEscape(c.config.ClientSecret))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc token endpoint returned %s", resp.Status)
+	}
+
+	var token TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("oidc token response has no id_token")
+	}
+	return &token, nil
+}
+
+// Verify memeriksa tanda tangan, issuer, audience, masa berlaku, dan nonce
+// ID token.
+func (c *Client) Verify(ctx context.Context, rawIDToken, nonce string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return c.verificationKey(ctx, token)
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidIDToken
+	}
+
+	if !claims.VerifyIssuer(c.config.Issuer, true) || !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, ErrInvalidIDToken
+	}
+	if !slices.Contains(stringList(claims["aud"]), c.config.ClientID) {
+		return nil, ErrInvalidIDToken
+	}
+	if azp, ok := claims["azp"].(string); ok && azp != c.config.ClientID {
+		return nil, ErrInvalidIDToken
+	}
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return nil, ErrInvalidIDToken
+	}
+
+	identity := &Identity{Issuer: c.config.Issuer, Groups: stringList(claims[c.config.GroupsClaim])}
+	identity.Subject, _ = claims["sub"].(string)
+	identity.Email, _ = claims["email"].(string)
+	identity.PreferredUsername, _ = claims["preferred_username"].(string)
+	if c.config.TenantClaim != "" {
+		identity.Tenant, _ = claims[c.config.TenantClaim].(string)
+	}
+	if identity.Subject == "" {
+		return nil, ErrInvalidIDToken
+	}
+	return identity, nil
+}
+
+// verificationKey mengambil ulang JWKS saat kid belum dikenal agar rotasi
+// kunci di identity provider langsung terbaca.
+func (c *Client) verificationKey(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, found := c.lookupKey(kid)
+	if !found {
+		if err := c.refreshKeys(ctx); err != nil {
+			return nil, err
+		}
+		if key, found = c.lookupKey(kid); !found {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey:
+		if token.Method.Alg() != jwt.SigningMethodRS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+	case *ecdsa.PublicKey:
+		if token.Method.Alg() != jwt.SigningMethodES256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+	case ed25519.PublicKey:
+		if token.Method.Alg() != utils.AlgorithmEdDSA {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+	}
+	return key, nil
+}
+
+// lookupKey tanpa kid hanya berhasil jika JWKS berisi tepat satu kunci.
+func (c *Client) lookupKey(kid string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	key, found := c.keys[kid]
+	return key, found
+}
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+func (c *Client) refreshKeys(ctx context.Context) error {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, c.httpClient, c.provider.JWKSURI, &set); err != nil {
+		return fmt.Errorf("oidc jwks: %w", err)
+	}
+
+	keys := map[string]interface{}{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// Kunci dengan tipe yang tidak didukung dilewati saja.
+		if key, err := parseJWK(jwk); err == nil {
+			keys[jwk.KeyID] = key
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys = keys
+	return nil
+}
+
+func parseJWK(jwk jsonWebKey) (interface{}, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if jwk.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if jwk.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.KeyType)
+	}
+}
+
+// NewVerifier membuat code verifier PKCE acak sepanjang 43 karakter.
+func NewVerifier() (string, error) {
+	return RandomString(32)
+}
+
+// S256Challenge menghitung code_challenge dari verifier sesuai RFC 7636.
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RandomString mengembalikan size byte acak dalam base64url, dipakai untuk
+// state, nonce, dan code verifier.
+func RandomString(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// stringList menerima claim berupa string tunggal atau array string.
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				items = append(items, s)
+			}
+		}
+		return items
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,91 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/danisasmita/customer-search/pkg/oidc"
+	"github.com/danisasmita/customer-search/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientAuthorizationCodeFlow(t *testing.T) {
+	server := oidctest.NewServer("customer-search", "s3cret")
+	defer server.Close()
+	server.SetUser(oidctest.User{Subject: "abc123", PreferredUsername: "jane", Groups: []string{"cs-admins"}, Branch: "JKT"})
+
+	ctx := context.Background()
+	client, err := oidc.Discover(ctx, oidc.Config{
+		Issuer:       server.Issuer(),
+		ClientID:     "customer-search",
+		ClientSecret: "s3cret",
+		RedirectURL:  "http://localhost:8080/oidc/callback",
+		TenantClaim:  "branch",
+	}, nil)
+	require.NoError(t, err)
+
+	verifier, err := oidc.NewVerifier()
+	require.NoError(t, err)
+
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := noRedirect.Get(client.AuthCodeURL("state-1", "nonce-1", oidc.S256Challenge(verifier)))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "state-1", callback.Query().Get("state"))
+	code := callback.Query().Get("code")
+
+	// Verifier yang salah ditolak oleh provider.
+	_, err = client.Exchange(ctx, code, "wrong-verifier")
+	assert.Error(t, err)
+
+	resp, err = noRedirect.Get(client.AuthCodeURL("state-2", "nonce-2", oidc.S256Challenge(verifier)))
+	require.NoError(t, err)
+	resp.Body.Close()
+	callback, _ = url.Parse(resp.Header.Get("Location"))
+
+	token, err := client.Exchange(ctx, callback.Query().Get("code"), verifier)
+	require.NoError(t, err)
+
+	_, err = client.Verify(ctx, token.IDToken, "nonce-1")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+
+	identity, err := client.Verify(ctx, token.IDToken, "nonce-2")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", identity.Subject)
+	assert.Equal(t, "jane", identity.PreferredUsername)
+	assert.Equal(t, []string{"cs-admins"}, identity.Groups)
+	assert.Equal(t, "JKT", identity.Tenant)
+}
+
+func TestClientRejectsForeignToken(t *testing.T) {
+	server := oidctest.NewServer("customer-search", "")
+	defer server.Close()
+	other := oidctest.NewServer("customer-search", "")
+	defer other.Close()
+
+	ctx := context.Background()
+	client, err := oidc.Discover(ctx, oidc.Config{Issuer: server.Issuer(), ClientID: "customer-search"}, nil)
+	require.NoError(t, err)
+
+	foreign, err := other.IDToken(oidctest.User{Subject: "abc123"}, "nonce")
+	require.NoError(t, err)
+	_, err = client.Verify(ctx, foreign, "nonce")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+
+	_, err = oidc.Discover(ctx, oidc.Config{Issuer: server.Issuer() + "/other"}, nil)
+	assert.Error(t, err)
+}
+
+func TestS256Challenge(t *testing.T) {
+	// Contoh dari RFC 7636 lampiran B.
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", oidc.S256Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
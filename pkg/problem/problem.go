@@ -0,0 +1,119 @@
+// Package problem menulis respons error dalam format RFC 7807
+// (application/problem+json). Klien membaca field code yang stabil, bukan
+// teks detail yang bisa berubah.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/danisasmita/customer-search/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+const ContentType = "application/problem+json"
+
+// RequestIDKey adalah key gin.Context tempat middleware RequestID
+// menyimpan ID request.
+const RequestIDKey = "requestID"
+
+// FieldError menjelaskan satu field request yang tidak valid. Params
+// mengisi placeholder pesan terjemahan selain {field}.
+type FieldError struct {
+	Field   string      `json:"field"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Params  i18n.Params `json:"-"`
+}
+
+// Problem adalah isi respons error. Extensions ditambahkan sebagai member
+// tingkat atas, misalnya daftar role yang valid.
+type Problem struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail"`
+	Instance   string                 `json:"instance,omitempty"`
+	Code       string                 `json:"code"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	Errors     []FieldError           `json:"errors,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+func New(status int, code, detail string) *Problem {
+	return &Problem{Status: status, Code: code, Detail: detail}
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type plain Problem
+	body, err := json.Marshal(plain(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return body, err
+	}
+
+	merged := map[string]interface{}{}
+	for key, value := range p.Extensions {
+		merged[key] = value
+	}
+	// Member standar tidak boleh tertimpa extension.
+	var standard map[string]interface{}
+	if err := json.Unmarshal(body, &standard); err != nil {
+		return nil, err
+	}
+	for key, value := range standard {
+		merged[key] = value
+	}
+	return json.Marshal(merged)
+}
+
+// Write melengkapi type, title, instance, dan request ID lalu menulis p.
+// Detail dan pesan per field diterjemahkan ke bahasa request berdasarkan
+// kodenya; kode yang tidak ada di katalog memakai teks aslinya.
+func Write(c *gin.Context, p *Problem) {
+	localize(p, c.GetString(i18n.ContextKey))
+
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	if p.Instance == "" && c.Request != nil {
+		p.Instance = c.Request.URL.Path
+	}
+	if p.RequestID == "" {
+		p.RequestID = c.GetString(RequestIDKey)
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(p.Status, ContentType, body)
+}
+
+func localize(p *Problem, lang string) {
+	if text, ok := i18n.Lookup(lang, p.Code); ok {
+		p.Detail = text
+	}
+	for i := range p.Errors {
+		field := &p.Errors[i]
+		text, ok := i18n.Lookup(lang, field.Code)
+		if !ok {
+			continue
+		}
+		params := i18n.Params{"field": field.Field}
+		for name, value := range field.Params {
+			params[name] = value
+		}
+		field.Message = i18n.Format(text, params)
+	}
+}
+
+// Abort menulis problem dan menghentikan handler berikutnya. Dipakai oleh
+// middleware.
+func Abort(c *gin.Context, status int, code, detail string) {
+	Write(c, New(status, code, detail))
+	c.Abort()
+}
@@ -0,0 +1,93 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danisasmita/customer-search/pkg/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/admin/users/:id", func(c *gin.Context) {
+		c.Set(RequestIDKey, "req-1")
+		p := New(http.StatusBadRequest, "invalid_role", "invalid role")
+		p.Errors = []FieldError{{Field: "role", Code: "invalid_role", Message: "invalid role"}}
+		p.Extensions = map[string]interface{}{"roles": []string{"admin", "agent"}, "status": 999}
+		Write(c, p)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users/7", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "invalid role",
+		"instance": "/admin/users/7",
+		"code": "invalid_role",
+		"request_id": "req-1",
+		"errors": [{"field": "role", "code": "invalid_role", "message": "invalid role"}],
+		"roles": ["admin", "agent"]
+	}`, w.Body.String())
+}
+
+func TestAbort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	called := false
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		Abort(c, http.StatusUnauthorized, "unauthorized", "unauthorized")
+	})
+	r.GET("/customers", func(c *gin.Context) {
+		called = true
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/customers", nil))
+
+	var body Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, http.StatusUnauthorized, body.Status)
+	assert.Equal(t, "unauthorized", body.Code)
+	assert.False(t, called)
+}
+
+func TestWriteLocalized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.POST("/register", func(c *gin.Context) {
+		c.Set(i18n.ContextKey, i18n.Indonesian)
+		p := New(http.StatusBadRequest, "password_policy", "password does not meet the password policy")
+		p.Errors = []FieldError{
+			{Field: "password", Code: "min_length", Message: "password must be at least 10 characters", Params: i18n.Params{"min": 10}},
+			{Field: "username", Code: "required", Message: "username is required"},
+			{Field: "name", Code: "custom_rule", Message: "kept as is"},
+		}
+		Write(c, p)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/register", nil))
+
+	var body Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "password_policy", body.Code)
+	assert.Equal(t, "password tidak memenuhi kebijakan password", body.Detail)
+	assert.Equal(t, []FieldError{
+		{Field: "password", Code: "min_length", Message: "password minimal 10 karakter"},
+		{Field: "username", Code: "required", Message: "username wajib diisi"},
+		{Field: "name", Code: "custom_rule", Message: "kept as is"},
+	}, body.Errors)
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// LocalURL mengembalikan URL path di server cfg yang bisa dipanggil dari
+// container yang sama. Skema mengikuti TLSEnabled, dan host kosong atau
+// wildcard diganti localhost.
+func LocalURL(cfg Config, path string) (string, error) {
+	host, port, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return "", err
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	scheme := "http"
+	if cfg.TLSEnabled() {
+		scheme = "https"
+	}
+	return scheme + "://" + net.JoinHostPort(host, port) + path, nil
+}
+
+// Probe memanggil GET url dan mengembalikan error jika status bukan 2xx.
+// Sertifikat tidak diverifikasi karena probe ditujukan ke server sendiri,
+// yang sertifikatnya tidak diterbitkan untuk localhost.
+func Probe(ctx context.Context, url string) error {
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalURL(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"port only", Config{Addr: ":8080"}, "http://localhost:8080/readyz"},
+		{"wildcard IPv4", Config{Addr: "0.0.0.0:8080"}, "http://localhost:8080/readyz"},
+		{"wildcard IPv6", Config{Addr: "[::]:8080"}, "http://localhost:8080/readyz"},
+		{"specific host", Config{Addr: "10.0.0.5:8080"}, "http://10.0.0.5:8080/readyz"},
+		{"TLS", Config{Addr: ":8443", TLSCertFile: "cert.pem", TLSKeyFile: "key.pem"}, "https://localhost:8443/readyz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LocalURL(tt.cfg, "/readyz")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := LocalURL(Config{Addr: "8080"}, "/readyz")
+	assert.Error(t, err)
+}
+
+func TestProbe(t *testing.T) {
+	certFile, keyFile := writeCertificate(t, t.TempDir(), "customer-search.example")
+	certs, err := LoadCertificate(certFile, keyFile)
+	require.NoError(t, err)
+
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	srv := New(Config{}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	srv.TLSConfig = &tls.Config{GetCertificate: certs.GetCertificate}
+	addr, cancel, done := startServer(t, srv, time.Second)
+
+	url, err := LocalURL(Config{Addr: addr, TLSCertFile: certFile, TLSKeyFile: keyFile}, "/readyz")
+	require.NoError(t, err)
+	assert.NoError(t, Probe(context.Background(), url))
+
+	status.Store(http.StatusServiceUnavailable)
+	assert.ErrorContains(t, Probe(context.Background(), url), "503")
+
+	// Probe HTTP biasa ke listener TLS gagal; inilah yang terjadi pada
+	// HEALTHCHECK lama saat TLS aktif.
+	assert.Error(t, Probe(context.Background(), "http://"+addr+"/readyz"))
+
+	cancel()
+	assert.NoError(t, <-done)
+}
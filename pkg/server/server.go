@@ -0,0 +1,106 @@
+// Package server menjalankan http.Server dengan timeout, graceful shutdown,
+// dan TLS opsional.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Config mengatur listener dan batas waktu server. TLS aktif jika
+// TLSCertFile atau TLSKeyFile diisi; keduanya wajib ada.
+type Config struct {
+	Addr              string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout adalah batas waktu menunggu request yang sedang
+	// berjalan setelah sinyal berhenti diterima.
+	ShutdownTimeout time.Duration
+
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSReloadInterval adalah jeda pemeriksaan perubahan file sertifikat.
+	TLSReloadInterval time.Duration
+}
+
+// TLSEnabled bernilai true jika sertifikat atau key diisi.
+func (c Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" || c.TLSKeyFile != ""
+}
+
+func New(cfg Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// Run melayani handler di cfg.Addr sampai ctx selesai, lalu menunggu
+// request yang sedang berjalan paling lama ShutdownTimeout.
+func Run(ctx context.Context, cfg Config, handler http.Handler) error {
+	srv := New(cfg, handler)
+
+	if cfg.TLSEnabled() {
+		certs, err := LoadCertificate(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return err
+		}
+		go certs.Run(ctx, cfg.TLSReloadInterval)
+		srv.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certs.GetCertificate,
+		}
+	}
+
+	ln, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		return err
+	}
+	return Serve(ctx, srv, ln, cfg.ShutdownTimeout)
+}
+
+// Serve melayani srv di ln sampai ctx selesai. Setelah itu listener
+// ditutup agar tidak ada koneksi baru, dan request yang sedang berjalan
+// diberi waktu sampai shutdownTimeout sebelum koneksinya diputus paksa.
+// srv.TLSConfig yang diisi berarti ln dilayani dengan TLS.
+func Serve(ctx context.Context, srv *http.Server, ln net.Listener, shutdownTimeout time.Duration) error {
+	errc := make(chan error, 1)
+	go func() {
+		if srv.TLSConfig != nil {
+			errc <- srv.ServeTLS(ln, "", "")
+			return
+		}
+		errc <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, waiting for in-flight requests", "timeout", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return fmt.Errorf("shutdown: %w", err)
+	}
+
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startServer menjalankan Serve di listener acak. started ditutup saat
+// handler mulai berjalan; handler selesai saat release ditutup.
+func startServer(t *testing.T, srv *http.Server, shutdownTimeout time.Duration) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, srv, ln, shutdownTimeout) }()
+	return ln.Addr().String(), cancel, done
+}
+
+func blockingHandler(started, release chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, "done")
+	})
+}
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	addr, cancel, done := startServer(t, New(Config{}, blockingHandler(started, release)), 5*time.Second)
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr)
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{body: string(body), err: err}
+	}()
+
+	<-started
+	cancel()
+
+	// Listener ditutup sehingga koneksi baru ditolak selama drain.
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+
+	close(release)
+	res := <-responses
+	assert.NoError(t, res.err)
+	assert.Equal(t, "done", res.body)
+	assert.NoError(t, <-done)
+}
+
+func TestServeShutdownDeadline(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	addr, cancel, done := startServer(t, New(Config{}, blockingHandler(started, release)), 50*time.Millisecond)
+
+	go http.Get("http://" + addr)
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve did not return after shutdown timeout")
+	}
+}
+
+func TestServeListenerError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ln.Close()
+
+	err = Serve(context.Background(), New(Config{}, http.NotFoundHandler()), ln, time.Second)
+	assert.Error(t, err)
+}
+
+func TestServeTLS(t *testing.T) {
+	certFile, keyFile := writeCertificate(t, t.TempDir(), "first.example")
+	certs, err := LoadCertificate(certFile, keyFile)
+	require.NoError(t, err)
+
+	srv := New(Config{}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Proto)
+	}))
+	srv.TLSConfig = &tls.Config{GetCertificate: certs.GetCertificate}
+	addr, cancel, done := startServer(t, srv, time.Second)
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	require.NoError(t, err)
+	assert.Equal(t, "first.example", conn.ConnectionState().PeerCertificates[0].Subject.CommonName)
+	conn.Close()
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestConfigTLSEnabled(t *testing.T) {
+	assert.False(t, Config{}.TLSEnabled())
+	assert.True(t, Config{TLSCertFile: "cert.pem"}.TLSEnabled())
+	assert.True(t, Config{TLSCertFile: "cert.pem", TLSKeyFile: "key.pem"}.TLSEnabled())
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCertificate menulis sertifikat self-signed dengan CN commonName ke
+// dir/cert.pem dan dir/key.pem.
+func writeCertificate(t *testing.T, dir, commonName string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+// touch memajukan waktu modifikasi agar perubahan terdeteksi walaupun
+// file ditulis dalam detik yang sama.
+func touch(t *testing.T, files ...string) {
+	t.Helper()
+	later := time.Now().Add(time.Minute)
+	for _, file := range files {
+		require.NoError(t, os.Chtimes(file, later, later))
+	}
+}
+
+func commonName(t *testing.T, r *CertReloader) string {
+	t.Helper()
+	cert, err := r.GetCertificate(nil)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestLoadCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir, "first.example")
+
+	r, err := LoadCertificate(certFile, keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, "first.example", commonName(t, r))
+
+	_, err = LoadCertificate(certFile, filepath.Join(dir, "missing.pem"))
+	assert.Error(t, err)
+	_, err = LoadCertificate(certFile, "")
+	assert.Error(t, err)
+}
+
+func TestCertReloaderReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir, "first.example")
+	r, err := LoadCertificate(certFile, keyFile)
+	require.NoError(t, err)
+
+	t.Run("unchanged files are not reloaded", func(t *testing.T) {
+		reloaded, err := r.Reload()
+		assert.NoError(t, err)
+		assert.False(t, reloaded)
+	})
+
+	t.Run("rotated certificate is picked up", func(t *testing.T) {
+		writeCertificate(t, dir, "second.example")
+		touch(t, certFile, keyFile)
+
+		reloaded, err := r.Reload()
+		assert.NoError(t, err)
+		assert.True(t, reloaded)
+		assert.Equal(t, "second.example", commonName(t, r))
+	})
+
+	t.Run("invalid pair keeps previous certificate", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(keyFile, []byte("not a key"), 0o600))
+		touch(t, keyFile)
+
+		reloaded, err := r.Reload()
+		assert.Error(t, err)
+		assert.False(t, reloaded)
+		assert.Equal(t, "second.example", commonName(t, r))
+	})
+}
@@ -0,0 +1,90 @@
+// Package tenant membatasi query GORM ke data milik satu cabang. Model
+// yang dimiliki cabang cukup punya kolom tenant_id dan method TenantOwned;
+// callback di package ini menambahkan filter tenant ke setiap query model
+// itu, termasuk query preload.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrMissingScope dikembalikan saat model milik cabang di-query tanpa
+// Scope di context. Query tanpa scope ditolak, bukan dibiarkan melihat
+// semua cabang.
+var ErrMissingScope = errors.New("tenant scope is required")
+
+const column = "tenant_id"
+
+// Owned ditandai oleh model yang datanya dimiliki satu cabang.
+type Owned interface {
+	TenantOwned()
+}
+
+// Scope dengan Global true boleh melihat semua cabang.
+type Scope struct {
+	TenantID string
+	Global   bool
+}
+
+type contextKey struct{}
+
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, contextKey{}, scope)
+}
+
+func FromContext(ctx context.Context) (Scope, bool) {
+	if ctx == nil {
+		return Scope{}, false
+	}
+	scope, ok := ctx.Value(contextKey{}).(Scope)
+	return scope, ok
+}
+
+// Global dipakai untuk proses internal seperti seeding yang memang
+// bekerja lintas cabang.
+func Global(ctx context.Context) context.Context {
+	return WithScope(ctx, Scope{Global: true})
+}
+
+// Register memasang callback sebelum query, row, update, dan delete.
+func Register(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", applyScope); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", applyScope); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", applyScope); err != nil {
+		return err
+	}
+	return callbacks.Delete().Before("gorm:delete").Register("tenant:delete", applyScope)
+}
+
+func applyScope(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	if _, owned := reflect.New(db.Statement.Schema.ModelType).Interface().(Owned); !owned {
+		return
+	}
+
+	scope, ok := FromContext(db.Statement.Context)
+	if !ok || (!scope.Global && scope.TenantID == "") {
+		db.AddError(ErrMissingScope)
+		return
+	}
+	if scope.Global {
+		return
+	}
+
+	// Nama tabel ditulis eksplisit agar tetap benar pada query dengan JOIN.
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Value: scope.TenantID},
+	}})
+}
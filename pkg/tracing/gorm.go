@@ -0,0 +1,14 @@
+package tracing
+
+import (
+	"gorm.io/gorm"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
+)
+
+// GORMPlugin membuat span untuk setiap statement GORM sebagai anak span di
+// context query. SQL dicatat dengan placeholder, tanpa nilai parameter.
+// Statistik pool sudah diekspor lewat Prometheus, jadi metrik plugin
+// dimatikan. Dipasang dengan db.Use(tracing.GORMPlugin()).
+func GORMPlugin() gorm.Plugin {
+	return gormtracing.NewPlugin(gormtracing.WithoutMetrics(), gormtracing.WithoutQueryVariables())
+}
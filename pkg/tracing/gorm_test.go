@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type owner struct {
+	ID      uint
+	Name    string
+	Gadgets []gadget
+}
+
+type gadget struct {
+	ID      uint
+	OwnerID uint
+	Label   string
+}
+
+func TestGORMPlugin(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := NewTracerProvider("customer-search", exporter)
+	setGlobalProvider(t, provider)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(GORMPlugin()))
+	require.NoError(t, db.AutoMigrate(&owner{}, &gadget{}))
+	require.NoError(t, db.Create(&owner{Name: "secret-name", Gadgets: []gadget{{Label: "a"}}}).Error)
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	exporter.Reset()
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "search")
+	var owners []owner
+	require.NoError(t, db.WithContext(ctx).Preload("Gadgets").Where("name = ?", "secret-name").Find(&owners).Error)
+	err = db.WithContext(ctx).First(&owner{}, 999).Error
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	parent.End()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	var tables []string
+	for _, span := range spans[:len(spans)-1] {
+		assert.Equal(t, "gorm.Query", span.Name)
+		for _, attr := range span.Attributes {
+			if attr.Key == "db.sql.table" {
+				tables = append(tables, attr.Value.AsString())
+			}
+		}
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+		assert.Equal(t, parent.SpanContext().TraceID(), span.SpanContext.TraceID())
+		assert.Empty(t, span.Events, span.Name)
+		for _, attr := range span.Attributes {
+			assert.NotContains(t, attr.Value.Emit(), "secret-name")
+		}
+	}
+	assert.ElementsMatch(t, []string{"owners", "gadgets", "owners"}, tables)
+}
+
+// setGlobalProvider memasang provider sebagai provider global selama test.
+func setGlobalProvider(t *testing.T, provider trace.TracerProvider) {
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+}
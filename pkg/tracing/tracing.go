@@ -0,0 +1,163 @@
+// Package tracing memasang OpenTelemetry SDK: tracer provider global,
+// propagator W3C Trace Context, dan exporter OTLP/HTTP, stdout, atau file
+// JSON lines. Tanpa exporter, span tetap dibuat agar trace context
+// diteruskan dan trace_id tercatat di log, tetapi tidak diekspor.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+// Config memilih exporter. Exporter kosong atau "none" berarti span tidak
+// diekspor.
+type Config struct {
+	Exporter    string
+	ServiceName string
+	// File dipakai exporter file, satu span JSON per baris.
+	File string
+	// OTLPEndpoint adalah base URL collector, misalnya
+	// http://localhost:4318; span dikirim ke /v1/traces.
+	OTLPEndpoint string
+	OTLPHeaders  map[string]string
+}
+
+// Setup membuat tracer provider dari cfg lalu memasangnya sebagai provider
+// dan propagator global. Provider wajib ditutup dengan Shutdown agar span
+// yang masih di antrean terkirim.
+func Setup(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
+	exporter, err := NewExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	provider := NewTracerProvider(cfg.ServiceName, exporter)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider, nil
+}
+
+// NewTracerProvider membuat provider yang mengirim span ke exporter per
+// batch. exporter nil berarti span hanya dibuat, tidak diekspor.
+func NewTracerProvider(serviceName string, exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+		sdktrace.WithSpanProcessor(routeOnlyProcessor{}),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// NewExporter membuat exporter berdasarkan cfg.Exporter. Exporter nil
+// tanpa error berarti tracing tidak diekspor.
+func NewExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterFile:
+		if cfg.File == "" {
+			return nil, fmt.Errorf("tracing file path is required")
+		}
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return fileExporter{SpanExporter: exporter, file: file}, nil
+	case ExporterOTLP:
+		if cfg.OTLPEndpoint == "" {
+			return nil, fmt.Errorf("OTLP endpoint is required")
+		}
+		return otlptracehttp.New(ctx,
+			otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.OTLPEndpoint, "/")+"/v1/traces"),
+			otlptracehttp.WithHeaders(cfg.OTLPHeaders),
+		)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+}
+
+// RecordError mencatat err di span dan menandai span gagal. err nil
+// diabaikan.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// fileExporter menutup file setelah exporter stdout selesai.
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e fileExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// pathAttributes adalah atribut otelgin yang berisi path asli: url.path
+// untuk semconv HTTP yang stabil dan http.target, termasuk query string,
+// untuk semconv lama yang masih menjadi default.
+var pathAttributes = []attribute.Key{semconv.URLPathKey, "http.target"}
+
+// routeOnlyProcessor mengganti path asli di span server dengan template
+// route karena path dan query string bisa berisi data nasabah.
+type routeOnlyProcessor struct{}
+
+func (routeOnlyProcessor) OnStart(_ context.Context, span sdktrace.ReadWriteSpan) {
+	var found []attribute.Key
+	var route string
+	for _, attr := range span.Attributes() {
+		if attr.Key == semconv.HTTPRouteKey {
+			route = attr.Value.AsString()
+		} else if slices.Contains(pathAttributes, attr.Key) {
+			found = append(found, attr.Key)
+		}
+	}
+	for _, key := range found {
+		span.SetAttributes(key.String(route))
+	}
+}
+
+func (routeOnlyProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (routeOnlyProcessor) Shutdown(context.Context) error   { return nil }
+func (routeOnlyProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewExporter(t *testing.T) {
+	ctx := context.Background()
+	for _, kind := range []string{"", ExporterNone} {
+		exporter, err := NewExporter(ctx, Config{Exporter: kind})
+		assert.NoError(t, err)
+		assert.Nil(t, exporter)
+	}
+
+	for _, cfg := range []Config{
+		{Exporter: ExporterStdout},
+		{Exporter: ExporterOTLP, OTLPEndpoint: "http://localhost:4318/", OTLPHeaders: map[string]string{"x-api-key": "k"}},
+	} {
+		exporter, err := NewExporter(ctx, cfg)
+		assert.NoError(t, err, cfg.Exporter)
+		assert.NotNil(t, exporter, cfg.Exporter)
+	}
+
+	_, err := NewExporter(ctx, Config{Exporter: ExporterFile})
+	assert.Error(t, err)
+	_, err = NewExporter(ctx, Config{Exporter: ExporterOTLP})
+	assert.Error(t, err)
+	_, err = NewExporter(ctx, Config{Exporter: "jaeger"})
+	assert.Error(t, err)
+}
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	exporter, err := NewExporter(context.Background(), Config{Exporter: ExporterFile, File: path})
+	require.NoError(t, err)
+	provider := NewTracerProvider("customer-search", exporter)
+
+	for _, name := range []string{"first", "second"} {
+		_, span := provider.Tracer("test").Start(context.Background(), name)
+		span.End()
+	}
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(content, []byte("\n")))
+	assert.Contains(t, string(content), `"Value":"customer-search"`)
+}
+
+func TestRouteOnlyProcessor(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := NewTracerProvider("customer-search", exporter)
+
+	_, span := provider.Tracer("test").Start(context.Background(), "/customers/:id", trace.WithAttributes(
+		semconv.URLPath("/customers/12345"),
+		attribute.String("http.target", "/customers/12345?name=jane"),
+		semconv.HTTPRoute("/customers/:id"),
+	))
+	span.End()
+	_, span = provider.Tracer("test").Start(context.Background(), "internal")
+	span.End()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Subset(t, spans[0].Attributes, []attribute.KeyValue{
+		attribute.String("url.path", "/customers/:id"),
+		attribute.String("http.target", "/customers/:id"),
+	})
+	assert.Empty(t, spans[1].Attributes)
+}
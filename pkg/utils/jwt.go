@@ -1,36 +1,151 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
-var jwtKey = []byte("your-secret-key")
+var tokenTTL = 15 * time.Minute
 
+var (
+	keysMu     sync.RWMutex
+	signingKey = NewHMACKey("", []byte("your-secret-key"))
+	verifyKeys = map[string]*SigningKey{}
+	keysLoaded bool
+)
+
+// PurposeMFA menandai token tantangan MFA yang hanya bisa ditukar di
+// langkah kedua login, bukan dipakai sebagai access token.
+const PurposeMFA = "mfa"
+
+// Claims.Id berisi jti yang dipakai untuk mencabut token sebelum kedaluwarsa.
+// TenantID adalah cabang user saat token diterbitkan. Token impersonation
+// berisi sub user target dan act admin yang memakainya (RFC 8693).
 type Claims struct {
-	UserID uint `json:"user_id"`
+	UserID   uint   `json:"user_id"`
+	TenantID string `json:"tenant_id,omitempty"`
+	Purpose  string `json:"purpose,omitempty"`
+	Act      *Actor `json:"act,omitempty"`
 	jwt.StandardClaims
 }
 
-func GenerateJWT(userID uint) (string, error) {
+// Actor adalah pihak yang bertindak atas nama subject token.
+type Actor struct {
+	Subject string `json:"sub"`
+}
+
+// ActorID mengembalikan ID admin pada token impersonation, atau 0.
+func (c *Claims) ActorID() uint {
+	if c.Act == nil {
+		return 0
+	}
+	id, err := strconv.ParseUint(c.Act.Subject, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
+
+// SetTokenTTL mengatur masa berlaku access token yang dibuat GenerateJWT.
+func SetTokenTTL(ttl time.Duration) {
+	tokenTTL = ttl
+}
+
+func TokenTTL() time.Duration {
+	return tokenTTL
+}
+
+// SetSigningKeys mengganti kunci aktif untuk menandatangani token. Kunci
+// tambahan hanya dipakai untuk verifikasi, misalnya kunci lama selama masa
+// rotasi sampai semua token lama kedaluwarsa.
+func SetSigningKeys(active *SigningKey, verifyOnly ...*SigningKey) {
+	keys := map[string]*SigningKey{active.ID: active}
+	for _, key := range verifyOnly {
+		keys[key.ID] = key
+	}
+
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	signingKey = active
+	verifyKeys = keys
+	keysLoaded = true
+}
+
+// SigningKeysLoaded bernilai false selama token masih ditandatangani kunci
+// bawaan, yaitu sebelum ConfigureSigning atau SetSigningKeys dipanggil.
+func SigningKeysLoaded() bool {
+	keysMu.RLock()
+	defer keysMu.RUnlock()
+	return keysLoaded
+}
+
+func GenerateJWT(userID uint, tenantID string) (string, error) {
+	return generateToken(userID, tenantID, "", tokenTTL)
+}
+
+// GenerateMFAChallenge membuat token berumur pendek yang membuktikan
+// password sudah benar dan menunggu kode MFA.
+func GenerateMFAChallenge(userID uint, ttl time.Duration) (string, error) {
+	return generateToken(userID, "", PurposeMFA, ttl)
+}
+
+// GenerateImpersonationJWT membuat access token atas nama userID untuk
+// dipakai adminID. Token ini tidak punya refresh token.
+func GenerateImpersonationJWT(adminID, userID uint, tenantID string, ttl time.Duration) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
-		},
+		UserID:   userID,
+		TenantID: tenantID,
+		Act:      &Actor{Subject: strconv.FormatUint(uint64(adminID), 10)},
+	}
+	claims.Subject = strconv.FormatUint(uint64(userID), 10)
+	return signClaims(claims, ttl)
+}
+
+func generateToken(userID uint, tenantID, purpose string, ttl time.Duration) (string, error) {
+	return signClaims(&Claims{UserID: userID, TenantID: tenantID, Purpose: purpose}, ttl)
+}
+
+func signClaims(claims *Claims, ttl time.Duration) (string, error) {
+	jti, err := newJTI()
+	if err != nil {
+		return "", err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtKey)
+	now := time.Now()
+	claims.Id = jti
+	claims.IssuedAt = now.Unix()
+	claims.ExpiresAt = now.Add(ttl).Unix()
+
+	keysMu.RLock()
+	key := signingKey
+	keysMu.RUnlock()
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	return token.SignedString(key.Private)
 }
 
+// ValidateJWT hanya menerima access token; token tantangan MFA ditolak.
 func ValidateJWT(tokenString string) (*Claims, error) {
+	return validateToken(tokenString, "")
+}
+
+func ValidateMFAChallenge(tokenString string) (*Claims, error) {
+	return validateToken(tokenString, PurposeMFA)
+}
+
+func validateToken(tokenString, purpose string) (*Claims, error) {
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, verificationKey)
 
 	if err != nil {
 		return nil, err
@@ -40,5 +155,40 @@ func ValidateJWT(tokenString string) (*Claims, error) {
 		return nil, errors.New("invalid token")
 	}
 
+	if claims.Purpose != purpose {
+		return nil, errors.New("unexpected token purpose")
+	}
+
 	return claims, nil
 }
+
+// verificationKey memilih kunci berdasarkan header kid. Token tanpa kid
+// diverifikasi dengan kunci aktif. Algoritma token harus sama dengan
+// algoritma kunci agar token HS256 tidak bisa memakai kunci publik RSA
+// sebagai secret.
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	keysMu.RLock()
+	defer keysMu.RUnlock()
+
+	key := signingKey
+	if kid, ok := token.Header["kid"].(string); ok {
+		found, exists := verifyKeys[kid]
+		if !exists {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		key = found
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return key.Public, nil
+}
+
+func newJTI() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// SigningKey adalah satu kunci JWT. Private kosong untuk kunci yang hanya
+// dipakai verifikasi.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private interface{}
+	Public  interface{}
+}
+
+// SigningConfig menggambarkan kunci yang dipakai aplikasi. VerificationKeys
+// berisi kid -> path file PEM kunci publik yang masih diterima.
+type SigningConfig struct {
+	Algorithm        string
+	KeyID            string
+	Secret           string
+	PrivateKeyFile   string
+	VerificationKeys map[string]string
+}
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewHMACKey(kid string, secret []byte) *SigningKey {
+	return &SigningKey{ID: kid, Method: jwt.SigningMethodHS256, Private: secret, Public: secret}
+}
+
+// ConfigureSigning memuat kunci sesuai cfg lalu memasangnya lewat
+// SetSigningKeys.
+func ConfigureSigning(cfg SigningConfig) error {
+	var active *SigningKey
+
+	switch cfg.Algorithm {
+	case "", AlgorithmHS256:
+		if cfg.Secret == "" {
+			return errors.New("JWT secret is required for HS256")
+		}
+		active = NewHMACKey(cfg.KeyID, []byte(cfg.Secret))
+	case AlgorithmRS256, AlgorithmEdDSA:
+		data, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return fmt.Errorf("read private key: %w", err)
+		}
+		active, err = ParsePrivateKeyPEM(cfg.KeyID, data)
+		if err != nil {
+			return err
+		}
+		if active.Method.Alg() != cfg.Algorithm {
+			return fmt.Errorf("private key is %s, expected %s", active.Method.Alg(), cfg.Algorithm)
+		}
+	default:
+		return fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
+	}
+
+	var verifyOnly []*SigningKey
+	for kid, path := range cfg.VerificationKeys {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read verification key %s: %w", kid, err)
+		}
+		key, err := ParsePublicKeyPEM(kid, data)
+		if err != nil {
+			return err
+		}
+		verifyOnly = append(verifyOnly, key)
+	}
+
+	SetSigningKeys(active, verifyOnly...)
+	return nil
+}
+
+// ParsePrivateKeyPEM menerima kunci RSA (PKCS#1 atau PKCS#8) atau Ed25519
+// (PKCS#8).
+func ParsePrivateKeyPEM(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodRS256, Private: key, Public: &key.PublicKey}, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodRS256, Private: key, Public: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: kid, Method: SigningMethodEdDSA, Private: key, Public: key.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+}
+
+func ParsePublicKeyPEM(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM public key")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PublicKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodRS256, Public: key}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{ID: kid, Method: SigningMethodEdDSA, Public: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", parsed)
+	}
+}
+
+// JWKS mengembalikan kunci publik yang sedang diterima. Kunci HMAC tidak
+// pernah dipublikasikan.
+func JWKS() JWKSet {
+	keysMu.RLock()
+	defer keysMu.RUnlock()
+
+	set := JWKSet{Keys: []JWK{}}
+	for kid, key := range verifyKeys {
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     kid,
+				Use:       "sig",
+				Algorithm: AlgorithmRS256,
+				N:         base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     kid,
+				Use:       "sig",
+				Algorithm: AlgorithmEdDSA,
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
+
+// jwt-go v3 belum punya EdDSA, jadi metode ini didaftarkan sendiri.
+type signingMethodEdDSA struct{}
+
+var SigningMethodEdDSA jwt.SigningMethod = &signingMethodEdDSA{}
+
+func init() {
+	jwt.RegisterSigningMethod(AlgorithmEdDSA, func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (m *signingMethodEdDSA) Alg() string {
+	return AlgorithmEdDSA
+}
+
+func (m *signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	return jwt.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
+
+func (m *signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return jwt.ErrSignatureInvalid
+	}
+	return nil
+}
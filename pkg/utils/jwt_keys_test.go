@@ -0,0 +1,179 @@
+package utils_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restoreDefaultKey mengembalikan kunci HMAC bawaan agar test lain tidak
+// terpengaruh.
+func restoreDefaultKey(t *testing.T) {
+	t.Cleanup(func() {
+		utils.SetSigningKeys(utils.NewHMACKey("", []byte("your-secret-key")))
+	})
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func generateRSAFiles(t *testing.T, dir, prefix string) (privatePath, publicPath string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	privatePath = writePEM(t, dir, prefix+".pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+	publicPath = writePEM(t, dir, prefix+".pub.pem", "PUBLIC KEY", publicDER)
+	return privatePath, publicPath
+}
+
+func tokenKeyID(t *testing.T, token string) string {
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, &utils.Claims{})
+	require.NoError(t, err)
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestConfigureSigningHMAC(t *testing.T) {
+	restoreDefaultKey(t)
+
+	err := utils.ConfigureSigning(utils.SigningConfig{Algorithm: utils.AlgorithmHS256, KeyID: "hmac-1", Secret: "from-config"})
+	require.NoError(t, err)
+	assert.True(t, utils.SigningKeysLoaded())
+
+	token, err := utils.GenerateJWT(1, "")
+	require.NoError(t, err)
+	assert.Equal(t, "hmac-1", tokenKeyID(t, token))
+
+	claims, err := utils.ValidateJWT(token)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), claims.UserID)
+
+	// Secret bawaan tidak lagi diterima
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &utils.Claims{UserID: 1})
+	signed, _ := forged.SignedString([]byte("your-secret-key"))
+	_, err = utils.ValidateJWT(signed)
+	assert.Error(t, err)
+
+	// JWKS tidak boleh membocorkan secret HMAC
+	assert.Empty(t, utils.JWKS().Keys)
+}
+
+func TestConfigureSigningHMACRequiresSecret(t *testing.T) {
+	restoreDefaultKey(t)
+
+	err := utils.ConfigureSigning(utils.SigningConfig{Algorithm: utils.AlgorithmHS256})
+	assert.Error(t, err)
+}
+
+func TestConfigureSigningRS256Rotation(t *testing.T) {
+	restoreDefaultKey(t)
+	dir := t.TempDir()
+	oldPrivate, oldPublic := generateRSAFiles(t, dir, "old")
+	newPrivate, _ := generateRSAFiles(t, dir, "new")
+
+	require.NoError(t, utils.ConfigureSigning(utils.SigningConfig{
+		Algorithm:      utils.AlgorithmRS256,
+		KeyID:          "2024-01",
+		PrivateKeyFile: oldPrivate,
+	}))
+	oldToken, err := utils.GenerateJWT(1, "")
+	require.NoError(t, err)
+
+	// Rotasi: kunci baru aktif, kunci lama tetap diterima untuk verifikasi
+	require.NoError(t, utils.ConfigureSigning(utils.SigningConfig{
+		Algorithm:        utils.AlgorithmRS256,
+		KeyID:            "2024-02",
+		PrivateKeyFile:   newPrivate,
+		VerificationKeys: map[string]string{"2024-01": oldPublic},
+	}))
+	newToken, err := utils.GenerateJWT(2, "")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-02", tokenKeyID(t, newToken))
+
+	_, err = utils.ValidateJWT(oldToken)
+	assert.NoError(t, err)
+	_, err = utils.ValidateJWT(newToken)
+	assert.NoError(t, err)
+
+	jwks := utils.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "2024-01", jwks.Keys[0].KeyID)
+	assert.Equal(t, "RSA", jwks.Keys[0].KeyType)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+
+	// Setelah kunci lama dilepas, token lama ditolak
+	require.NoError(t, utils.ConfigureSigning(utils.SigningConfig{
+		Algorithm:      utils.AlgorithmRS256,
+		KeyID:          "2024-02",
+		PrivateKeyFile: newPrivate,
+	}))
+	_, err = utils.ValidateJWT(oldToken)
+	assert.Error(t, err)
+}
+
+func TestConfigureSigningEdDSA(t *testing.T) {
+	restoreDefaultKey(t)
+	dir := t.TempDir()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	path := writePEM(t, dir, "ed25519.pem", "PRIVATE KEY", der)
+
+	require.NoError(t, utils.ConfigureSigning(utils.SigningConfig{
+		Algorithm:      utils.AlgorithmEdDSA,
+		KeyID:          "ed-1",
+		PrivateKeyFile: path,
+	}))
+
+	token, err := utils.GenerateJWT(5, "")
+	require.NoError(t, err)
+	claims, err := utils.ValidateJWT(token)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), claims.UserID)
+
+	jwks := utils.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+}
+
+func TestValidateJWTRejectsAlgorithmConfusion(t *testing.T) {
+	restoreDefaultKey(t)
+	dir := t.TempDir()
+	private, public := generateRSAFiles(t, dir, "rsa")
+
+	require.NoError(t, utils.ConfigureSigning(utils.SigningConfig{
+		Algorithm:      utils.AlgorithmRS256,
+		KeyID:          "rsa-1",
+		PrivateKeyFile: private,
+	}))
+
+	// Penyerang memakai kunci publik sebagai secret HS256
+	publicPEM, err := os.ReadFile(public)
+	require.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &utils.Claims{UserID: 1})
+	forged.Header["kid"] = "rsa-1"
+	signed, err := forged.SignedString(publicPEM)
+	require.NoError(t, err)
+
+	_, err = utils.ValidateJWT(signed)
+	assert.Error(t, err)
+}
@@ -11,14 +11,14 @@ import (
 
 func TestGenerateJWT(t *testing.T) {
 	userID := uint(123)
-	token, err := utils.GenerateJWT(userID)
+	token, err := utils.GenerateJWT(userID, "")
 	assert.NoError(t, err, "GenerateJWT should not return an error")
 	assert.NotEmpty(t, token, "Generated token should not be empty")
 }
 
 func TestValidateJWT(t *testing.T) {
 	userID := uint(123)
-	token, err := utils.GenerateJWT(userID)
+	token, err := utils.GenerateJWT(userID, "")
 	assert.NoError(t, err, "GenerateJWT should not return an error")
 	assert.NotEmpty(t, token, "Generated token should not be empty")
 
@@ -49,3 +49,39 @@ func TestValidateJWTExpiredToken(t *testing.T) {
 	assert.Error(t, err, "ValidateJWT should return an error for expired token")
 	assert.Nil(t, claims, "Claims should be nil for expired token")
 }
+
+func TestMFAChallengeIsNotAccessToken(t *testing.T) {
+	challenge, err := utils.GenerateMFAChallenge(123, time.Minute)
+	assert.NoError(t, err)
+
+	_, err = utils.ValidateJWT(challenge)
+	assert.Error(t, err, "MFA challenge must not be accepted as access token")
+
+	claims, err := utils.ValidateMFAChallenge(challenge)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(123), claims.UserID)
+
+	access, err := utils.GenerateJWT(123, "")
+	assert.NoError(t, err)
+	_, err = utils.ValidateMFAChallenge(access)
+	assert.Error(t, err)
+}
+
+func TestImpersonationJWT(t *testing.T) {
+	token, err := utils.GenerateImpersonationJWT(1, 42, "JKT", time.Minute)
+	assert.NoError(t, err)
+
+	claims, err := utils.ValidateJWT(token)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(42), claims.UserID)
+	assert.Equal(t, "42", claims.Subject)
+	assert.Equal(t, "JKT", claims.TenantID)
+	assert.Equal(t, uint(1), claims.ActorID())
+
+	access, err := utils.GenerateJWT(42, "JKT")
+	assert.NoError(t, err)
+	claims, err = utils.ValidateJWT(access)
+	assert.NoError(t, err)
+	assert.Nil(t, claims.Act)
+	assert.Zero(t, claims.ActorID())
+}
@@ -1,13 +1,200 @@
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	HashArgon2id = "argon2id"
+	HashBcrypt   = "bcrypt"
+)
+
+// Argon2Params mengikuti parameter di string PHC: m (KiB), t, dan p.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params memakai rekomendasi OWASP untuk Argon2id.
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// PasswordHasher membuat hash dalam format PHC
+// ($argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>) atau format bcrypt
+// ($2a$10$...). Verifikasi menerima keduanya apa pun algoritma aktifnya.
+type PasswordHasher struct {
+	Algorithm  string
+	Argon2     Argon2Params
+	BcryptCost int
+}
+
+var (
+	hasherMu       sync.RWMutex
+	passwordHasher = &PasswordHasher{Algorithm: HashArgon2id, Argon2: DefaultArgon2Params, BcryptCost: bcrypt.DefaultCost}
+
+	// dummyHash dibuat ulang saat hasher aktif diganti.
+	dummyMu     sync.Mutex
+	dummyHasher *PasswordHasher
+	dummyHash   string
+)
+
+func NewPasswordHasher(algorithm string, argon Argon2Params, bcryptCost int) (*PasswordHasher, error) {
+	switch algorithm {
+	case HashArgon2id:
+		if argon.Memory == 0 || argon.Iterations == 0 || argon.Parallelism == 0 {
+			return nil, errors.New("argon2id memory, iterations and parallelism must be positive")
+		}
+		if argon.SaltLength == 0 {
+			argon.SaltLength = DefaultArgon2Params.SaltLength
+		}
+		if argon.KeyLength == 0 {
+			argon.KeyLength = DefaultArgon2Params.KeyLength
+		}
+	case HashBcrypt:
+		if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", algorithm)
+	}
+	return &PasswordHasher{Algorithm: algorithm, Argon2: argon, BcryptCost: bcryptCost}, nil
+}
+
+// SetPasswordHasher mengganti hasher yang dipakai HashPassword dan
+// VerifyPassword.
+func SetPasswordHasher(hasher *PasswordHasher) {
+	hasherMu.Lock()
+	defer hasherMu.Unlock()
+	passwordHasher = hasher
+}
+
+func currentHasher() *PasswordHasher {
+	hasherMu.RLock()
+	defer hasherMu.RUnlock()
+	return passwordHasher
+}
 
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
+	return currentHasher().Hash(password)
 }
 
 func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	match, _ := currentHasher().Verify(password, hash)
+	return match
+}
+
+// DummyPasswordHash mengembalikan hash tetap yang dibuat dengan konfigurasi
+// aktif. Dipakai untuk verifikasi saat user tidak ditemukan agar waktu
+// respons sama dengan password salah dan username terdaftar tidak bocor.
+func DummyPasswordHash() string {
+	hasher := currentHasher()
+
+	dummyMu.Lock()
+	defer dummyMu.Unlock()
+	if dummyHasher != hasher {
+		hash, err := hasher.Hash("dummy-password")
+		if err != nil {
+			return ""
+		}
+		dummyHasher, dummyHash = hasher, hash
+	}
+	return dummyHash
+}
+
+// VerifyPassword juga melaporkan apakah hash perlu dibuat ulang karena
+// algoritma atau parameternya sudah tidak sama dengan konfigurasi aktif.
+func VerifyPassword(password, hash string) (match, needsRehash bool) {
+	return currentHasher().Verify(password, hash)
+}
+
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.Algorithm == HashBcrypt {
+		bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.BcryptCost)
+		return string(bytes), err
+	}
+
+	salt := make([]byte, h.Argon2.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Argon2.Iterations, h.Argon2.Memory, h.Argon2.Parallelism, h.Argon2.KeyLength)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		HashArgon2id, argon2.Version,
+		h.Argon2.Memory, h.Argon2.Iterations, h.Argon2.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *PasswordHasher) Verify(password, encoded string) (match, needsRehash bool) {
+	if strings.HasPrefix(encoded, "$"+HashArgon2id+"$") {
+		params, salt, key, err := decodeArgon2(encoded)
+		if err != nil {
+			return false, false
+		}
+		actual := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+		if subtle.ConstantTimeCompare(actual, key) != 1 {
+			return false, false
+		}
+		return true, h.Algorithm != HashArgon2id ||
+			params.Memory != h.Argon2.Memory ||
+			params.Iterations != h.Argon2.Iterations ||
+			params.Parallelism != h.Argon2.Parallelism ||
+			params.KeyLength != h.Argon2.KeyLength
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)); err != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return true, err != nil || h.Algorithm != HashBcrypt || cost != h.BcryptCost
+}
+
+func decodeArgon2(encoded string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, errors.New("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errors.New("unsupported argon2 version")
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
 }
@@ -1,10 +1,12 @@
 package utils_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/danisasmita/customer-search/pkg/utils"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestHashPassword(t *testing.T) {
@@ -26,3 +28,91 @@ func TestCheckPasswordHash(t *testing.T) {
 	// Test incorrect password
 	assert.False(t, utils.CheckPasswordHash("wrongpassword", hashedPassword))
 }
+
+func TestPasswordHasherArgon2id(t *testing.T) {
+	hasher, err := utils.NewPasswordHasher(utils.HashArgon2id, utils.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1}, 0)
+	assert.NoError(t, err)
+
+	hash, err := hasher.Hash("mysecurepassword")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	match, rehash := hasher.Verify("mysecurepassword", hash)
+	assert.True(t, match)
+	assert.False(t, rehash)
+
+	match, _ = hasher.Verify("wrongpassword", hash)
+	assert.False(t, match)
+
+	// Parameter dinaikkan: hash lama masih valid tetapi perlu dibuat ulang
+	stronger, err := utils.NewPasswordHasher(utils.HashArgon2id, utils.Argon2Params{Memory: 2048, Iterations: 1, Parallelism: 1}, 0)
+	assert.NoError(t, err)
+	match, rehash = stronger.Verify("mysecurepassword", hash)
+	assert.True(t, match)
+	assert.True(t, rehash)
+}
+
+func TestPasswordHasherBcryptUpgrade(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("securepassword"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	hasher, err := utils.NewPasswordHasher(utils.HashArgon2id, utils.DefaultArgon2Params, bcrypt.DefaultCost)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		password    string
+		hash        string
+		match       bool
+		needsRehash bool
+	}{
+		{name: "Legacy Bcrypt", password: "securepassword", hash: string(legacy), match: true, needsRehash: true},
+		{name: "Invalid Password", password: "wrongpassword", hash: string(legacy), match: false},
+		{name: "Empty Password", password: "", hash: string(legacy), match: false},
+		{name: "Malformed Hash", password: "securepassword", hash: "$argon2id$v=19$broken", match: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, needsRehash := hasher.Verify(tt.password, tt.hash)
+			assert.Equal(t, tt.match, match)
+			assert.Equal(t, tt.needsRehash, needsRehash)
+		})
+	}
+
+	bcryptHasher, err := utils.NewPasswordHasher(utils.HashBcrypt, utils.Argon2Params{}, bcrypt.MinCost)
+	assert.NoError(t, err)
+	match, needsRehash := bcryptHasher.Verify("securepassword", string(legacy))
+	assert.True(t, match)
+	assert.False(t, needsRehash)
+}
+
+func TestNewPasswordHasherInvalid(t *testing.T) {
+	_, err := utils.NewPasswordHasher("md5", utils.DefaultArgon2Params, bcrypt.DefaultCost)
+	assert.Error(t, err)
+
+	_, err = utils.NewPasswordHasher(utils.HashBcrypt, utils.DefaultArgon2Params, 99)
+	assert.Error(t, err)
+
+	_, err = utils.NewPasswordHasher(utils.HashArgon2id, utils.Argon2Params{}, 0)
+	assert.Error(t, err)
+}
+
+func TestDummyPasswordHash(t *testing.T) {
+	hasher, err := utils.NewPasswordHasher(utils.HashArgon2id, utils.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1}, 0)
+	assert.NoError(t, err)
+	utils.SetPasswordHasher(hasher)
+	defer utils.SetPasswordHasher(&utils.PasswordHasher{Algorithm: utils.HashArgon2id, Argon2: utils.DefaultArgon2Params, BcryptCost: bcrypt.DefaultCost})
+
+	// Hash tetap selama konfigurasi sama dan memakai parameter aktif
+	dummy := utils.DummyPasswordHash()
+	assert.True(t, strings.HasPrefix(dummy, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.Equal(t, dummy, utils.DummyPasswordHash())
+	match, _ := utils.VerifyPassword("anything", dummy)
+	assert.False(t, match)
+
+	bcryptHasher, err := utils.NewPasswordHasher(utils.HashBcrypt, utils.DefaultArgon2Params, bcrypt.MinCost)
+	assert.NoError(t, err)
+	utils.SetPasswordHasher(bcryptHasher)
+	assert.True(t, strings.HasPrefix(utils.DummyPasswordHash(), "$2a$04$"))
+}
@@ -0,0 +1,56 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danisasmita/customer-search/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Secret "12345678901234567890" dari vektor uji RFC 6238.
+const rfcTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	tests := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range tests {
+		code, err := utils.TOTPCode(rfcTOTPSecret, time.Unix(unix, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	step, ok := utils.ValidateTOTP(rfcTOTPSecret, "005924", now)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1234567890/30), step)
+
+	// Satu periode terlambat masih diterima
+	_, ok = utils.ValidateTOTP(rfcTOTPSecret, "005924", now.Add(30*time.Second))
+	assert.True(t, ok)
+
+	_, ok = utils.ValidateTOTP(rfcTOTPSecret, "005924", now.Add(2*time.Minute))
+	assert.False(t, ok)
+
+	_, ok = utils.ValidateTOTP(rfcTOTPSecret, "123", now)
+	assert.False(t, ok)
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := utils.GenerateTOTPSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	uri := utils.TOTPURI("Customer Search", "john", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Customer%20Search:john?"))
+	assert.Contains(t, uri, "secret="+secret)
+}
@@ -0,0 +1,123 @@
+// Package validation mendaftarkan validator domain ke validator bawaan gin
+// sehingga DTO cukup memakai tag binding, misalnya `binding:"required,role"`.
+// Pendaftaran berjalan saat package diimpor.
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/danisasmita/customer-search/internal/model"
+	"github.com/danisasmita/customer-search/pkg/i18n"
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Tag validator domain. Nilainya juga menjadi kode di daftar errors.
+const (
+	TagRole          = "role"
+	TagTenant        = "tenant"
+	TagLanguage      = "language"
+	TagScope         = "scope"
+	TagAccountNumber = "account_number"
+)
+
+// domainRule dengan values menyebut nilai yang diizinkan di pesan error.
+type domainRule struct {
+	valid  func(string) bool
+	values []string
+}
+
+var domainRules = map[string]domainRule{
+	TagRole:          {valid: model.ValidRole, values: model.Roles},
+	TagTenant:        {valid: model.ValidTenant},
+	TagLanguage:      {valid: i18n.Supported, values: i18n.Languages},
+	TagScope:         {valid: model.ValidScope, values: model.APIKeyScopes},
+	TagAccountNumber: {valid: model.ValidAccountNumber},
+}
+
+func init() {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		panic("validation: gin validator engine is not go-playground/validator")
+	}
+	if err := Register(engine); err != nil {
+		panic(err)
+	}
+}
+
+// Register memasang validator domain dan memakai nama field JSON atau
+// query di pesan error.
+func Register(engine *validator.Validate) error {
+	engine.RegisterTagNameFunc(fieldName)
+	for tag, rule := range domainRules {
+		valid := rule.valid
+		err := engine.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			return valid(fl.Field().String())
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// FieldError berisi satu pelanggaran aturan dengan pesan English. Pesan
+// diterjemahkan lagi saat respons ditulis.
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+	Params  i18n.Params
+}
+
+// Fields mengubah error validator menjadi daftar FieldError. ok bernilai
+// false jika err bukan error validasi, misalnya body JSON rusak.
+func Fields(err error) (fields []FieldError, ok bool) {
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return nil, false
+	}
+
+	for _, fe := range invalid {
+		code := fe.Tag()
+		if _, known := i18n.Lookup(i18n.English, code); !known {
+			code = message.CodeInvalidField
+		}
+		params := i18n.Params{"param": fe.Param()}
+		if values := domainRules[code].values; len(values) > 0 {
+			params["values"] = strings.Join(values, ", ")
+		}
+
+		fields = append(fields, FieldError{
+			Field:   fe.Field(),
+			Code:    code,
+			Message: i18n.T(i18n.English, code, withField(params, fe.Field())),
+			Params:  params,
+		})
+	}
+	return fields, true
+}
+
+func withField(params i18n.Params, field string) i18n.Params {
+	merged := i18n.Params{"field": field}
+	for name, value := range params {
+		merged[name] = value
+	}
+	return merged
+}
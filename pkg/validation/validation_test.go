@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/danisasmita/customer-search/pkg/message"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+type sampleRequest struct {
+	Username      string   `json:"username" binding:"required,max=5"`
+	Email         string   `form:"email" binding:"omitempty,email"`
+	Role          string   `json:"role" binding:"omitempty,role"`
+	Scopes        []string `json:"scopes" binding:"dive,scope"`
+	AccountNumber string   `json:"account_number" binding:"omitempty,account_number"`
+	Code          string   `json:"code" binding:"omitempty,numeric"`
+}
+
+func TestFields(t *testing.T) {
+	err := binding.Validator.ValidateStruct(sampleRequest{
+		Email:         "not-an-email",
+		Role:          "root",
+		Scopes:        []string{"customers:read", "everything"},
+		AccountNumber: "12-34",
+		Code:          "abc",
+	})
+
+	fields, ok := Fields(err)
+	assert.True(t, ok)
+	assert.Equal(t, []FieldError{
+		{Field: "username", Code: message.CodeRequired, Message: "username is required", Params: map[string]interface{}{"param": ""}},
+		{Field: "email", Code: "email", Message: "email must be a valid email address", Params: map[string]interface{}{"param": ""}},
+		{Field: "role", Code: TagRole, Message: "role must be one of: admin, agent", Params: map[string]interface{}{"param": "", "values": "admin, agent"}},
+		{Field: "scopes[1]", Code: TagScope, Message: "scopes[1] must be one of: customers:read, admin", Params: map[string]interface{}{"param": "", "values": "customers:read, admin"}},
+		{Field: "account_number", Code: TagAccountNumber, Message: "account_number must be 10 to 16 digits", Params: map[string]interface{}{"param": ""}},
+		{Field: "code", Code: message.CodeInvalidField, Message: "code is invalid", Params: map[string]interface{}{"param": ""}},
+	}, fields)
+}
+
+func TestFieldsLength(t *testing.T) {
+	fields, ok := Fields(binding.Validator.ValidateStruct(sampleRequest{Username: "too_long"}))
+	assert.True(t, ok)
+	if assert.Len(t, fields, 1) {
+		assert.Equal(t, "max", fields[0].Code)
+		assert.Equal(t, "username must have a length of at most 5", fields[0].Message)
+	}
+}
+
+func TestFieldsNotValidation(t *testing.T) {
+	_, ok := Fields(errors.New("unexpected EOF"))
+	assert.False(t, ok)
+}
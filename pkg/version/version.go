@@ -0,0 +1,47 @@
+// Package version menyimpan informasi build. Commit dan BuildTime diisi
+// saat build dengan -ldflags, misalnya:
+//
+//	go build -ldflags "-X github.com/danisasmita/customer-search/pkg/version.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/danisasmita/customer-search/pkg/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	Commit    = ""
+	BuildTime = ""
+)
+
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get memakai nilai dari -ldflags. Jika kosong, revisi dan waktu commit
+// yang dicatat go build dari git dipakai sebagai gantinya.
+func Get() Info {
+	info := Info{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
@@ -0,0 +1,27 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	commit, buildTime := Commit, BuildTime
+	t.Cleanup(func() { Commit, BuildTime = commit, buildTime })
+
+	Commit, BuildTime = "abc123", "2026-10-19T00:00:00Z"
+	assert.Equal(t, Info{Commit: "abc123", BuildTime: "2026-10-19T00:00:00Z", GoVersion: runtime.Version()}, Get())
+}
+
+func TestGetWithoutLdflags(t *testing.T) {
+	commit, buildTime := Commit, BuildTime
+	t.Cleanup(func() { Commit, BuildTime = commit, buildTime })
+
+	Commit, BuildTime = "", ""
+	info := Get()
+	assert.NotEmpty(t, info.Commit)
+	assert.NotEmpty(t, info.BuildTime)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+}